@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+type catOpts struct {
+	repositoryURL string
+}
+
+func newCatCommand() *cobra.Command {
+	var opts catOpts
+
+	cmd := &cobra.Command{
+		Use:   "cat <experiment or checkpoint ID> <path>",
+		Short: "Print a file of an experiment or checkpoint",
+		Long: `Print a file of an experiment or checkpoint, without checking it out.
+
+Like "keepsake checkout", the files saved with the checkpoint are on top of
+the files saved with its experiment. If an experiment ID is passed, its best
+checkpoint is used, or its latest checkpoint if it doesn't have a primary
+metric.
+
+To see which files can be printed, run "keepsake files".
+`,
+		Run: handleErrors(func(cmd *cobra.Command, args []string) error {
+			return catFile(opts, args[0], args[1], os.Stdout)
+		}),
+		Args: cobra.ExactArgs(2),
+		Example: `Print the config file that a checkpoint was trained with:
+$ keepsake cat 1cccccc config.yaml
+
+Compare it to the config file in your working directory:
+$ keepsake cat 1cccccc config.yaml | diff - config.yaml
+`,
+	}
+
+	addRepositoryURLFlagVar(cmd, &opts.repositoryURL)
+
+	return cmd
+}
+
+func catFile(opts catOpts, prefix string, path string, out io.Writer) error {
+	repositoryURL, projectDir, err := getRepositoryURLFromStringOrConfig(opts.repositoryURL)
+	if err != nil {
+		return err
+	}
+	proj, err := getProject(repositoryURL, projectDir)
+	if err != nil {
+		return err
+	}
+	exp, chk, err := getExperimentAndCheckpoint(prefix, proj, "Reading")
+	if err != nil {
+		return err
+	}
+	return proj.ReadCheckpointFile(exp, chk, path, out)
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"bytes"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/config"
+	"github.com/replicate/keepsake/golang/pkg/testutil"
+)
+
+func TestCatFile(t *testing.T) {
+	workingDir := t.TempDir()
+	repo := createShowTestData(t, workingDir, &config.Config{})
+	opts := catOpts{repositoryURL: "file://" + path.Join(workingDir, ".keepsake")}
+	testutil.PutTarFiles(t, repo, "checkpoints/2ccccccccc.tar.gz", "data", map[string]string{"config.yaml": "lr: 0.1\n"})
+	testutil.PutTarFiles(t, repo, "checkpoints/3ccccccccc.tar.gz", "data", map[string]string{"config.yaml": "lr: 0.01\n"})
+
+	out := new(bytes.Buffer)
+	require.NoError(t, catFile(opts, "3ccc", "data/config.yaml", out))
+	require.Equal(t, "lr: 0.01\n", out.String())
+
+	// an experiment has the files of its best checkpoint
+	out = new(bytes.Buffer)
+	require.NoError(t, catFile(opts, "1eee", "data/config.yaml", out))
+	require.Equal(t, "lr: 0.1\n", out.String())
+
+	err := catFile(opts, "2ccc", "data/missing.txt", new(bytes.Buffer))
+	require.EqualError(t, err, "Neither the checkpoint 2cccccc nor its experiment 1eeeeee have the file data/missing.txt")
+
+	err = catFile(opts, "2ccc", "data", new(bytes.Buffer))
+	require.EqualError(t, err, "data is a directory")
+}
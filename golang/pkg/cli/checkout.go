@@ -41,7 +41,9 @@ func newCheckoutCommand() *cobra.Command {
 }
 
 // Returns the experiment and the most appropriate checkpoint for that experiment.
-func getExperimentAndCheckpoint(prefix string, proj *project.Project, projectDir string) (*project.Experiment, *project.Checkpoint, error) {
+// action is what is being done with the files, e.g. "Checking out", which is
+// logged with the experiment and checkpoint that were chosen.
+func getExperimentAndCheckpoint(prefix string, proj *project.Project, action string) (*project.Experiment, *project.Checkpoint, error) {
 	result, err := proj.CheckpointOrExperimentFromPrefix(prefix)
 	if err != nil {
 		return nil, nil, err
@@ -50,24 +52,24 @@ func getExperimentAndCheckpoint(prefix string, proj *project.Project, projectDir
 	checkpoint := result.Checkpoint
 
 	if checkpoint != nil {
-		console.Info("Checking out files from checkpoint %s and its experiment %s", checkpoint.ShortID(), experiment.ShortID())
+		console.Info("%s files from checkpoint %s and its experiment %s", action, checkpoint.ShortID(), experiment.ShortID())
 		return experiment, checkpoint, nil
 	}
 
 	// When checking out experiment, also check out best/latest checkpoint
 	checkpoint = experiment.BestCheckpoint()
 	if checkpoint != nil {
-		console.Info("Checking out files from experiment %s and its best checkpoint %s", experiment.ShortID(), checkpoint.ShortID())
+		console.Info("%s files from experiment %s and its best checkpoint %s", action, experiment.ShortID(), checkpoint.ShortID())
 		return experiment, checkpoint, nil
 	}
 
 	checkpoint = experiment.LatestCheckpoint()
 	if checkpoint != nil {
-		console.Info("Checking out files from experiment %s and its latest checkpoint %s", experiment.ShortID(), checkpoint.ShortID())
+		console.Info("%s files from experiment %s and its latest checkpoint %s", action, experiment.ShortID(), checkpoint.ShortID())
 		return experiment, checkpoint, nil
 	}
 
-	console.Info("Checking out files from experiment %s", experiment.ShortID())
+	console.Info("%s files from experiment %s", action, experiment.ShortID())
 	return experiment, checkpoint, nil
 }
 
@@ -160,13 +162,11 @@ func checkoutCheckpoint(opts checkoutOpts, args []string) error {
 	if err != nil {
 		return err
 	}
-	repo, err := getRepository(repositoryURL, projectDir)
+	proj, err := getProject(repositoryURL, projectDir)
 	if err != nil {
 		return err
 	}
-
-	proj := project.NewProject(repo, projectDir)
-	experiment, checkpoint, err := getExperimentAndCheckpoint(prefix, proj, projectDir)
+	experiment, checkpoint, err := getExperimentAndCheckpoint(prefix, proj, "Checking out")
 	if err != nil {
 		return err
 	}
@@ -203,6 +203,6 @@ func checkoutCheckpoint(opts checkoutOpts, args []string) error {
 	if checkoutPath == "" {
 		return proj.CheckoutCheckpoint(checkpoint, experiment, outputDir, false)
 	} else {
-		return proj.CheckoutFileOrDirectory(checkpoint, experiment, outputDir, checkoutPath)
+		return proj.CheckoutFileOrDirectory(checkpoint, experiment, outputDir, checkoutPath, false)
 	}
 }
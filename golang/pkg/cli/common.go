@@ -11,6 +11,7 @@ import (
 	"github.com/replicate/keepsake/golang/pkg/config"
 	"github.com/replicate/keepsake/golang/pkg/console"
 	"github.com/replicate/keepsake/golang/pkg/global"
+	"github.com/replicate/keepsake/golang/pkg/project"
 	"github.com/replicate/keepsake/golang/pkg/repository"
 )
 
@@ -35,26 +36,32 @@ func addRepositoryURLFlagVar(cmd *cobra.Command, opt *string) {
 // * Otherwise, the current working directory is used
 // Returns (repositoryURL, projectDir, error)
 func getRepositoryURLFromStringOrConfig(repositoryURL string) (string, string, error) {
-	projectDir := global.ProjectDirectory
+	return getRepositoryURLFromStringOrConfigInDir(repositoryURL, global.ProjectDirectory)
+}
+
+// getRepositoryURLFromStringOrConfigInDir is like getRepositoryURLFromStringOrConfig,
+// but uses overrideDir in place of the directory passed with -D
+func getRepositoryURLFromStringOrConfigInDir(repositoryURL string, overrideDir string) (string, string, error) {
+	projectDir := overrideDir
 	if repositoryURL == "" {
-		conf, confProjectDir, err := config.FindConfigInWorkingDir(global.ProjectDirectory)
+		conf, confProjectDir, err := config.FindConfigInWorkingDir(overrideDir)
 		if err != nil {
 			return "", "", err
 		}
 		if repositoryURL == "" {
 			repositoryURL = conf.Repository
 		}
-		if global.ProjectDirectory == "" {
+		if overrideDir == "" {
 			projectDir = confProjectDir
 		} else {
-			projectDir = global.ProjectDirectory
+			projectDir = overrideDir
 		}
 	}
 
 	// abs of "" is cwd
 	projectDir, err := filepath.Abs(projectDir)
 	if err != nil {
-		return "", "", fmt.Errorf("Failed to determine absolute directory of '%s': %w", global.ProjectDirectory, err)
+		return "", "", fmt.Errorf("Failed to determine absolute directory of '%s': %w", overrideDir, err)
 	}
 
 	return repositoryURL, projectDir, nil
@@ -100,7 +107,9 @@ func getRepository(repositoryURL, projectDir string) (repository.Repository, err
 		if err != nil {
 			return nil, err
 		}
-		cachedRepo := repo.(*repository.CachedRepository)
+	}
+	repo = repository.WithContext(commandContext, repo)
+	if cachedRepo, ok := repo.(*repository.CachedRepository); ok {
 		if err := cachedRepo.SyncCache(); err != nil {
 			return nil, err
 		}
@@ -108,6 +117,16 @@ func getRepository(repositoryURL, projectDir string) (repository.Repository, err
 	return repo, nil
 }
 
+// getProject returns the project in projectDir, stored in repositoryURL,
+// traced as part of the command that is running
+func getProject(repositoryURL, projectDir string) (*project.Project, error) {
+	repo, err := getRepository(repositoryURL, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return project.NewProject(repo, projectDir).WithContext(commandContext), nil
+}
+
 // handlErrors wraps a cobra function, and will print and exit on error
 //
 // We don't use RunE because if that returns an error, Cobra will print usage.
@@ -115,7 +134,9 @@ func getRepository(repositoryURL, projectDir string) (repository.Repository, err
 func handleErrors(f func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) {
 	return func(cmd *cobra.Command, args []string) {
 		if err := f(cmd, args); err != nil {
-			console.Fatal(err.Error())
+			// console.Fatal exits, so spans have to be exported first
+			finishTracing(err)
+			console.Fatal("%s", err)
 		}
 	}
 }
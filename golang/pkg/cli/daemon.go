@@ -1,34 +1,119 @@
 package cli
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"github.com/replicate/keepsake/golang/pkg/console"
 	"github.com/replicate/keepsake/golang/pkg/global"
+	"github.com/replicate/keepsake/golang/pkg/listenaddress"
 	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/settings"
 	"github.com/replicate/keepsake/golang/pkg/shared"
 )
 
+const defaultDaemonTokenSecret = "daemon-token"
+
+type daemonOpts struct {
+	listen          string
+	httpListen      string
+	metricsListen   string
+	tlsCertFile     string
+	tlsKeyFile      string
+	authTokenSecret string
+	insecureNoAuth  bool
+}
+
 func NewDaemonCommand() *cobra.Command {
+	var opts daemonOpts
+
 	cmd := &cobra.Command{
-		Use:  "keepsake-daemon <socket-path>",
-		RunE: runDaemon,
+		Use:   "keepsake-daemon [<socket-path>]",
+		Short: "Serve the Keepsake API over gRPC",
+		Long: `Serve the Keepsake API over gRPC.
+
+By default the daemon listens on the UNIX socket passed as an argument. To
+share one daemon between several processes, for example containers on the
+same node, pass --listen tcp://<host>:<port> instead.
+
+When listening on TCP, clients must authenticate with the token stored in the
+Keepsake secret named by --auth-token-secret (in ~/.config/keepsake/secrets/).
+If that secret doesn't exist, the daemon refuses to listen on TCP, unless
+--insecure-no-auth is passed to let anyone who can connect to it read and
+write the repository.
+
+Pass --http-listen to also serve the API as JSON over HTTP. Every method is
+available as POST /v1/<method>, for example /v1/list_experiments, and the
+OpenAPI description is served at /v1/openapi.json. Run
+"keepsake-daemon openapi" to print it.
+
+Pass --metrics-listen to serve Prometheus metrics at /metrics. Metrics cover
+repository operations, labelled by scheme and operation, the queue of uploads,
+and the time taken by every request. The metrics endpoint isn't authenticated.
+
+Set KEEPSAKE_TRACE, or the tracing option in keepsake.yaml, to record
+OpenTelemetry traces of requests, either "otlp" or "file:<path>". Clients
+can continue their own traces by passing a W3C traceparent in the request
+metadata, or in the HTTP headers of gateway requests.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemon(cmd, opts, args)
+		},
+		Args: cobra.MaximumNArgs(1),
 	}
 	setPersistentFlags(cmd)
 	handleEnvironmentVariables()
 	addRepositoryURLFlag(cmd)
+	cmd.Flags().StringVar(&opts.listen, "listen", "", "Address to listen on, either unix://<path> or tcp://<host>:<port>")
+	cmd.Flags().StringVar(&opts.httpListen, "http-listen", "", "Address to serve the JSON over HTTP API on, either unix://<path> or tcp://<host>:<port>")
+	cmd.Flags().StringVar(&opts.metricsListen, "metrics-listen", "", "Address to serve Prometheus metrics on at /metrics, either unix://<path> or tcp://<host>:<port>")
+	cmd.Flags().StringVar(&opts.tlsCertFile, "tls-cert", "", "Path to a TLS certificate. If set together with --tls-key, connections are served over TLS")
+	cmd.Flags().StringVar(&opts.tlsKeyFile, "tls-key", "", "Path to the TLS certificate's private key")
+	cmd.Flags().StringVar(&opts.authTokenSecret, "auth-token-secret", defaultDaemonTokenSecret, "Name of the secret that holds the bearer token clients must pass when connecting over TCP")
+	cmd.Flags().BoolVar(&opts.insecureNoAuth, "insecure-no-auth", false, "Allow listening on TCP without a token, so anyone who can connect to the daemon can read and write the repository")
+	cmd.AddCommand(newDaemonOpenAPICommand())
 	return cmd
 }
 
-func runDaemon(cmd *cobra.Command, args []string) error {
-	socketPath := args[0]
+func newDaemonOpenAPICommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "openapi",
+		Short: "Print the OpenAPI description of the JSON over HTTP API",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spec, err := shared.OpenAPI()
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(spec)
+			return err
+		},
+		Args: cobra.NoArgs,
+	}
+}
 
+func runDaemon(cmd *cobra.Command, opts daemonOpts, args []string) error {
 	if global.Verbose {
 		console.SetLevel(console.DebugLevel)
 	}
 
-	projectGetter := func() (proj *project.Project, err error) {
-		repositoryURL, projectDir, err := getRepositoryURLFromFlagOrConfig(cmd)
+	serveOpts, err := getServeOptions(opts, args)
+	if err != nil {
+		return err
+	}
+
+	setupTracing("keepsake-daemon")
+	defer finishTracing(nil)
+
+	projectGetter := func(repositoryURL string, projectDir string) (proj *project.Project, err error) {
+		// requests that don't identify a project use the project the
+		// daemon was started with
+		if repositoryURL == "" && projectDir == "" {
+			repositoryURL, projectDir, err = getRepositoryURLFromFlagOrConfig(cmd)
+		} else {
+			repositoryURL, projectDir, err = getRepositoryURLFromStringOrConfigInDir(repositoryURL, projectDir)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -40,8 +125,53 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		return proj, err
 	}
 
-	if err := shared.Serve(projectGetter, socketPath); err != nil {
+	if err := shared.Serve(projectGetter, serveOpts); err != nil {
 		return err
 	}
 	return nil
 }
+
+func getServeOptions(opts daemonOpts, args []string) (shared.ServeOptions, error) {
+	serveOpts := shared.ServeOptions{
+		Listen:         opts.listen,
+		TLSCertFile:    opts.tlsCertFile,
+		TLSKeyFile:     opts.tlsKeyFile,
+		HTTPListen:     opts.httpListen,
+		MetricsListen:  opts.metricsListen,
+		InsecureNoAuth: opts.insecureNoAuth,
+	}
+	if len(args) > 0 {
+		if opts.listen != "" {
+			return serveOpts, fmt.Errorf("Cannot pass both a socket path and --listen")
+		}
+		serveOpts.Listen = args[0]
+	}
+	if serveOpts.Listen == "" {
+		return serveOpts, fmt.Errorf("You must pass either a socket path or --listen")
+	}
+
+	onNetwork := false
+	for _, address := range []string{serveOpts.Listen, serveOpts.HTTPListen} {
+		if address == "" {
+			continue
+		}
+		network, _, err := listenaddress.Parse(address)
+		if err != nil {
+			return serveOpts, err
+		}
+		onNetwork = onNetwork || network == "tcp"
+	}
+	// UNIX sockets are protected by file permissions, so the token is only
+	// required when listening on the network
+	if onNetwork && opts.authTokenSecret != "" {
+		token, err := settings.GetSecret(opts.authTokenSecret)
+		if err != nil {
+			return serveOpts, fmt.Errorf("Failed to read secret %s: %w", opts.authTokenSecret, err)
+		}
+		serveOpts.AuthToken = strings.TrimSpace(string(token))
+	}
+	if onNetwork && serveOpts.AuthToken == "" && !opts.insecureNoAuth {
+		return serveOpts, fmt.Errorf("The daemon needs a token to listen on TCP, otherwise anyone who can connect to it could read and write your repository. Save a token in the Keepsake secret %q, or pick another secret with --auth-token-secret, or pass --insecure-no-auth to listen without one", opts.authTokenSecret)
+	}
+	return serveOpts, nil
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetServeOptionsRequiresTokenOnTCP(t *testing.T) {
+	// a secret that doesn't exist, so there is no token
+	opts := daemonOpts{listen: "tcp://127.0.0.1:0", authTokenSecret: "keepsake-test-missing-daemon-token"}
+	_, err := getServeOptions(opts, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--insecure-no-auth")
+
+	opts.listen = ""
+	opts.httpListen = "tcp://127.0.0.1:0"
+	_, err = getServeOptions(opts, []string{"/tmp/keepsake.sock"})
+	require.Error(t, err)
+
+	opts.insecureNoAuth = true
+	serveOpts, err := getServeOptions(opts, []string{"/tmp/keepsake.sock"})
+	require.NoError(t, err)
+	require.Equal(t, "", serveOpts.AuthToken)
+	require.True(t, serveOpts.InsecureNoAuth)
+
+	// UNIX sockets don't need a token
+	serveOpts, err = getServeOptions(daemonOpts{authTokenSecret: "keepsake-test-missing-daemon-token"}, []string{"/tmp/keepsake.sock"})
+	require.NoError(t, err)
+	require.Equal(t, "/tmp/keepsake.sock", serveOpts.Listen)
+}
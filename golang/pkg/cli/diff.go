@@ -1,173 +1,426 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
-	"sort"
-	"strconv"
+	"strings"
 	"text/tabwriter"
-	"time"
 
 	"github.com/logrusorgru/aurora"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/spf13/cobra"
 
 	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/output"
 	"github.com/replicate/keepsake/golang/pkg/param"
 	"github.com/replicate/keepsake/golang/pkg/project"
 )
 
 func newDiffCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "diff <ID> <ID>",
-		Short: "Compare two experiments or checkpoints",
-		Long: `Compare two experiments or checkpoints.
+		Use:   "diff <ID> <ID> [<ID>...]",
+		Short: "Compare experiments or checkpoints",
+		Long: `Compare two or more experiments or checkpoints side by side.
+
+Only the params, metrics, Python packages and other values that differ are shown. Params and metrics that are objects are compared value by value, with paths like "optimizer.lr". Values of different types, like 1 and "1", are different.
 
 If an experiment ID is passed, it will pick the best checkpoint from that experiment. If a primary metric is not defined in keepsake.yaml, it will use the latest checkpoint.`,
 		Run:  handleErrors(diffCheckpoints),
-		Args: cobra.ExactArgs(2),
+		Args: cobra.MinimumNArgs(2),
+		Example: `Compare the best checkpoints of two experiments:
+$ keepsake diff 1eeeeee 2eeeeee
+
+Compare the best checkpoints of three experiments:
+$ keepsake diff 1eeeeee 2eeeeee 3eeeeee
+
+Write the differences as a Markdown table to paste into a pull request:
+$ keepsake diff 1eeeeee 2eeeeee --format markdown
+
+List the files that were added, removed or modified between two
+checkpoints, with a diff of each small text file that was modified:
+$ keepsake diff --files 1cccccc 2cccccc
+`,
 	}
 
-	// We should have a --json flag here, see https://github.com/replicate/keepsake/issues/338
+	cmd.Flags().Bool("files", false, "Compare the files of two checkpoints, including the files of their experiments, instead of their metadata")
+	cmd.Flags().Bool("json", false, "Print output in JSON format")
+	cmd.Flags().String("format", "", "Output format: "+output.FormatNames+", with a row for each difference and columns for the section, the key and a value for each checkpoint")
 	addRepositoryURLFlag(cmd)
 
 	return cmd
 }
 
 func diffCheckpoints(cmd *cobra.Command, args []string) error {
-	// We should allow >2 experiments/checkpoints, see https://github.com/replicate/keepsake/issues/339
-	// TODO(bfirsh): it probably makes sense to refactor this to diff param.Values instead of strings at some point.
-	// that way we can do interesting stuff like diff JSON structures, using param.Value comparison methods, ShortString, etc.
-
-	prefix1 := args[0]
-	prefix2 := args[1]
-
 	repositoryURL, projectDir, err := getRepositoryURLFromFlagOrConfig(cmd)
 	if err != nil {
 		return err
 	}
-	repo, err := getRepository(repositoryURL, projectDir)
+	json, err := cmd.Flags().GetBool("json")
 	if err != nil {
 		return err
 	}
-	proj := project.NewProject(repo, projectDir)
+	formatString, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	if json && formatString != "" {
+		return fmt.Errorf("Cannot use the --format flag in combination with --json")
+	}
+	var format output.Format
+	if formatString != "" {
+		var ok bool
+		format, ok = output.ParseFormat(formatString)
+		if !ok {
+			return fmt.Errorf("Unknown format %q, it must be %s", formatString, output.FormatNames)
+		}
+	}
+	diffFiles, err := cmd.Flags().GetBool("files")
+	if err != nil {
+		return err
+	}
+	if diffFiles && len(args) != 2 {
+		return fmt.Errorf("The --files flag can only compare two experiments or checkpoints")
+	}
+	proj, err := getProject(repositoryURL, projectDir)
+	if err != nil {
+		return err
+	}
+	if diffFiles {
+		differences, err := loadFileDifferences(proj, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		switch {
+		case json:
+			return printFileDiffJSON(os.Stdout, differences)
+		case formatString != "":
+			return writeFileDiff(os.Stdout, differences, format)
+		}
+		return printFileDiff(os.Stdout, getAurora(), differences)
+	}
+	switch {
+	case json:
+		return printDiffJSON(os.Stdout, proj, args...)
+	case formatString != "":
+		return writeDiff(os.Stdout, proj, format, args...)
+	}
 	au := getAurora()
-	return printDiff(os.Stdout, au, proj, prefix1, prefix2)
+	return printDiff(os.Stdout, au, proj, args...)
 }
 
 // TODO: implement this as a thing in console
-func br(w *tabwriter.Writer) {
-	fmt.Fprintf(w, "\t\t\n")
+func br(w *tabwriter.Writer, numColumns int) {
+	fmt.Fprintf(w, "%s\n", strings.Repeat("\t", numColumns))
 }
 
-func heading(w *tabwriter.Writer, au aurora.Aurora, text string) {
-	fmt.Fprintf(w, "%s\t\t\n", au.Bold(text))
+func heading(w *tabwriter.Writer, au aurora.Aurora, text string, numColumns int) {
+	fmt.Fprintf(w, "%s%s\n", au.Bold(text), strings.Repeat("\t", numColumns))
 }
 
-func printDiff(out io.Writer, au aurora.Aurora, proj *project.Project, prefix1 string, prefix2 string) error {
-	exp1, com1, err := loadCheckpoint(proj, prefix1)
-	if err != nil {
-		return err
+// loadDiffTargets returns the checkpoints to compare for each prefix, as
+// picked by loadCheckpoint
+func loadDiffTargets(proj *project.Project, prefixes []string) ([]*project.DiffTarget, error) {
+	targets := make([]*project.DiffTarget, len(prefixes))
+	for i, prefix := range prefixes {
+		exp, chk, err := loadCheckpoint(proj, prefix)
+		if err != nil {
+			return nil, err
+		}
+		targets[i] = &project.DiffTarget{Experiment: exp, Checkpoint: chk}
+	}
+	return targets, nil
+}
+
+// sameExperiment returns whether all the targets are in the same experiment
+func sameExperiment(targets []*project.DiffTarget) bool {
+	for _, target := range targets[1:] {
+		if target.Experiment.ID != targets[0].Experiment.ID {
+			return false
+		}
 	}
-	exp2, com2, err := loadCheckpoint(proj, prefix2)
+	return true
+}
+
+func printDiff(out io.Writer, au aurora.Aurora, proj *project.Project, prefixes ...string) error {
+	targets, err := loadDiffTargets(proj, prefixes)
 	if err != nil {
 		return err
 	}
 
-	// min width for 3 columns in 78 char terminal
-	w := tabwriter.NewWriter(out, 78/3, 8, 2, ' ', 0)
+	// min width for a key and a column for each checkpoint in 78 char terminal
+	w := tabwriter.NewWriter(out, 78/(len(targets)+1), 8, 2, ' ', 0)
 
-	heading(w, au, "Experiment")
-	fmt.Fprintf(w, "ID:\t%s\t%s\n", exp1.ShortID(), exp2.ShortID())
-	// HACK: don't show "no differences" if it's the same experiment, but still show ID because that's useful
-	if exp1.ID != exp2.ID {
-		printMapDiff(w, au, experimentToMap(exp1), experimentToMap(exp2))
+	for _, section := range project.DiffMany(targets, timezone) {
+		heading(w, au, section.Name, len(targets))
+		switch section.Name {
+		case "Experiment":
+			ids := []string{}
+			for _, target := range targets {
+				ids = append(ids, target.Experiment.ShortID())
+			}
+			fmt.Fprintf(w, "ID:\t%s\n", strings.Join(ids, "\t"))
+			// HACK: don't show "no differences" if it's the same experiment, but still show ID because that's useful
+			if !sameExperiment(targets) {
+				printDifferences(w, au, section.Differences, len(targets))
+			}
+		case "Checkpoint":
+			ids := []string{}
+			for _, target := range targets {
+				ids = append(ids, target.Checkpoint.ShortID())
+			}
+			fmt.Fprintf(w, "ID:\t%s\n", strings.Join(ids, "\t"))
+			printDifferences(w, au, section.Differences, len(targets))
+		default:
+			// TODO(bfirsh): put primary metric first
+			printDifferences(w, au, section.Differences, len(targets))
+		}
+		br(w, len(targets))
 	}
 
-	br(w)
-
-	heading(w, au, "Params")
-	printMapDiff(w, au, paramMapToStringMap(exp1.Params), paramMapToStringMap(exp2.Params))
-	br(w)
-
-	heading(w, au, "Python Packages")
-	printMapDiff(w, au, exp1.PythonPackages, exp2.PythonPackages)
-	br(w)
+	return w.Flush()
+}
 
-	heading(w, au, "Checkpoint")
-	fmt.Fprintf(w, "ID:\t%s\t%s\n", com1.ShortID(), com2.ShortID())
-	printMapDiff(w, au, checkpointToMap(com1), checkpointToMap(com2))
-	br(w)
+// diffJSON is the output of `keepsake diff --json`
+type diffJSON struct {
+	Checkpoints []*diffCheckpointJSON       `json:"checkpoints"`
+	Sections    []*project.MultiDiffSection `json:"sections"`
+}
 
-	heading(w, au, "Metrics")
-	// TODO(bfirsh): put primary metric first
-	printMapDiff(w, au, paramMapToStringMap(com1.Metrics), paramMapToStringMap(com2.Metrics))
-	br(w)
+type diffCheckpointJSON struct {
+	ExperimentID string `json:"experiment_id"`
+	CheckpointID string `json:"checkpoint_id"`
+}
 
-	return w.Flush()
+// printDiffJSON prints the differences as JSON. Each difference has a value
+// for each checkpoint, which is null if it isn't set for that checkpoint.
+func printDiffJSON(out io.Writer, proj *project.Project, prefixes ...string) error {
+	targets, err := loadDiffTargets(proj, prefixes)
+	if err != nil {
+		return err
+	}
+	result := &diffJSON{
+		Checkpoints: []*diffCheckpointJSON{},
+		Sections:    project.DiffMany(targets, timezone),
+	}
+	for _, target := range targets {
+		result.Checkpoints = append(result.Checkpoints, &diffCheckpointJSON{
+			ExperimentID: target.Experiment.ID,
+			CheckpointID: target.Checkpoint.ID,
+		})
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
 }
 
-func printMapDiff(w *tabwriter.Writer, au aurora.Aurora, map1, map2 map[string]string) {
-	diffMap := mapString(map1, map2)
+// writeDiff writes the differences between checkpoints in format, with a
+// row for each difference and a column for each checkpoint, headed by its
+// short ID. Like printDiff, the IDs of the experiments and checkpoints are
+// always included, and values that aren't set for a checkpoint are None.
+func writeDiff(out io.Writer, proj *project.Project, format output.Format, prefixes ...string) error {
+	targets, err := loadDiffTargets(proj, prefixes)
+	if err != nil {
+		return err
+	}
 
-	// sort the keys
-	type keyVal struct {
-		key   string
-		value []*string
+	columns := []string{"section", "key"}
+	seen := map[string]int{}
+	for _, target := range targets {
+		name := target.Checkpoint.ShortID()
+		seen[name]++
+		if seen[name] > 1 {
+			name = fmt.Sprintf("%s (%d)", name, seen[name])
+		}
+		columns = append(columns, name)
 	}
-	keyVals := []keyVal{}
-	for k, v := range diffMap {
-		keyVals = append(keyVals, keyVal{k, v})
+	table := output.NewTable(columns...)
+	addRow := func(section string, key string, values []param.Value) {
+		table.AddRow(append([]param.Value{param.String(section), param.String(key)}, values...)...)
 	}
-	sort.Slice(keyVals, func(i, j int) bool {
-		return keyVals[i].key < keyVals[j].key
-	})
 
-	if len(keyVals) > 0 {
-		for _, kv := range keyVals {
-			left := "(not set)"
-			right := "(not set)"
-			if kv.value[0] != nil {
-				left = *(kv.value[0])
+	for _, section := range project.DiffMany(targets, timezone) {
+		switch section.Name {
+		case "Experiment":
+			ids := []param.Value{}
+			for _, target := range targets {
+				ids = append(ids, param.String(target.Experiment.ID))
+			}
+			addRow(section.Name, "ID", ids)
+			if sameExperiment(targets) {
+				continue
+			}
+		case "Checkpoint":
+			ids := []param.Value{}
+			for _, target := range targets {
+				ids = append(ids, param.String(target.Checkpoint.ID))
 			}
-			if kv.value[1] != nil {
-				right = *(kv.value[1])
+			addRow(section.Name, "ID", ids)
+		}
+		for _, d := range section.Differences {
+			values := []param.Value{}
+			for _, v := range d.Values {
+				if v == nil {
+					values = append(values, param.None())
+				} else {
+					values = append(values, *v)
+				}
+			}
+			addRow(section.Name, d.Key, values)
+		}
+	}
+	return table.Write(out, format)
+}
+
+func printDifferences(w *tabwriter.Writer, au aurora.Aurora, differences []*project.MultiDifference, numValues int) {
+	if len(differences) == 0 {
+		fmt.Fprintf(w, "%s%s\n", au.Faint("(no difference)"), strings.Repeat("\t", numValues))
+		return
+	}
+	for _, d := range differences {
+		values := []string{}
+		for _, v := range d.Values {
+			s := "(not set)"
+			if v != nil {
+				s = v.String()
 			}
 			// Truncate to 50, which seems ball-park sensible figure to make this fit in a wide terminal
 			// At some point when we have a clever responsive tabwriter, we can adjust this based on terminal width!
-			fmt.Fprintf(w, "%s:\t%s\t%s\n", kv.key, param.Truncate(left, 50), param.Truncate(right, 50))
+			values = append(values, param.Truncate(s, 50))
 		}
-	} else {
-		fmt.Fprintf(w, "%s\t\t\n", au.Faint("(no difference)"))
+		fmt.Fprintf(w, "%s:\t%s\n", d.Key, strings.Join(values, "\t"))
+	}
+}
+
+// fileDifferences are the files that differ between two checkpoints
+type fileDifferences struct {
+	left        *project.DiffTarget
+	right       *project.DiffTarget
+	differences []*project.FileDifference
+}
+
+func loadFileDifferences(proj *project.Project, prefix1 string, prefix2 string) (*fileDifferences, error) {
+	targets, err := loadDiffTargets(proj, []string{prefix1, prefix2})
+	if err != nil {
+		return nil, err
+	}
+	left, right := targets[0], targets[1]
+	differences, err := proj.DiffFiles(left.Experiment, left.Checkpoint, right.Experiment, right.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &fileDifferences{left: left, right: right, differences: differences}, nil
+}
+
+// unified returns a unified diff of a modified text file, with the short
+// IDs of the checkpoints in the file names
+func (diff *fileDifferences) unified(d *project.FileDifference) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(d.Left),
+		B:        splitLines(d.Right),
+		FromFile: diff.left.Checkpoint.ShortID() + "/" + d.Path,
+		ToFile:   diff.right.Checkpoint.ShortID() + "/" + d.Path,
+		Context:  3,
+	})
+}
+
+// splitLines splits a file into lines, each ending in a newline, which
+// difflib.SplitLines doesn't do if the file ends in a newline
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return []string{}
 	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
 }
 
-// Returns a map of checkpoint things we want to show in diff
-func checkpointToMap(checkpoint *project.Checkpoint) map[string]string {
-	return map[string]string{
-		"Step":    strconv.FormatInt(checkpoint.Step, 10),
-		"Created": checkpoint.Created.In(timezone).Format(time.RFC1123),
-		"Path":    checkpoint.Path,
+// printFileDiff prints the files that were added, removed or modified, then
+// a unified diff of each modified text file
+func printFileDiff(out io.Writer, au aurora.Aurora, diff *fileDifferences) error {
+	fmt.Fprintf(out, "%s\n", au.Bold("Files"))
+	if len(diff.differences) == 0 {
+		fmt.Fprintf(out, "%s\n", au.Faint("(no difference)"))
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	for _, d := range diff.differences {
+		change := au.Yellow(d.Change)
+		switch d.Change {
+		case project.FileAdded:
+			change = au.Green(d.Change)
+		case project.FileRemoved:
+			change = au.Red(d.Change)
+		}
+		fmt.Fprintf(w, "%s:\t%s\n", change, d.Path)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, d := range diff.differences {
+		if !d.IsText() {
+			continue
+		}
+		text, err := diff.unified(d)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out)
+		for _, line := range strings.SplitAfter(text, "\n") {
+			switch {
+			case strings.HasPrefix(line, "---") || strings.HasPrefix(line, "+++"):
+				fmt.Fprint(out, au.Bold(line))
+			case strings.HasPrefix(line, "@@"):
+				fmt.Fprint(out, au.Cyan(line))
+			case strings.HasPrefix(line, "+"):
+				fmt.Fprint(out, au.Green(line))
+			case strings.HasPrefix(line, "-"):
+				fmt.Fprint(out, au.Red(line))
+			default:
+				fmt.Fprint(out, line)
+			}
+		}
 	}
+	return nil
 }
 
-// Returns a map of checkpoint things we want to show in diff
-func experimentToMap(exp *project.Experiment) map[string]string {
-	return map[string]string{
-		"Created":        exp.Created.In(timezone).Format(time.RFC1123),
-		"Host":           exp.Host,
-		"User":           exp.User,
-		"Command":        exp.Command,
-		"Python version": exp.PythonVersion,
+// printFileDiffJSON prints the files that differ as JSON, with a unified
+// diff for each modified text file
+func printFileDiffJSON(out io.Writer, diff *fileDifferences) error {
+	type fileDifferenceJSON struct {
+		*project.FileDifference
+		Diff *string `json:"diff"`
+	}
+	result := []*fileDifferenceJSON{}
+	for _, d := range diff.differences {
+		f := &fileDifferenceJSON{FileDifference: d}
+		if d.IsText() {
+			text, err := diff.unified(d)
+			if err != nil {
+				return err
+			}
+			f.Diff = &text
+		}
+		result = append(result, f)
 	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
 }
 
-func paramMapToStringMap(params param.ValueMap) map[string]string {
-	result := make(map[string]string)
-	for k, v := range params {
-		result[k] = v.String()
+// writeFileDiff writes the files that differ in format, with a row for each
+// file
+func writeFileDiff(out io.Writer, diff *fileDifferences, format output.Format) error {
+	table := output.NewTable("path", "change")
+	for _, d := range diff.differences {
+		table.AddRow(param.String(d.Path), param.String(string(d.Change)))
 	}
-	return result
+	return table.Write(out, format)
 }
 
 // loadCheckpoint returns a checkpoint given a prefix. If the prefix matches a
@@ -200,41 +453,3 @@ func loadCheckpoint(proj *project.Project, prefix string) (*project.Experiment,
 	}
 	return exp, checkpoint, nil
 }
-
-// mapString takes two maps of strings and returns a single map with two values
-// where the values are different. If only one map has a key, then the map
-// without the value will be marked as nil
-//
-// e.g.
-// >>> mapString({"layers": "2", "foo": "bar"}, {"layers": "4"})
-// {
-//    "foo": ["bar", nil],
-//	  "layers": ["2", "4"]
-// }
-//
-func mapString(left, right map[string]string) map[string][]*string {
-	result := make(map[string][]*string)
-	for k, v := range left {
-		if _, ok := right[k]; ok {
-			// Key in both left and right
-			if v != right[k] {
-				// copy so pointers are unique
-				v2 := v
-				s := right[k]
-				result[k] = []*string{&v2, &s}
-			}
-		} else {
-			// Key just in left
-			v2 := v
-			result[k] = []*string{&v2, nil}
-		}
-	}
-	for k, v := range right {
-		// Key just in right
-		if _, ok := left[k]; !ok {
-			v2 := v
-			result[k] = []*string{nil, &v2}
-		}
-	}
-	return result
-}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"testing"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/replicate/keepsake/golang/pkg/config"
+	"github.com/replicate/keepsake/golang/pkg/output"
 	"github.com/replicate/keepsake/golang/pkg/project"
 	"github.com/replicate/keepsake/golang/pkg/testutil"
 )
@@ -102,46 +104,158 @@ metric-3:                 (not set)                      0.5
 	require.Equal(t, expected, actual)
 }
 
-func TestMapString(t *testing.T) {
-	// string pointer helpers
-	baz := "baz"
-	bop := "bop"
-
-	// same
-	require.Equal(t, map[string][]*string{}, mapString(map[string]string{
-		"same": "in both",
-	}, map[string]string{
-		"same": "in both",
-	}))
-
-	// just in left
-	require.Equal(t, map[string][]*string{
-		"left": {&baz, nil},
-	}, mapString(map[string]string{
-		"same": "in both",
-		"left": "baz",
-	}, map[string]string{
-		"same": "in both",
-	}))
-
-	// just in right
-	require.Equal(t, map[string][]*string{
-		"right": {nil, &baz},
-	}, mapString(map[string]string{
-		"same": "in both",
-	}, map[string]string{
-		"same":  "in both",
-		"right": "baz",
-	}))
-
-	// different
-	require.Equal(t, map[string][]*string{
-		"different": {&baz, &bop},
-	}, mapString(map[string]string{
-		"same":      "in both",
-		"different": "baz",
-	}, map[string]string{
-		"same":      "in both",
-		"different": "bop",
-	}))
+func TestDiffFormat(t *testing.T) {
+	workingDir := t.TempDir()
+	repo := createShowTestData(t, workingDir, &config.Config{})
+	proj := project.NewProject(repo, workingDir)
+
+	out := new(bytes.Buffer)
+	err := writeDiff(out, proj, output.FormatCSV, "1e", "3c")
+	require.NoError(t, err)
+	expected := `
+section,key,2cccccc,3cccccc
+Experiment,ID,1eeeeeeeee,1eeeeeeeee
+Checkpoint,ID,2ccccccccc,3ccccccccc
+Checkpoint,Created,"Mon, 02 Jan 2006 23:00:05 +08","Mon, 02 Jan 2006 23:01:05 +08"
+Metrics,metric-1,0.01,0.02
+`
+	require.Equal(t, expected[1:], out.String())
+
+	out = new(bytes.Buffer)
+	err = writeDiff(out, proj, output.FormatJSONLines, "1e", "4c")
+	require.NoError(t, err)
+	actual := out.String()
+	require.Contains(t, actual, `{"section":"Params","key":"param-1","2cccccc":100,"4cccccc":200}
+{"section":"Params","key":"param-3","2cccccc":null,"4cccccc":"hi"}
+`)
+	require.Contains(t, actual, `{"section":"Checkpoint","key":"ID","2cccccc":"2ccccccccc","4cccccc":"4ccccccccc"}
+`)
+}
+
+func TestDiffThreeCheckpoints(t *testing.T) {
+	workingDir := t.TempDir()
+	repo := createShowTestData(t, workingDir, &config.Config{})
+	proj := project.NewProject(repo, workingDir)
+
+	out := new(bytes.Buffer)
+	err := printDiff(out, aurora.NewAurora(false), proj, "1c", "3c", "4c")
+	require.NoError(t, err)
+	actual := testutil.TrimRightLines(out.String())
+
+	expected := `
+Experiment
+ID:                1eeeeee                        1eeeeee                        2eeeeee
+Command:           train.py --gamma=1.2 -x        train.py --gamma=1.2 -x
+Created:           Mon, 02 Jan 2006 22:54:05 +08  Mon, 02 Jan 2006 22:54:05 +08  Mon, 02 Jan 2006 23:03:05 +08
+Host:              10.1.1.1                       10.1.1.1                       10.1.1.2
+Python version:    3.4.5                          3.4.5                          3.4.6
+
+Params
+param-1:           100                            100                            200
+param-3:           (not set)                      (not set)                      hi
+
+Python Packages
+foo:               1.2.3                          1.2.3                          (not set)
+foo2:              1.2.3                          1.2.3                          (not set)
+foo3:              1.2.3                          1.2.3                          (not set)
+foo4:              1.2.3                          1.2.3                          (not set)
+foo5:              1.2.3                          1.2.3                          (not set)
+tensorflow:        2.0.0                          2.0.0                          (not set)
+
+Checkpoint
+ID:                1cccccc                        3cccccc                        4cccccc
+Created:           Mon, 02 Jan 2006 22:59:05 +08  Mon, 02 Jan 2006 23:01:05 +08  Mon, 02 Jan 2006 23:02:05 +08
+Step:              10                             20                             5
+
+Metrics
+metric-1:          0.1                            0.02                           (not set)
+metric-2:          2                              2                              (not set)
+metric-3:          (not set)                      (not set)                      0.5
+
+`
+	require.Equal(t, expected[1:], actual)
+}
+
+func TestDiffJSON(t *testing.T) {
+	workingDir := t.TempDir()
+	repo := createShowTestData(t, workingDir, &config.Config{})
+	proj := project.NewProject(repo, workingDir)
+
+	out := new(bytes.Buffer)
+	err := printDiffJSON(out, proj, "1c", "3c", "4c")
+	require.NoError(t, err)
+
+	var result struct {
+		Checkpoints []struct {
+			ExperimentID string `json:"experiment_id"`
+			CheckpointID string `json:"checkpoint_id"`
+		} `json:"checkpoints"`
+		Sections []struct {
+			Name        string `json:"name"`
+			Differences []struct {
+				Key    string        `json:"key"`
+				Values []interface{} `json:"values"`
+			} `json:"differences"`
+		} `json:"sections"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	require.Len(t, result.Checkpoints, 3)
+	require.Equal(t, "2eeeeeeeee", result.Checkpoints[2].ExperimentID)
+	require.Equal(t, "4ccccccccc", result.Checkpoints[2].CheckpointID)
+
+	params := result.Sections[1]
+	require.Equal(t, "Params", params.Name)
+	require.Len(t, params.Differences, 2)
+	require.Equal(t, "param-1", params.Differences[0].Key)
+	require.Equal(t, []interface{}{100.0, 100.0, 200.0}, params.Differences[0].Values)
+	require.Equal(t, []interface{}{nil, nil, "hi"}, params.Differences[1].Values)
+
+	metrics := result.Sections[4]
+	require.Equal(t, "Metrics", metrics.Name)
+	require.Equal(t, "metric-1", metrics.Differences[0].Key)
+	require.Equal(t, []interface{}{0.1, 0.02, nil}, metrics.Differences[0].Values)
+}
+
+func TestDiffFiles(t *testing.T) {
+	workingDir := t.TempDir()
+	repo := createShowTestData(t, workingDir, &config.Config{})
+	proj := project.NewProject(repo, workingDir)
+
+	// checkpoints 2ccccccccc and 3ccccccccc have the path "data"
+	testutil.PutTarFiles(t, repo, "checkpoints/2ccccccccc.tar.gz", "data", map[string]string{
+		"config.yaml": "epochs: 10\nlr: 0.1\nbatch_size: 32\n",
+	})
+	testutil.PutTarFiles(t, repo, "checkpoints/3ccccccccc.tar.gz", "data", map[string]string{
+		"config.yaml": "epochs: 10\nlr: 0.01\nbatch_size: 32\n",
+		"weights.bin": "\x00\x01\x02",
+	})
+
+	diff, err := loadFileDifferences(proj, "2c", "3c")
+	require.NoError(t, err)
+	out := new(bytes.Buffer)
+	require.NoError(t, printFileDiff(out, aurora.NewAurora(false), diff))
+	expected := `
+Files
+modified:  data/config.yaml
+added:     data/weights.bin
+
+--- 2cccccc/data/config.yaml
++++ 3cccccc/data/config.yaml
+@@ -1,3 +1,3 @@
+ epochs: 10
+-lr: 0.1
++lr: 0.01
+ batch_size: 32
+`
+	require.Equal(t, expected[1:], out.String())
+
+	out = new(bytes.Buffer)
+	require.NoError(t, writeFileDiff(out, diff, output.FormatCSV))
+	require.Equal(t, "path,change\ndata/config.yaml,modified\ndata/weights.bin,added\n", out.String())
+
+	diff, err = loadFileDifferences(proj, "2c", "2c")
+	require.NoError(t, err)
+	out = new(bytes.Buffer)
+	require.NoError(t, printFileDiff(out, aurora.NewAurora(false), diff))
+	require.Equal(t, "Files\n(no difference)\n", out.String())
 }
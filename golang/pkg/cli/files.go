@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/output"
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/project"
+)
+
+type filesOpts struct {
+	format        string
+	repositoryURL string
+}
+
+func newFilesCommand() *cobra.Command {
+	var opts filesOpts
+
+	cmd := &cobra.Command{
+		Use:   "files <experiment or checkpoint ID>",
+		Short: "List the files of an experiment or checkpoint",
+		Long: `List the files of an experiment or checkpoint, without checking them out.
+
+These are the files that "keepsake checkout" would copy: the files saved with
+the experiment, with the files saved with the checkpoint on top. If an
+experiment ID is passed, its best checkpoint is used, or its latest checkpoint
+if it doesn't have a primary metric.
+`,
+		Run: handleErrors(func(cmd *cobra.Command, args []string) error {
+			return listFiles(opts, args[0], os.Stdout)
+		}),
+		Args: cobra.ExactArgs(1),
+		Example: `List the files of a checkpoint:
+$ keepsake files 1cccccc
+
+Read one of those files:
+$ keepsake cat 1cccccc config.yaml
+`,
+	}
+
+	cmd.Flags().StringVar(&opts.format, "format", "", "Output format: "+output.FormatNames)
+	addRepositoryURLFlagVar(cmd, &opts.repositoryURL)
+
+	return cmd
+}
+
+func listFiles(opts filesOpts, prefix string, out io.Writer) error {
+	var format output.Format
+	if opts.format != "" {
+		var ok bool
+		format, ok = output.ParseFormat(opts.format)
+		if !ok {
+			return fmt.Errorf("Unknown format %q, it must be %s", opts.format, output.FormatNames)
+		}
+	}
+	repositoryURL, projectDir, err := getRepositoryURLFromStringOrConfig(opts.repositoryURL)
+	if err != nil {
+		return err
+	}
+	proj, err := getProject(repositoryURL, projectDir)
+	if err != nil {
+		return err
+	}
+	exp, chk, err := getExperimentAndCheckpoint(prefix, proj, "Listing")
+	if err != nil {
+		return err
+	}
+	checkpointFiles, err := proj.ListCheckpointFiles(exp, chk)
+	if err != nil {
+		return err
+	}
+
+	if opts.format != "" {
+		table := output.NewTable("path", "size", "from", "id")
+		for _, f := range checkpointFiles {
+			from, id, _ := fileSource(f, exp, chk)
+			table.AddRow(param.String(f.Path), param.Int(f.Size), param.String(from), param.String(id))
+		}
+		return table.Write(out, format)
+	}
+
+	if len(checkpointFiles) == 0 {
+		console.Info("No files were saved with %s", prefix)
+		return nil
+	}
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PATH\tSIZE\tFROM")
+	for _, f := range checkpointFiles {
+		from, _, shortID := fileSource(f, exp, chk)
+		fmt.Fprintf(tw, "%s\t%s\t%s %s\n", f.Path, console.FormatSize(f.Size), from, shortID)
+	}
+	return tw.Flush()
+}
+
+// fileSource returns whether a file was saved with the experiment or the
+// checkpoint, and the ID and short ID of that experiment or checkpoint
+func fileSource(f *project.CheckpointFile, exp *project.Experiment, chk *project.Checkpoint) (from string, id string, shortID string) {
+	if chk != nil && f.TarPath == chk.StorageTarPath() {
+		return "checkpoint", chk.ID, chk.ShortID()
+	}
+	return "experiment", exp.ID, exp.ShortID()
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/config"
+	"github.com/replicate/keepsake/golang/pkg/testutil"
+)
+
+func TestListFiles(t *testing.T) {
+	workingDir := t.TempDir()
+	repo := createShowTestData(t, workingDir, &config.Config{})
+	repositoryURL := "file://" + path.Join(workingDir, ".keepsake")
+	testutil.PutTarFiles(t, repo, "checkpoints/2ccccccccc.tar.gz", "data", map[string]string{
+		"config.yaml":  "lr: 0.1\n",
+		"weights.bin":  string(make([]byte, 2048)),
+		"logs/out.txt": "done\n",
+	})
+
+	// an experiment has the files of its best checkpoint
+	out := new(bytes.Buffer)
+	require.NoError(t, listFiles(filesOpts{repositoryURL: repositoryURL}, "1eee", out))
+	expected := `
+PATH               SIZE    FROM
+data/config.yaml   8 B     checkpoint 2cccccc
+data/logs/out.txt  5 B     checkpoint 2cccccc
+data/weights.bin   2.0 KB  checkpoint 2cccccc
+`
+	require.Equal(t, expected[1:], out.String())
+
+	out = new(bytes.Buffer)
+	require.NoError(t, listFiles(filesOpts{repositoryURL: repositoryURL, format: "csv"}, "2ccc", out))
+	expected = `
+path,size,from,id
+data/config.yaml,8,checkpoint,2ccccccccc
+data/logs/out.txt,5,checkpoint,2ccccccccc
+data/weights.bin,2048,checkpoint,2ccccccccc
+`
+	require.Equal(t, expected[1:], out.String())
+
+	// checkpoints without files
+	out = new(bytes.Buffer)
+	require.NoError(t, listFiles(filesOpts{repositoryURL: repositoryURL}, "3ccc", out))
+	require.Equal(t, "", out.String())
+
+	err := listFiles(filesOpts{repositoryURL: repositoryURL, format: "xml"}, "2ccc", out)
+	require.EqualError(t, err, `Unknown format "xml", it must be csv, tsv, markdown or jsonl`)
+}
@@ -2,10 +2,15 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/replicate/keepsake/golang/pkg/cli/list"
+	"github.com/replicate/keepsake/golang/pkg/config"
+	"github.com/replicate/keepsake/golang/pkg/errors"
+	"github.com/replicate/keepsake/golang/pkg/global"
+	"github.com/replicate/keepsake/golang/pkg/output"
 	"github.com/replicate/keepsake/golang/pkg/param"
 )
 
@@ -26,8 +31,47 @@ List experiments where the parameter "optimizer" is "adam" and
 the best "accuracy" metric is greater than 0.8:
 $ keepsake ls --filter "optimizer = adam" --filter "accuracy > 0.8"
 
+List experiments that used either "adam" or "adamw" and are not running:
+$ keepsake ls --filter "(optimizer = adam or optimizer = adamw) and not status = running"
+
+List experiments using one of several models, run with a command that
+matches a regular expression:
+$ keepsake ls --filter "model in [resnet50, vit]" --filter "command ~ train_large"
+
+Params and metrics that are objects can be filtered and sorted on with
+paths inside them:
+$ keepsake ls --filter "optimizer.lr < 0.01" --sort "layers[0].units"
+
+Metrics can be aggregated over all the checkpoints of an experiment with
+min, max, mean, first, last and count:
+$ keepsake ls --filter "max(val_acc) > 0.9" --sort "min(loss)"
+
+List experiments created in the last 3 days that ran for more than 2 hours:
+$ keepsake ls --filter "created > 3 days ago" --filter "duration > 2h"
+
 Sort all stopped experiments by the metric "val_loss":
 $ keepsake ls --sort "val_loss" --filter "status = stopped"
+
+Sort by the metric "val_loss", then by descending step, putting
+experiments without a "val_loss" first:
+$ keepsake ls --sort "val_loss nulls first,-step"
+
+Display a column computed from metrics, and filter and sort on it:
+$ keepsake ls --column "gap=train_acc - val_acc" --filter "gap < 0.05" --sort "-gap"
+
+Choose which columns to display, and in what order:
+$ keepsake ls --columns "id,created,status,optimizer,val_loss"
+
+Draw how the metric "val_loss" changed over each experiment's checkpoints:
+$ keepsake ls --column "sparkline(val_loss)"
+
+Write all params and metrics as CSV, or as a Markdown table to paste
+into a pull request:
+$ keepsake ls --format csv > experiments.csv
+$ keepsake ls --format markdown --columns "id,optimizer,val_loss"
+
+Print each experiment with a Go template:
+$ keepsake ls --format 'template={{.ShortID}} {{.GetValue "val_loss"}}'
 `,
 	}
 
@@ -35,6 +79,7 @@ $ keepsake ls --sort "val_loss" --filter "status = stopped"
 	addListFormatFlags(cmd)
 	addListFilterFlag(cmd)
 	addListSortFlag(cmd)
+	addListColumnFlags(cmd)
 
 	return cmd
 }
@@ -44,67 +89,115 @@ func listExperiments(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	format, all, err := parseListFormatFlags(cmd)
+	opts, err := parseListFlags(cmd)
 	if err != nil {
 		return err
 	}
-	filters, err := parseListFilterFlag(cmd)
+	proj, err := getProject(repositoryURL, projectDir)
 	if err != nil {
 		return err
 	}
-	sortKey, err := parseListSortFlag(cmd)
+	return list.Experiments(proj, opts)
+}
+
+// parseListFlags parses the flags that ls and ps share
+func parseListFlags(cmd *cobra.Command) (*list.Options, error) {
+	opts := &list.Options{}
+	if err := parseListFormatFlags(cmd, opts); err != nil {
+		return nil, err
+	}
+	filters, err := parseListFilterFlag(cmd)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	repo, err := getRepository(repositoryURL, projectDir)
+	sorter, err := parseListSortFlag(cmd)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	computed, columns, extraColumns, err := parseListColumnFlags(cmd)
+	if err != nil {
+		return nil, err
 	}
-	return list.Experiments(repo, format, all, filters, sortKey)
+	opts.Filters = filters
+	opts.Sorter = sorter
+	opts.Computed = computed
+	opts.Columns = columns
+	opts.ExtraColumns = extraColumns
+	return opts, nil
 }
 
 func addListFormatFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool("json", false, "Print output in JSON format")
 	cmd.Flags().Bool("all", false, "Output all params and metrics. Default: only params/metrics that differ")
 	cmd.Flags().BoolP("quiet", "q", false, "Only print experiment IDs")
+	cmd.Flags().String("format", "", "Output format: table, json, quiet, "+output.FormatNames+", or template=<go template> to print each experiment with a Go template, e.g. 'template={{.ShortID}} {{.GetValue \"val_loss\"}}'. csv, tsv, markdown and jsonl have a column for each param and metric")
 }
 
-// FIXME(bfirsh): use an opts struct and the "Var" version of flag functions to get rid of this
-func parseListFormatFlags(cmd *cobra.Command) (format list.Format, all bool, err error) {
+// parseListFormatFlags sets the format options in opts
+// FIXME(bfirsh): use the "Var" version of flag functions to get rid of this
+func parseListFormatFlags(cmd *cobra.Command, opts *list.Options) error {
 	json, err := cmd.Flags().GetBool("json")
 	if err != nil {
-		return 0, false, err
+		return err
 	}
 	if json {
-		format = list.FormatJSON
+		opts.Format = list.FormatJSON
 	} else {
-		format = list.FormatTable
+		opts.Format = list.FormatTable
 	}
 
 	quiet, err := cmd.Flags().GetBool("quiet")
 	if err != nil {
-		return 0, false, err
+		return err
 	}
-	if quiet && format == list.FormatJSON {
-		return 0, false, fmt.Errorf("Cannot use the --quiet flag in combination with --json")
+	if quiet && json {
+		return fmt.Errorf("Cannot use the --quiet flag in combination with --json")
 	}
 
-	all, err = cmd.Flags().GetBool("all")
+	opts.All, err = cmd.Flags().GetBool("all")
 	if err != nil {
-		return 0, false, err
+		return err
 	}
-	if quiet && all {
-		return 0, false, fmt.Errorf("Cannot use the --quiet flag in combination with --all")
+	if quiet && opts.All {
+		return fmt.Errorf("Cannot use the --quiet flag in combination with --all")
 	}
 	if quiet {
-		format = list.FormatQuiet
+		opts.Format = list.FormatQuiet
 	}
 
-	return format, all, nil
+	formatString, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	if formatString == "" {
+		return nil
+	}
+	if json || quiet {
+		return fmt.Errorf("Cannot use the --format flag in combination with --json or --quiet")
+	}
+	switch {
+	case formatString == "table":
+		opts.Format = list.FormatTable
+	case formatString == "json":
+		opts.Format = list.FormatJSON
+	case formatString == "quiet":
+		opts.Format = list.FormatQuiet
+	case strings.HasPrefix(formatString, "template="):
+		opts.Format = list.FormatTemplate
+		opts.Template = strings.TrimPrefix(formatString, "template=")
+	default:
+		outputFormat, ok := output.ParseFormat(formatString)
+		if !ok {
+			return fmt.Errorf("Unknown format %q, it must be table, json, quiet, %s or template=<go template>", formatString, output.FormatNames)
+		}
+		opts.Format = list.FormatOutput
+		opts.OutputFormat = outputFormat
+	}
+	return nil
 }
 
 func addListFilterFlag(cmd *cobra.Command) {
-	cmd.Flags().StringArrayP("filter", "f", []string{}, "Filters (format: \"<name> <operator> <value>\")")
+	cmd.Flags().StringArrayP("filter", "f", []string{}, "Filters (format: \"<name> <operator> <value>\", combined with and, or, not and parentheses)")
 }
 
 // The filter names ought to be validated, see https://github.com/replicate/keepsake/issues/340
@@ -124,7 +217,7 @@ func parseListFilterFlag(cmd *cobra.Command) (*param.Filters, error) {
 }
 
 func addListSortFlag(cmd *cobra.Command) {
-	cmd.Flags().StringP("sort", "s", "created", "Sort key. Suffix with '-desc' for descending sort, e.g. --sort=created-desc")
+	cmd.Flags().StringP("sort", "s", "created", "Sort keys, separated by commas. Prefix a key with '-' for descending sort, and suffix it with ' nulls first' to put missing values first, e.g. --sort=val_loss,-step")
 }
 
 func parseListSortFlag(cmd *cobra.Command) (*param.Sorter, error) {
@@ -132,5 +225,69 @@ func parseListSortFlag(cmd *cobra.Command) (*param.Sorter, error) {
 	if err != nil {
 		return nil, err
 	}
-	return param.NewSorter(sortString), nil
+	return param.NewSorter(sortString)
+}
+
+func addListColumnFlags(cmd *cobra.Command) {
+	cmd.Flags().String("columns", "", "Columns to display, separated by commas, e.g. --columns=id,created,status,optimizer,val_loss. Columns can be id, created, status, host, user, params, best_checkpoint, latest_checkpoint, sparkline(<metric>) to draw a metric over an experiment's checkpoints, or the names of params, metrics, fields like step, computed fields or expressions. Default: the columns in keepsake.yaml")
+	cmd.Flags().StringArray("column", []string{}, "Extra columns to display, in the format \"<name>=<expression>\", e.g. \"gap=train_acc - val_acc\". Named columns can be used in filters and sorting. Computed fields in keepsake.yaml can be displayed with just their name")
+}
+
+// parseListColumnFlags returns the computed fields in keepsake.yaml, with
+// any named columns added to them, the columns chosen with --columns or in
+// keepsake.yaml, and the extra columns added with --column
+func parseListColumnFlags(cmd *cobra.Command) (computed *param.ComputedFields, columns []*list.Column, extraColumns []*list.Column, err error) {
+	columnsString, err := cmd.Flags().GetString("columns")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	extraStrings, err := cmd.Flags().GetStringArray("column")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	conf, err := getListConfig()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	computed, err = param.ParseComputedFields(conf.Computed)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if columnsString != "" {
+		columns, err = list.ParseColumns(columnsString, computed)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	} else {
+		for _, s := range conf.Columns {
+			column, err := list.ParseColumn(s, computed)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("Invalid column in keepsake.yaml: %w", err)
+			}
+			columns = append(columns, column)
+		}
+	}
+
+	for _, s := range extraStrings {
+		column, err := list.ParseColumn(s, computed)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		extraColumns = append(extraColumns, column)
+	}
+	return computed, columns, extraColumns, nil
+}
+
+// getListConfig returns keepsake.yaml, or an empty config if there isn't
+// one, e.g. if the repository is passed with --repository
+func getListConfig() (*config.Config, error) {
+	conf, _, err := config.FindConfigInWorkingDir(global.ProjectDirectory)
+	if err != nil {
+		if errors.IsConfigNotFound(err) {
+			return &config.Config{}, nil
+		}
+		return nil, err
+	}
+	return conf, nil
 }
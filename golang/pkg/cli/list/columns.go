@@ -0,0 +1,225 @@
+package list
+
+import (
+	"strings"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/project"
+)
+
+// Column is a column in the table. Name is either a built-in column, like
+// "id" or "params", a sparkline like "sparkline(val_loss)", or the name of
+// an expression that is computed from each experiment, like "val_loss" or
+// "gap".
+type Column struct {
+	Name string
+	// Heading defaults to Name in upper case
+	Heading string
+	// Expression is nil for built-in columns
+	Expression *param.Expression
+
+	// sparkline is the metric that is drawn as a sparkline over the
+	// experiment's checkpoints, for columns like "sparkline(val_loss)"
+	sparkline string
+
+	// hideIfSame hides the column if every experiment has the same value
+	hideIfSame bool
+	// hideIfEmpty hides the column if no experiment has a value
+	hideIfEmpty bool
+}
+
+// table is what built-in columns need to know about the whole table
+type table struct {
+	paramsToDisplay  []string
+	metricsToDisplay []string
+	sparklineWidth   int
+}
+
+type builtinColumn struct {
+	heading string
+	// multiLine columns can have several lines in each row, so rows are
+	// separated by blank lines
+	multiLine bool
+	render    func(exp *project.ListExperiment, t *table) string
+}
+
+// builtinColumns are columns that are displayed specially, rather than as
+// values
+var builtinColumns = map[string]builtinColumn{
+	"id": {heading: "ID", render: func(exp *project.ListExperiment, t *table) string {
+		return exp.ShortID()
+	}},
+	"created": {heading: "CREATED", render: func(exp *project.ListExperiment, t *table) string {
+		return console.FormatTime(exp.Created)
+	}},
+	"status": {heading: "STATUS", render: func(exp *project.ListExperiment, t *table) string {
+		if exp.Running {
+			return "running"
+		}
+		return "stopped"
+	}},
+	"host": {heading: "HOST", render: func(exp *project.ListExperiment, t *table) string {
+		return exp.Host
+	}},
+	"user": {heading: "USER", render: func(exp *project.ListExperiment, t *table) string {
+		return exp.User
+	}},
+	"params": {heading: "PARAMS", multiLine: true, render: func(exp *project.ListExperiment, t *table) string {
+		params := []string{}
+		flatParams := exp.Params.Flatten()
+		for _, key := range t.paramsToDisplay {
+			if val, ok := flatParams[key]; ok {
+				params = append(params, key+"="+val.ShortString(valueMaxLength, valueTruncate))
+			}
+		}
+		return strings.Join(params, "\n")
+	}},
+	"best_checkpoint": {heading: "BEST CHECKPOINT", multiLine: true, render: func(exp *project.ListExperiment, t *table) string {
+		if exp.BestCheckpoint == nil {
+			return ""
+		}
+		return displayCheckpoint(exp.BestCheckpoint, t.metricsToDisplay)
+	}},
+	"latest_checkpoint": {heading: "LATEST CHECKPOINT", multiLine: true, render: func(exp *project.ListExperiment, t *table) string {
+		if exp.LatestCheckpoint == nil {
+			return ""
+		}
+		return displayCheckpoint(exp.LatestCheckpoint, t.metricsToDisplay)
+	}},
+}
+
+// ParseColumn parses a column, which is either a built-in column, a
+// sparkline of a metric like "sparkline(val_loss)", an expression like
+// "val_loss" or "train_acc - val_acc", or a named expression like
+// "gap=train_acc - val_acc". Named expressions are added to computed, so
+// filters and sorting can use them too. Like in filters, a column without
+// spaces is a name, even if it contains operators, so "metric-1" doesn't
+// need to be quoted.
+func ParseColumn(s string, computed *param.ComputedFields) (*Column, error) {
+	name, expression := splitColumn(s)
+	if name != "" {
+		if err := computed.Add(name, expression); err != nil {
+			return nil, err
+		}
+	} else {
+		name = strings.TrimSpace(expression)
+		if metric, ok := parseSparkline(name); ok {
+			return &Column{Name: name, sparkline: metric}, nil
+		}
+		if _, ok := builtinColumns[strings.ToLower(name)]; ok {
+			return &Column{Name: strings.ToLower(name)}, nil
+		}
+		if name != "" && !strings.ContainsAny(name, "\"'() \t") {
+			return &Column{Name: name, Expression: param.NameExpression(name)}, nil
+		}
+	}
+	e, err := param.ParseExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &Column{Name: name, Expression: e}, nil
+}
+
+// parseSparkline returns the metric in a column like "sparkline(val_loss)"
+func parseSparkline(s string) (metric string, ok bool) {
+	lower := strings.ToLower(s)
+	if !strings.HasPrefix(lower, "sparkline(") || !strings.HasSuffix(lower, ")") {
+		return "", false
+	}
+	metric = strings.TrimSpace(s[len("sparkline(") : len(s)-1])
+	return metric, metric != ""
+}
+
+// ParseColumns parses a comma-separated list of columns, like
+// "id,created,val_loss,gap=train_acc - val_acc"
+func ParseColumns(s string, computed *param.ComputedFields) ([]*Column, error) {
+	columns := []*Column{}
+	for _, c := range param.SplitNames(s) {
+		column, err := ParseColumn(c, computed)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// splitColumn splits a column like "gap=train_acc - val_acc" into its name
+// and expression. The name is empty if the column is just an expression.
+func splitColumn(s string) (name string, expression string) {
+	i := strings.IndexByte(s, '=')
+	if i < 0 {
+		return "", s
+	}
+	name = strings.TrimSpace(s[:i])
+	// "=" inside a quoted name isn't a separator
+	if name == "" || strings.ContainsAny(name, "\"'()[] \t") {
+		return "", s
+	}
+	return name, s[i+1:]
+}
+
+// defaultColumns are the columns of the table if none are chosen. Host
+// and user are only displayed if they differ between experiments.
+func defaultColumns(extra []*Column) []*Column {
+	columns := []*Column{
+		{Name: "id", Heading: "EXPERIMENT"},
+		{Name: "created", Heading: "STARTED"},
+		{Name: "status"},
+		{Name: "host", hideIfSame: true},
+		{Name: "user", hideIfSame: true},
+		{Name: "params"},
+	}
+	columns = append(columns, extra...)
+	return append(columns,
+		&Column{Name: "best_checkpoint", hideIfEmpty: true},
+		&Column{Name: "latest_checkpoint"},
+	)
+}
+
+// tableColumns returns the columns of the table, with extra columns after
+// the params if columns is empty, or at the end if they aren't in columns
+func tableColumns(columns []*Column, extra []*Column) []*Column {
+	if len(columns) == 0 {
+		return defaultColumns(extra)
+	}
+	result := append([]*Column{}, columns...)
+	for _, e := range extra {
+		found := false
+		for _, c := range columns {
+			if c.Name == e.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func (c *Column) heading() string {
+	if c.Heading != "" {
+		return c.Heading
+	}
+	if builtin, ok := builtinColumns[c.Name]; ok && c.Expression == nil {
+		return builtin.heading
+	}
+	return strings.ToUpper(c.Name)
+}
+
+func (c *Column) render(exp *project.ListExperiment, t *table) string {
+	if c.sparkline != "" {
+		return sparkline(exp, c.sparkline, t.sparklineWidth)
+	}
+	if c.Expression == nil {
+		return builtinColumns[c.Name].render(exp, t)
+	}
+	val := exp.Columns[c.Name]
+	if val.IsNone() {
+		return ""
+	}
+	return val.ShortString(valueMaxLength, valueTruncate)
+}
@@ -1,6 +1,7 @@
 package list
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,13 +10,13 @@ import (
 	"strconv"
 	"strings"
 	"text/tabwriter"
-	"time"
+	"text/template"
 
-	"github.com/replicate/keepsake/golang/pkg/config"
 	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/output"
 	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/plot"
 	"github.com/replicate/keepsake/golang/pkg/project"
-	"github.com/replicate/keepsake/golang/pkg/repository"
 	"github.com/replicate/keepsake/golang/pkg/slices"
 )
 
@@ -25,200 +26,192 @@ const (
 	FormatJSON = iota
 	FormatTable
 	FormatQuiet
+	FormatTemplate
+	// FormatOutput is one of the formats in the output package, like CSV
+	FormatOutput
 )
 
 const valueMaxLength = 20
 const valueTruncate = 5
 
-type ListExperiment struct {
-	ID               string              `json:"id"`
-	Created          time.Time           `json:"created"`
-	Params           param.ValueMap      `json:"params"`
-	Command          string              `json:"command"`
-	NumCheckpoints   int                 `json:"num_checkpoints"`
-	LatestCheckpoint *project.Checkpoint `json:"latest_checkpoint"`
-	BestCheckpoint   *project.Checkpoint `json:"best_checkpoint"`
-	User             string              `json:"user"`
-	Host             string              `json:"host"`
-	Running          bool                `json:"running"`
-
-	// exclude config from json output
-	Config *config.Config `json:"-"`
+// sparkline draws a metric over the experiment's checkpoints, in the order
+// they were created. It is empty if none of them have the metric.
+func sparkline(exp *project.ListExperiment, metric string, width int) string {
+	line := plot.Sparkline(project.CheckpointMetricValues(exp.Checkpoints(), metric), width)
+	if strings.TrimSpace(line) == "" {
+		return ""
+	}
+	return line
 }
 
-// We should add some validation and better error messages, see https://github.com/replicate/keepsake/issues/340
-func (exp *ListExperiment) GetValue(name string) param.Value {
-	if name == "started" || name == "created" {
-		// floating point timestamp used in sorting
-		return param.Float(float64(exp.Created.Unix()))
-	}
-	if name == "step" {
-		if exp.LatestCheckpoint != nil {
-			return param.Int(int64(exp.LatestCheckpoint.Step))
-		}
-		return param.Int(0)
-	}
-	if name == "user" {
-		return param.String(exp.User)
-	}
-	if name == "host" {
-		return param.String(exp.Host)
-	}
-	if name == "command" {
-		return param.String(exp.Command)
-	}
-	if name == "status" {
-		if exp.Running {
-			return param.String("running")
-		}
-		return param.String("stopped")
-	}
-	if exp.BestCheckpoint != nil {
-		if val, ok := exp.BestCheckpoint.Metrics[name]; ok {
-			return val
-		}
-	}
-	if val, ok := exp.Params[name]; ok {
-		return val
-	}
-	return param.None()
+// Options are the options for listing experiments
+type Options struct {
+	Format Format
+	// Template is the Go template that each experiment is printed with,
+	// for FormatTemplate
+	Template string
+	// OutputFormat is the format for FormatOutput
+	OutputFormat output.Format
+	// All displays all params and metrics, rather than only params that
+	// differ and primary metrics
+	All      bool
+	Filters  *param.Filters
+	Sorter   *param.Sorter
+	Computed *param.ComputedFields
+	// Columns are the columns of the table. If it is empty, the default
+	// columns are used.
+	Columns []*Column
+	// ExtraColumns are added after the params in the default columns, or
+	// after Columns if they aren't already in it
+	ExtraColumns []*Column
 }
 
-func Experiments(repo repository.Repository, format Format, all bool, filters *param.Filters, sorter *param.Sorter) error {
-	proj := project.NewProject(repo, "")
-	listExperiments, err := createListExperiments(proj, filters)
+// Experiments lists the experiments in proj. Filters and sorting can use
+// the names of computed fields.
+func Experiments(proj *project.Project, opts *Options) error {
+	listExperiments, err := createListExperiments(proj, opts.Filters, opts.Computed)
 	if err != nil {
 		return err
 	}
-	sort.Slice(listExperiments, func(i, j int) bool {
-		return sorter.LessThan(listExperiments[i], listExperiments[j])
+	// stable, so experiments that sort the same stay in the order they
+	// were created
+	sort.SliceStable(listExperiments, func(i, j int) bool {
+		return opts.Sorter.LessThan(listExperiments[i], listExperiments[j])
 	})
+	columns := tableColumns(opts.Columns, opts.ExtraColumns)
+	computeColumns(listExperiments, columns)
 
-	switch format {
+	switch opts.Format {
 	case FormatJSON:
 		return outputJSON(listExperiments)
 	case FormatTable:
-		return outputTable(listExperiments, all)
+		return outputTable(listExperiments, opts.All, columns)
 	case FormatQuiet:
 		return outputQuiet(listExperiments)
+	case FormatTemplate:
+		return outputTemplate(listExperiments, opts.Template)
+	case FormatOutput:
+		return outputFormatted(os.Stdout, listExperiments, opts.Columns, opts.ExtraColumns, opts.OutputFormat)
+	}
+	panic(fmt.Sprintf("Unknown format: %d", opts.Format))
+}
+
+// computeColumns sets the values of the columns that are expressions on
+// each experiment. If a column can't be computed, it is empty, and the
+// first error for each column is displayed as a warning.
+func computeColumns(experiments []*project.ListExperiment, columns []*Column) {
+	warned := map[string]bool{}
+	for _, exp := range experiments {
+		for _, col := range columns {
+			if col.Expression == nil {
+				continue
+			}
+			val, err := col.Expression.Evaluate(exp)
+			if err != nil && !warned[col.Name] {
+				console.Warn("%s", err)
+				warned[col.Name] = true
+			}
+			if exp.Columns == nil {
+				exp.Columns = param.ValueMap{}
+			}
+			exp.Columns[col.Name] = val
+		}
 	}
-	panic(fmt.Sprintf("Unknown format: %d", format))
 }
 
-func outputQuiet(experiments []*ListExperiment) error {
+func outputQuiet(experiments []*project.ListExperiment) error {
 	for _, exp := range experiments {
 		fmt.Println(exp.ID)
 	}
 	return nil
 }
 
-func outputJSON(experiments []*ListExperiment) error {
+func outputJSON(experiments []*project.ListExperiment) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(experiments)
 }
 
-func outputTable(experiments []*ListExperiment, all bool) error {
-	if len(experiments) == 0 {
-		console.Info("No experiments found")
-		return nil
+// outputTemplate prints each experiment on its own line with a Go
+// template, e.g. '{{.ShortID}} {{.GetValue "val_loss"}}'
+func outputTemplate(experiments []*project.ListExperiment, text string) error {
+	tmpl, err := template.New("format").Parse(text)
+	if err != nil {
+		return fmt.Errorf("Failed to parse template: %w", err)
 	}
-
-	paramsToDisplay := getParamsToDisplay(experiments, all)
-	metricsToDisplay := getMetricsToDisplay(experiments, all)
-
-	// does any experiment have a primary metric?
-	hasBestCheckpoint := false
+	w := bufio.NewWriter(os.Stdout)
 	for _, exp := range experiments {
-		if exp.BestCheckpoint != nil {
-			hasBestCheckpoint = true
-			break
+		if err := tmpl.Execute(w, exp); err != nil {
+			return fmt.Errorf("Failed to format experiment %s: %w", exp.ShortID(), err)
 		}
+		fmt.Fprintln(w)
 	}
+	return w.Flush()
+}
 
-	// Hide various fields if they are all the same
-	displayHost := false
-	displayUser := false
-	prevExp := experiments[0]
-	for _, exp := range experiments {
-		if exp.Host != prevExp.Host {
-			displayHost = true
-		}
-		if exp.User != prevExp.User {
-			displayUser = true
-		}
+func outputTable(experiments []*project.ListExperiment, all bool, columns []*Column) error {
+	if len(experiments) == 0 {
+		console.Info("No experiments found")
+		return nil
 	}
 
-	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-
-	headings := []string{"EXPERIMENT", "STARTED", "STATUS"}
-	if displayHost {
-		headings = append(headings, "HOST")
-	}
-	if displayUser {
-		headings = append(headings, "USER")
-	}
-	headings = append(headings, "PARAMS")
-	if hasBestCheckpoint {
-		headings = append(headings, "BEST CHECKPOINT")
+	t := &table{
+		paramsToDisplay:  getParamsToDisplay(experiments, all),
+		metricsToDisplay: getMetricsToDisplay(experiments, all),
+		sparklineWidth:   plot.SparklineWidth(),
 	}
-	headings = append(headings, "LATEST CHECKPOINT")
 
-	for i, key := range headings {
-		fmt.Fprintf(tw, "%s", key)
-		if i < len(headings)-1 {
-			fmt.Fprint(tw, "\t")
+	// render every cell first, so columns can be hidden if they are all
+	// the same or all empty
+	cells := make([][]string, len(experiments))
+	for i, exp := range experiments {
+		cells[i] = make([]string, len(columns))
+		for j, col := range columns {
+			cells[i][j] = col.render(exp, t)
 		}
 	}
-	fmt.Fprint(tw, "\n")
-
-	for _, exp := range experiments {
-		columns := []string{exp.ID[:7], console.FormatTime(exp.Created)}
-		if exp.Running {
-			columns = append(columns, "running")
-		} else {
-			columns = append(columns, "stopped")
-		}
-
-		if displayHost {
-			columns = append(columns, exp.Host)
-		}
-
-		if displayUser {
-			columns = append(columns, exp.User)
-		}
-
-		params := []string{}
-		for _, key := range paramsToDisplay {
-			if val, ok := exp.Params[key]; ok {
-				params = append(params, key+"="+val.ShortString(valueMaxLength, valueTruncate))
+	visible := []int{}
+	multiLine := false
+	for j, col := range columns {
+		same, empty := true, true
+		for i := range experiments {
+			if cells[i][j] != cells[0][j] {
+				same = false
 			}
-		}
-		columns = append(columns, strings.Join(params, "\n"))
-
-		if hasBestCheckpoint {
-			bestCheckpoint := ""
-			if exp.BestCheckpoint != nil {
-				bestCheckpoint = displayCheckpoint(exp.BestCheckpoint, metricsToDisplay)
+			if cells[i][j] != "" {
+				empty = false
 			}
-			columns = append(columns, bestCheckpoint)
 		}
-
-		latestCheckpoint := ""
-		if exp.LatestCheckpoint != nil {
-			latestCheckpoint = displayCheckpoint(exp.LatestCheckpoint, metricsToDisplay)
+		if col.hideIfSame && same || col.hideIfEmpty && empty {
+			continue
 		}
-		columns = append(columns, latestCheckpoint)
+		visible = append(visible, j)
+		if builtin, ok := builtinColumns[col.Name]; ok && col.Expression == nil && builtin.multiLine {
+			multiLine = true
+		}
+	}
 
-		writeRow(tw, columns)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
+	headings := []string{}
+	for _, j := range visible {
+		headings = append(headings, columns[j].heading())
 	}
+	fmt.Fprintln(tw, strings.Join(headings, "\t"))
 
-	if err := tw.Flush(); err != nil {
-		return err
+	for i := range experiments {
+		row := []string{}
+		for _, j := range visible {
+			row = append(row, cells[i][j])
+		}
+		if multiLine {
+			writeRow(tw, row)
+		} else {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
 	}
 
-	return nil
+	return tw.Flush()
 }
 
 func displayCheckpoint(checkpoint *project.Checkpoint, metricsToDisplay []string) string {
@@ -270,14 +263,15 @@ func writeRow(w io.Writer, columns []string) {
 }
 
 // Get experiment params to display in list. If onlyChangedParams is true, only return
-// params which have changed across experiments.
-func getParamsToDisplay(experiments []*ListExperiment, all bool) []string {
+// params which have changed across experiments. Objects are flattened, so the values
+// inside them are displayed with paths like "optimizer.lr".
+func getParamsToDisplay(experiments []*project.ListExperiment, all bool) []string {
 	expHeadingSet := map[string]bool{}
 
 	if all {
 		for _, exp := range experiments {
-			for key, val := range exp.Params {
-				// Don't show objects in list view, because they're likely long and not very helpful
+			for key, val := range exp.Params.Flatten() {
+				// Don't show empty objects in list view, because they're not very helpful
 				if val.Type() == param.TypeObject {
 					continue
 				}
@@ -287,8 +281,8 @@ func getParamsToDisplay(experiments []*ListExperiment, all bool) []string {
 	} else {
 		paramValues := param.ValueMap{}
 		for _, exp := range experiments {
-			for key, val := range exp.Params {
-				// Don't show objects in list view, because they're likely long and not very helpful
+			for key, val := range exp.Params.Flatten() {
+				// Don't show empty objects in list view, because they're not very helpful
 				if val.Type() == param.TypeObject {
 					continue
 				}
@@ -312,7 +306,7 @@ func getParamsToDisplay(experiments []*ListExperiment, all bool) []string {
 }
 
 // Get metrics to display for each checkpoint shown in list
-func getMetricsToDisplay(experiments []*ListExperiment, all bool) []string {
+func getMetricsToDisplay(experiments []*project.ListExperiment, all bool) []string {
 	metricsToDisplay := map[string]bool{}
 
 	if all {
@@ -339,30 +333,18 @@ func getMetricsToDisplay(experiments []*ListExperiment, all bool) []string {
 	return slices.StringKeys(metricsToDisplay)
 }
 
-func createListExperiments(proj *project.Project, filters *param.Filters) ([]*ListExperiment, error) {
+func createListExperiments(proj *project.Project, filters *param.Filters, computed *param.ComputedFields) ([]*project.ListExperiment, error) {
 	experiments, err := proj.Experiments()
 	if err != nil {
 		return nil, err
 	}
-	ret := []*ListExperiment{}
+	ret := []*project.ListExperiment{}
 	for _, exp := range experiments {
-		listExperiment := &ListExperiment{
-			ID:      exp.ID,
-			Params:  exp.Params,
-			Command: exp.Command,
-			Created: exp.Created,
-			Host:    exp.Host,
-			User:    exp.User,
-			Config:  exp.Config,
-		}
-		running, err := proj.ExperimentIsRunning(exp.ID)
+		listExperiment, err := project.ListExperimentFromProject(proj, exp)
 		if err != nil {
 			return nil, err
 		}
-		listExperiment.LatestCheckpoint = exp.LatestCheckpoint()
-		listExperiment.BestCheckpoint = exp.BestCheckpoint()
-		listExperiment.NumCheckpoints = len(exp.Checkpoints)
-		listExperiment.Running = running
+		listExperiment.Computed = computed
 
 		match, err := filters.Matches(listExperiment)
 		if err != nil {
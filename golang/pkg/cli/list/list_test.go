@@ -1,9 +1,13 @@
 package list
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +16,7 @@ import (
 
 	"github.com/replicate/keepsake/golang/pkg/config"
 	"github.com/replicate/keepsake/golang/pkg/hash"
+	"github.com/replicate/keepsake/golang/pkg/output"
 	"github.com/replicate/keepsake/golang/pkg/param"
 	"github.com/replicate/keepsake/golang/pkg/project"
 	"github.com/replicate/keepsake/golang/pkg/repository"
@@ -133,8 +138,11 @@ func TestListOutputTableWithPrimaryMetricOnlyChangedParams(t *testing.T) {
 
 	repo := createTestData(t, workingDir, conf)
 
+	sorter, err := param.NewSorter("started")
+	require.NoError(t, err)
+
 	actual := capturer.CaptureStdout(func() {
-		err = Experiments(repo, FormatTable, false, new(param.Filters), &param.Sorter{Key: "started"})
+		err = Experiments(project.NewProject(repo, ""), &Options{Format: FormatTable, Filters: new(param.Filters), Sorter: sorter})
 	})
 	require.NoError(t, err)
 	expected := `
@@ -160,8 +168,11 @@ func TestListOutputTableWithPrimaryMetricAll(t *testing.T) {
 	conf := &config.Config{}
 	repo := createTestData(t, workingDir, conf)
 
+	sorter, err := param.NewSorter("started")
+	require.NoError(t, err)
+
 	actual := capturer.CaptureStdout(func() {
-		err = Experiments(repo, FormatTable, true, new(param.Filters), &param.Sorter{Key: "started"})
+		err = Experiments(project.NewProject(repo, ""), &Options{Format: FormatTable, All: true, Filters: new(param.Filters), Sorter: sorter})
 	})
 	require.NoError(t, err)
 	expected := `
@@ -195,10 +206,11 @@ func TestListOutputTableFilter(t *testing.T) {
 	repo := createTestData(t, workingDir, conf)
 	filters, err := param.MakeFilters([]string{"step >= 5"})
 	require.NoError(t, err)
-	sorter := param.NewSorter("started")
+	sorter, err := param.NewSorter("started")
+	require.NoError(t, err)
 
 	actual := capturer.CaptureStdout(func() {
-		err = Experiments(repo, FormatTable, false, filters, sorter)
+		err = Experiments(project.NewProject(repo, ""), &Options{Format: FormatTable, Filters: filters, Sorter: sorter})
 	})
 	require.NoError(t, err)
 	expected := `
@@ -223,10 +235,11 @@ func TestListOutputTableFilterRunning(t *testing.T) {
 	repo := createTestData(t, workingDir, conf)
 	filters, err := param.MakeFilters([]string{"status = running"})
 	require.NoError(t, err)
-	sorter := param.NewSorter("started")
+	sorter, err := param.NewSorter("started")
+	require.NoError(t, err)
 
 	actual := capturer.CaptureStdout(func() {
-		err = Experiments(repo, FormatTable, false, filters, sorter)
+		err = Experiments(project.NewProject(repo, ""), &Options{Format: FormatTable, Filters: filters, Sorter: sorter})
 	})
 	require.NoError(t, err)
 	expected := `
@@ -247,10 +260,11 @@ func TestListOutputTableSort(t *testing.T) {
 
 	conf := &config.Config{}
 	repo := createTestData(t, workingDir, conf)
-	sorter := param.NewSorter("started-desc")
+	sorter, err := param.NewSorter("started-desc")
+	require.NoError(t, err)
 
 	actual := capturer.CaptureStdout(func() {
-		err = Experiments(repo, FormatTable, false, new(param.Filters), sorter)
+		err = Experiments(project.NewProject(repo, ""), &Options{Format: FormatTable, Filters: new(param.Filters), Sorter: sorter})
 	})
 	require.NoError(t, err)
 	expected := `
@@ -314,12 +328,15 @@ func TestListJSON(t *testing.T) {
 	require.NoError(t, project.CreateHeartbeat(repository, exp.ID, time.Now().UTC()))
 
 	// keepsake ls
+	sorter, err := param.NewSorter("started")
+	require.NoError(t, err)
+
 	actual := capturer.CaptureStdout(func() {
-		err = Experiments(repository, FormatJSON, true, new(param.Filters), &param.Sorter{Key: "started"})
+		err = Experiments(project.NewProject(repository, ""), &Options{Format: FormatJSON, All: true, Filters: new(param.Filters), Sorter: sorter})
 	})
 	require.NoError(t, err)
 
-	experiments := make([]ListExperiment, 0)
+	experiments := make([]project.ListExperiment, 0)
 	require.NoError(t, json.Unmarshal([]byte(actual), &experiments))
 	require.Equal(t, 2, len(experiments))
 
@@ -335,3 +352,403 @@ func TestListJSON(t *testing.T) {
 	require.Equal(t, param.Float(0.987), experiments[1].LatestCheckpoint.Metrics["accuracy"])
 	require.Equal(t, true, experiments[1].Running)
 }
+
+func TestListNestedParams(t *testing.T) {
+	workingDir, err := os.MkdirTemp("", "keepsake-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(workingDir)
+
+	repo, err := repository.NewDiskRepository(path.Join(workingDir, ".keepsake"))
+	require.NoError(t, err)
+	for i, params := range []string{
+		`{"optimizer": {"name": "adam", "lr": 0.01}, "layers": [{"units": 64}, {"units": 10}]}`,
+		`{"optimizer": {"name": "sgd", "lr": 0.1}, "layers": [{"units": 128}, {"units": 10}]}`,
+		`{"optimizer": {"name": "adam", "lr": 0.001}, "layers": [{"units": 256}, {"units": 10}]}`,
+	} {
+		exp := &project.Experiment{
+			ID:      fmt.Sprintf("%daaaaaaaaa", i+1),
+			Created: time.Now().UTC().Add(time.Duration(-i) * time.Minute),
+		}
+		require.NoError(t, json.Unmarshal([]byte(params), &exp.Params))
+		require.NoError(t, exp.Save(repo))
+	}
+
+	filters, err := param.MakeFilters([]string{"optimizer.lr < 0.05"})
+	require.NoError(t, err)
+	sorter, err := param.NewSorter("layers[0].units-desc")
+	require.NoError(t, err)
+
+	actual := capturer.CaptureStdout(func() {
+		err = Experiments(project.NewProject(repo, ""), &Options{Format: FormatTable, Filters: filters, Sorter: sorter})
+	})
+	require.NoError(t, err)
+	expected := `
+EXPERIMENT  STARTED             STATUS   PARAMS               LATEST CHECKPOINT
+3aaaaaa     2 minutes ago       stopped  layers[0].units=256
+                                         optimizer.lr=0.001
+
+1aaaaaa     about a second ago  stopped  layers[0].units=64
+                                         optimizer.lr=0.01
+
+`
+	expected = expected[1:] // strip initial whitespace, added for readability
+	actual = testutil.TrimRightLines(actual)
+	require.Equal(t, expected, actual)
+}
+
+func TestListAggregations(t *testing.T) {
+	workingDir, err := os.MkdirTemp("", "keepsake-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(workingDir)
+
+	conf := &config.Config{}
+	repo := createTestData(t, workingDir, conf)
+	proj := project.NewProject(repo, "")
+
+	for _, tt := range []struct {
+		filter   string
+		sort     string
+		expected []string
+	}{
+		{"max(metric-1) > 0.05", "created", []string{"1eeeeeeeee"}},
+		{"min(metric-1) < 0.05", "created", []string{"1eeeeeeeee"}},
+		{"last(metric-1) = 0.02", "created", []string{"1eeeeeeeee"}},
+		{"first(metric-1) = 0.1", "created", []string{"1eeeeeeeee"}},
+		{"mean(metric-3) = 0.5", "created", []string{"2eeeeeeeee"}},
+		{"max(step) >= 5", "count()-desc", []string{"1eeeeeeeee", "2eeeeeeeee"}},
+		{"count() = 0", "created", []string{"3eeeeeeeee"}},
+		// metric-3 is None in the checkpoint of 1eeeeeeeee, so it isn't counted
+		{"count(metric-3) = 1", "created", []string{"2eeeeeeeee"}},
+	} {
+		filters, err := param.MakeFilters([]string{tt.filter})
+		require.NoError(t, err)
+		experiments, err := createListExperiments(proj, filters, nil)
+		require.NoError(t, err)
+		sorter, err := param.NewSorter(tt.sort)
+		require.NoError(t, err)
+		sort.Slice(experiments, func(i, j int) bool {
+			return sorter.LessThan(experiments[i], experiments[j])
+		})
+		ids := []string{}
+		for _, exp := range experiments {
+			ids = append(ids, exp.ID)
+		}
+		require.Equal(t, tt.expected, ids, tt.filter)
+	}
+}
+
+func TestListCreatedAndDuration(t *testing.T) {
+	workingDir, err := os.MkdirTemp("", "keepsake-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(workingDir)
+
+	conf := &config.Config{}
+	repo := createTestData(t, workingDir, conf)
+	proj := project.NewProject(repo, "")
+
+	for _, tt := range []struct {
+		filter   string
+		expected []string
+	}{
+		{"created > 90 seconds ago", []string{"2eeeeeeeee", "1eeeeeeeee"}},
+		{"created < 90 seconds ago", []string{"3eeeeeeeee"}},
+		{"created > last year", []string{"3eeeeeeeee", "2eeeeeeeee", "1eeeeeeeee"}},
+		// 2eeeeeeeee's checkpoint was created a minute after it
+		{"duration > 30s", []string{"2eeeeeeeee"}},
+		{"duration < 30 seconds", []string{"1eeeeeeeee"}},
+		// 3eeeeeeeee doesn't have a checkpoint or heartbeat
+		{"duration = null", []string{"3eeeeeeeee"}},
+	} {
+		filters, err := param.MakeFilters([]string{tt.filter})
+		require.NoError(t, err)
+		experiments, err := createListExperiments(proj, filters, nil)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, exp := range experiments {
+			ids = append(ids, exp.ID)
+		}
+		require.Equal(t, tt.expected, ids, tt.filter)
+	}
+}
+
+func TestListComputedColumns(t *testing.T) {
+	workingDir, err := os.MkdirTemp("", "keepsake-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(workingDir)
+
+	conf := &config.Config{}
+	repo := createTestData(t, workingDir, conf)
+	proj := project.NewProject(repo, "")
+
+	computed, err := param.ParseComputedFields(map[string]string{
+		"ratio":  `"param-1" / "metric-2"`,
+		"scaled": `"param-1" * 2`,
+	})
+	require.NoError(t, err)
+	columns := []*Column{}
+	for _, s := range []string{"ratio", `"param-1" + 1`} {
+		e, err := param.ParseExpression(s)
+		require.NoError(t, err)
+		columns = append(columns, &Column{Name: s, Expression: e})
+	}
+
+	sorter, err := param.NewSorter("-scaled,created")
+	require.NoError(t, err)
+	actual := capturer.CaptureStdout(func() {
+		err = Experiments(proj, &Options{Format: FormatTable, Filters: new(param.Filters), Sorter: sorter, Computed: computed, ExtraColumns: columns})
+	})
+	require.NoError(t, err)
+	expected := `
+EXPERIMENT  STARTED             STATUS   HOST      USER     PARAMS       RATIO  "PARAM-1" + 1  BEST CHECKPOINT    LATEST CHECKPOINT
+3eeeeee     2 minutes ago       stopped  10.1.1.2  ben      param-1=200         201
+
+2eeeeee     about a minute ago  stopped  10.1.1.2  andreas  param-1=200         201                               4cccccc (step 5)
+
+1eeeeee     about a second ago  running  10.1.1.1  andreas  param-1=100  50     101            2cccccc (step 20)  3cccccc (step 20)
+                                                                                               metric-1=0.01      metric-1=0.02
+
+`
+	expected = expected[1:] // strip initial whitespace, added for readability
+	actual = testutil.TrimRightLines(actual)
+	require.Equal(t, expected, actual)
+
+	for _, tt := range []struct {
+		filter   string
+		expected []string
+	}{
+		{"scaled > 250", []string{"3eeeeeeeee", "2eeeeeeeee"}},
+		{"ratio = 50.0", []string{"1eeeeeeeee"}},
+		{"ratio = null", []string{"3eeeeeeeee", "2eeeeeeeee"}},
+	} {
+		filters, err := param.MakeFilters([]string{tt.filter})
+		require.NoError(t, err)
+		experiments, err := createListExperiments(proj, filters, computed)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, exp := range experiments {
+			ids = append(ids, exp.ID)
+		}
+		require.Equal(t, tt.expected, ids, tt.filter)
+	}
+}
+
+func TestListChosenColumns(t *testing.T) {
+	workingDir, err := os.MkdirTemp("", "keepsake-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(workingDir)
+
+	conf := &config.Config{}
+	repo := createTestData(t, workingDir, conf)
+	proj := project.NewProject(repo, "")
+
+	computed := param.NewComputedFields()
+	columns, err := ParseColumns(`id,Status,user,param-1,"metric-1" * 10,double="param-1" * 2`, computed)
+	require.NoError(t, err)
+	extra, err := ParseColumn("double", computed)
+	require.NoError(t, err)
+	step, err := ParseColumn("step", computed)
+	require.NoError(t, err)
+
+	sorter, err := param.NewSorter("-double,created")
+	require.NoError(t, err)
+	actual := capturer.CaptureStdout(func() {
+		err = Experiments(proj, &Options{
+			Format:       FormatTable,
+			Filters:      new(param.Filters),
+			Sorter:       sorter,
+			Computed:     computed,
+			Columns:      columns,
+			ExtraColumns: []*Column{extra, step},
+		})
+	})
+	require.NoError(t, err)
+	expected := `
+ID       STATUS   USER     PARAM-1  "METRIC-1" * 10  DOUBLE  STEP
+3eeeeee  stopped  ben      200                       400     0
+2eeeeee  stopped  andreas  200                       400     5
+1eeeeee  running  andreas  100      0.1              200     20
+`
+	expected = expected[1:] // strip initial whitespace, added for readability
+	actual = testutil.TrimRightLines(actual)
+	require.Equal(t, expected, actual)
+	require.Equal(t, []string{"double"}, computed.Names())
+}
+
+func TestListTemplate(t *testing.T) {
+	workingDir, err := os.MkdirTemp("", "keepsake-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(workingDir)
+
+	conf := &config.Config{}
+	repo := createTestData(t, workingDir, conf)
+
+	sorter, err := param.NewSorter("created")
+	require.NoError(t, err)
+	actual := capturer.CaptureStdout(func() {
+		err = Experiments(project.NewProject(repo, ""), &Options{
+			Format:   FormatTemplate,
+			Template: `{{.ShortID}} {{.User}} {{.GetValue "param-1"}} {{.GetValue "metric-1"}}`,
+			Filters:  new(param.Filters),
+			Sorter:   sorter,
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, `3eeeeee ben 200 null
+2eeeeee andreas 200 null
+1eeeeee andreas 100 0.01
+`, actual)
+
+	err = Experiments(project.NewProject(repo, ""), &Options{
+		Format:   FormatTemplate,
+		Template: `{{.ShortID`,
+		Filters:  new(param.Filters),
+		Sorter:   sorter,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Failed to parse template")
+}
+
+func TestListSparklineColumn(t *testing.T) {
+	workingDir := t.TempDir()
+	repo := createTestData(t, workingDir, &config.Config{})
+	proj := project.NewProject(repo, "")
+
+	computed := param.NewComputedFields()
+	columns, err := ParseColumns("id,Sparkline(metric-1)", computed)
+	require.NoError(t, err)
+	sorter, err := param.NewSorter("created")
+	require.NoError(t, err)
+	opts := &Options{
+		Format:   FormatTable,
+		Filters:  new(param.Filters),
+		Sorter:   sorter,
+		Computed: computed,
+		Columns:  columns,
+	}
+	actual := capturer.CaptureStdout(func() {
+		err = Experiments(proj, opts)
+	})
+	require.NoError(t, err)
+	expected := `
+ID       SPARKLINE(METRIC-1)
+3eeeeee
+2eeeeee
+1eeeeee  █▁▂
+`
+	require.Equal(t, expected[1:], testutil.TrimRightLines(actual))
+
+	opts.Format = FormatOutput
+	opts.OutputFormat = output.FormatCSV
+	actual = capturer.CaptureStdout(func() {
+		err = Experiments(proj, opts)
+	})
+	require.NoError(t, err)
+	require.Equal(t, "id,Sparkline(metric-1)\n3eeeeeeeee,\n2eeeeeeeee,\n1eeeeeeeee,█▁▂\n", actual)
+}
+
+func TestParseColumn(t *testing.T) {
+	computed := param.NewComputedFields()
+	for _, tt := range []struct {
+		input      string
+		name       string
+		expression string
+	}{
+		{"id", "id", ""},
+		{"CREATED", "created", ""},
+		{"val_loss", "val_loss", "val_loss"},
+		{"metric-1", "metric-1", "metric-1"},
+		{"optimizer.lr", "optimizer.lr", "optimizer.lr"},
+		{" train_acc - val_acc ", "train_acc - val_acc", "train_acc - val_acc"},
+		{"gap=train_acc - val_acc", "gap", "train_acc - val_acc"},
+		{`"a=b" * 2`, `"a=b" * 2`, `"a=b" * 2`},
+		{`optimizer["b=c"]`, `optimizer["b=c"]`, `optimizer["b=c"]`},
+	} {
+		column, err := ParseColumn(tt.input, computed)
+		require.NoError(t, err, tt.input)
+		require.Equal(t, tt.name, column.Name, tt.input)
+		if tt.expression == "" {
+			require.Nil(t, column.Expression, tt.input)
+		} else {
+			require.Equal(t, tt.expression, column.Expression.String(), tt.input)
+		}
+	}
+	require.Equal(t, []string{"gap"}, computed.Names())
+
+	_, err := ParseColumn("train_acc -", computed)
+	require.Error(t, err)
+}
+
+func TestListOutputFormats(t *testing.T) {
+	workingDir, err := os.MkdirTemp("", "keepsake-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(workingDir)
+
+	conf := &config.Config{}
+	repo := createTestData(t, workingDir, conf)
+	proj := project.NewProject(repo, "")
+
+	sorter, err := param.NewSorter("created")
+	require.NoError(t, err)
+	actual := capturer.CaptureStdout(func() {
+		err = Experiments(proj, &Options{
+			Format:       FormatOutput,
+			OutputFormat: output.FormatTSV,
+			Filters:      new(param.Filters),
+			Sorter:       sorter,
+		})
+	})
+	require.NoError(t, err)
+	lines := strings.Split(actual, "\n")
+	require.Equal(t, "id\tcreated\tstatus\thost\tuser\tcommand\tstep\tbest_checkpoint\tlatest_checkpoint\tparam-1\tparam-2\tparam-3\tparam-4\tmetric-1\tmetric-2\tmetric-3", lines[0])
+	require.Len(t, lines, 5)
+	row := strings.Split(lines[3], "\t")
+	require.Equal(t, "1eeeeeeeee", row[0])
+	require.Equal(t, []string{"running", "10.1.1.1", "andreas", "train.py --foo bar", "20", "2ccccccccc", "3ccccccccc", "100", "hello", "", "", "0.01", "2", ""}, row[2:])
+	row = strings.Split(lines[2], "\t")
+	require.Equal(t, []string{"stopped", "10.1.1.2", "andreas", "", "5", "", "4ccccccccc", "200", "hello", "hi", "", "", "", "0.5"}, row[2:])
+
+	computed := param.NewComputedFields()
+	columns, err := ParseColumns(`id,status,params,best_checkpoint,double="param-1" * 2`, computed)
+	require.NoError(t, err)
+	actual = capturer.CaptureStdout(func() {
+		err = Experiments(proj, &Options{
+			Format:       FormatOutput,
+			OutputFormat: output.FormatMarkdown,
+			Filters:      new(param.Filters),
+			Sorter:       sorter,
+			Computed:     computed,
+			Columns:      columns,
+		})
+	})
+	require.NoError(t, err)
+	expected := `
+| id         | status  | param-1 | param-2 | param-3 | param-4 | best_checkpoint | double |
+| ---------- | ------- | ------- | ------- | ------- | ------- | --------------- | ------ |
+| 3eeeeeeeee | stopped | 200     | hello   | hi      |         |                 | 400    |
+| 2eeeeeeeee | stopped | 200     | hello   | hi      |         |                 | 400    |
+| 1eeeeeeeee | running | 100     | hello   |         |         | 2ccccccccc      | 200    |
+`
+	require.Equal(t, expected[1:], actual)
+}
+
+func TestListOutputFormatsNameCollisions(t *testing.T) {
+	exp := &project.ListExperiment{
+		ID:      "1eeeeeeeee",
+		Created: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		Params:  param.ValueMap{"step": param.Int(3), "lr": param.Float(0.1)},
+		BestCheckpoint: &project.Checkpoint{
+			ID:      "1ccccccccc",
+			Step:    10,
+			Metrics: param.ValueMap{"lr": param.Float(0.2), "id": param.String("m")},
+		},
+		Computed: param.NewComputedFields(),
+	}
+	var buf bytes.Buffer
+	err := outputFormatted(&buf, []*project.ListExperiment{exp}, nil, nil, output.FormatTSV)
+	require.NoError(t, err)
+	lines := strings.Split(buf.String(), "\n")
+	require.Equal(t, "id\tcreated\tstatus\thost\tuser\tcommand\tstep\tbest_checkpoint\tlatest_checkpoint\tlr\tstep (param)\tid (metric)\tlr (metric)", lines[0])
+	row := strings.Split(lines[1], "\t")
+	require.Equal(t, "1eeeeeeeee", row[0])
+	require.Equal(t, []string{"0.1", "3", "m", "0.2"}, row[9:])
+}
@@ -0,0 +1,193 @@
+package list
+
+import (
+	"io"
+
+	"github.com/replicate/keepsake/golang/pkg/output"
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/plot"
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/slices"
+)
+
+// field is a column of a table that is written with the output package.
+// Unlike in the table that is displayed in a terminal, values are not
+// truncated and params and metrics each have their own column.
+type field struct {
+	name  string
+	value func(row *outputRow) param.Value
+}
+
+// outputRow is an experiment that is being written, with its params and
+// metrics flattened once rather than for every column
+type outputRow struct {
+	*project.ListExperiment
+	params  map[string]param.Value
+	metrics map[string]param.Value
+}
+
+func newOutputRow(exp *project.ListExperiment) *outputRow {
+	row := &outputRow{ListExperiment: exp, params: exp.Params.Flatten()}
+	if chk := displayedCheckpoint(exp); chk != nil {
+		row.metrics = chk.Metrics.Flatten()
+	}
+	return row
+}
+
+// builtinFields are the values of built-in columns in output formats
+var builtinFields = map[string]func(row *outputRow) param.Value{
+	"id": func(row *outputRow) param.Value {
+		return param.String(row.ID)
+	},
+	"created": func(row *outputRow) param.Value {
+		return param.Time(row.Created)
+	},
+	"best_checkpoint": func(row *outputRow) param.Value {
+		return checkpointID(row.BestCheckpoint)
+	},
+	"latest_checkpoint": func(row *outputRow) param.Value {
+		return checkpointID(row.LatestCheckpoint)
+	},
+}
+
+func checkpointID(chk *project.Checkpoint) param.Value {
+	if chk == nil {
+		return param.None()
+	}
+	return param.String(chk.ID)
+}
+
+// outputFormatted writes the experiments in one of the formats in the output
+// package. If columns is empty, there is a column for each built-in column,
+// each param and each metric of the best (or latest) checkpoint, followed
+// by extraColumns.
+func outputFormatted(w io.Writer, experiments []*project.ListExperiment, columns []*Column, extraColumns []*Column, format output.Format) error {
+	fields := []*field{}
+	if len(columns) == 0 {
+		for _, name := range []string{"id", "created", "status", "host", "user", "command", "step", "best_checkpoint", "latest_checkpoint"} {
+			fields = append(fields, builtinField(name))
+		}
+		fields = append(fields, paramFields(experiments, fields)...)
+		fields = append(fields, metricFields(experiments, fields)...)
+		columns = extraColumns
+	} else {
+		columns = tableColumns(columns, extraColumns)
+	}
+	for _, col := range columns {
+		switch {
+		case col.Expression != nil:
+			name := col.Name
+			fields = append(fields, &field{name: name, value: func(row *outputRow) param.Value {
+				if val, ok := row.Columns[name]; ok {
+					return val
+				}
+				return param.None()
+			}})
+		case col.sparkline != "":
+			metric, width := col.sparkline, plot.SparklineWidth()
+			fields = append(fields, &field{name: col.Name, value: func(row *outputRow) param.Value {
+				return param.String(sparkline(row.ListExperiment, metric, width))
+			}})
+		case col.Name == "params":
+			fields = append(fields, paramFields(experiments, fields)...)
+		default:
+			fields = append(fields, builtinField(col.Name))
+		}
+	}
+
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+	}
+	table := output.NewTable(names...)
+	for _, exp := range experiments {
+		row := newOutputRow(exp)
+		values := make([]param.Value, len(fields))
+		for i, f := range fields {
+			values[i] = f.value(row)
+		}
+		table.AddRow(values...)
+	}
+	return table.Write(w, format)
+}
+
+func builtinField(name string) *field {
+	if value, ok := builtinFields[name]; ok {
+		return &field{name: name, value: value}
+	}
+	return &field{name: name, value: func(row *outputRow) param.Value {
+		return row.GetValue(name)
+	}}
+}
+
+// paramFields returns a field for each param of any of the experiments,
+// with objects flattened into paths like "optimizer.lr". Params that have
+// the same name as an existing field are suffixed with " (param)".
+func paramFields(experiments []*project.ListExperiment, existing []*field) []*field {
+	taken := fieldNames(existing)
+	fields := []*field{}
+	for _, name := range getParamsToDisplay(experiments, true) {
+		name := name
+		heading := name
+		if taken[name] {
+			heading = name + " (param)"
+		}
+		fields = append(fields, &field{name: heading, value: func(row *outputRow) param.Value {
+			if val, ok := row.params[name]; ok {
+				return val
+			}
+			return param.None()
+		}})
+	}
+	return fields
+}
+
+func fieldNames(fields []*field) map[string]bool {
+	names := map[string]bool{}
+	for _, f := range fields {
+		names[f.name] = true
+	}
+	return names
+}
+
+// metricFields returns a field for each metric of the best checkpoint of
+// any of the experiments, or the latest checkpoint if they don't have a
+// best checkpoint. Metrics that have the same name as an existing field
+// are suffixed with " (metric)".
+func metricFields(experiments []*project.ListExperiment, existing []*field) []*field {
+	taken := fieldNames(existing)
+	names := map[string]bool{}
+	for _, exp := range experiments {
+		if chk := displayedCheckpoint(exp); chk != nil {
+			for key, val := range chk.Metrics.Flatten() {
+				if val.Type() != param.TypeObject {
+					names[key] = true
+				}
+			}
+		}
+	}
+	fields := []*field{}
+	for _, name := range slices.StringKeys(names) {
+		name := name
+		heading := name
+		if taken[name] {
+			heading = name + " (metric)"
+		}
+		fields = append(fields, &field{name: heading, value: func(row *outputRow) param.Value {
+			if val, ok := row.metrics[name]; ok {
+				return val
+			}
+			return param.None()
+		}})
+	}
+	return fields
+}
+
+// displayedCheckpoint is the checkpoint whose metrics are displayed for an
+// experiment
+func displayedCheckpoint(exp *project.ListExperiment) *project.Checkpoint {
+	if exp.BestCheckpoint != nil {
+		return exp.BestCheckpoint
+	}
+	return exp.LatestCheckpoint
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/plot"
+	"github.com/replicate/keepsake/golang/pkg/project"
+)
+
+const defaultPlotWidth = 80
+
+type plotOpts struct {
+	metrics       []string
+	width         int
+	height        int
+	ascii         bool
+	repositoryURL string
+}
+
+func newPlotCommand() *cobra.Command {
+	var opts plotOpts
+
+	cmd := &cobra.Command{
+		Use:   "plot <experiment or checkpoint ID> [experiment or checkpoint ID...]",
+		Short: "Plot metrics of experiments in the terminal",
+		Long: `Plot how metrics changed over the checkpoints of one or more experiments,
+as line charts in the terminal.
+
+The X axis is the step of each checkpoint. If a checkpoint ID is passed, all the
+checkpoints of its experiment are plotted. If --metric isn't passed, the primary
+metric of the experiments is plotted.
+`,
+		Run: handleErrors(func(cmd *cobra.Command, args []string) error {
+			return plotMetrics(opts, args, os.Stdout)
+		}),
+		Args: cobra.MinimumNArgs(1),
+		Example: `Plot the primary metric of an experiment:
+$ keepsake plot 1eeeeee
+
+Compare the validation loss of two experiments:
+$ keepsake plot 1eeeeee 2eeeeee --metric val_loss
+
+Plot several metrics, with only ASCII characters:
+$ keepsake plot 1eeeeee -m loss -m val_loss --ascii
+`,
+	}
+
+	cmd.Flags().StringArrayVarP(&opts.metrics, "metric", "m", []string{}, "Metric to plot. Can be passed several times to plot several metrics. Default: the primary metric")
+	cmd.Flags().IntVar(&opts.width, "width", 0, "Width of the charts. Default: the width of the terminal")
+	cmd.Flags().IntVar(&opts.height, "height", 15, "Height of the charts, in lines")
+	cmd.Flags().BoolVar(&opts.ascii, "ascii", false, "Only use ASCII characters")
+	addRepositoryURLFlagVar(cmd, &opts.repositoryURL)
+
+	return cmd
+}
+
+func plotMetrics(opts plotOpts, prefixes []string, out io.Writer) error {
+	if opts.height < 2 {
+		return fmt.Errorf("The height of the charts must be at least 2 lines")
+	}
+	repositoryURL, projectDir, err := getRepositoryURLFromStringOrConfig(opts.repositoryURL)
+	if err != nil {
+		return err
+	}
+	proj, err := getProject(repositoryURL, projectDir)
+	if err != nil {
+		return err
+	}
+
+	experiments := []*project.Experiment{}
+	seen := map[string]bool{}
+	for _, prefix := range prefixes {
+		result, err := proj.CheckpointOrExperimentFromPrefix(prefix)
+		if err != nil {
+			return err
+		}
+		if !seen[result.Experiment.ID] {
+			experiments = append(experiments, result.Experiment)
+			seen[result.Experiment.ID] = true
+		}
+	}
+
+	metrics := opts.metrics
+	if len(metrics) == 0 {
+		metric := primaryMetricName(experiments)
+		if metric == "" {
+			return fmt.Errorf("The experiments don't have a primary metric, so pass the metric to plot with --metric")
+		}
+		metrics = []string{metric}
+	}
+
+	chartOpts := plot.Options{Width: opts.width, Height: opts.height, ASCII: opts.ascii}
+	if chartOpts.Width <= 0 {
+		width, err := console.GetWidth()
+		if err != nil || width == 0 {
+			width = defaultPlotWidth
+		}
+		chartOpts.Width = int(width)
+	}
+	return writePlots(getAurora(), out, experiments, metrics, chartOpts)
+}
+
+// writePlots writes a chart for each metric, with a line for each
+// experiment that has the metric
+func writePlots(au aurora.Aurora, out io.Writer, experiments []*project.Experiment, metrics []string, opts plot.Options) error {
+	plotted := false
+	for _, metric := range metrics {
+		series := []*plot.Series{}
+		for _, exp := range experiments {
+			if s := metricSeries(exp, metric); s != nil {
+				series = append(series, s)
+			} else {
+				console.Warn("Experiment %s doesn't have any checkpoints with a numeric value of %s", exp.ShortID(), metric)
+			}
+		}
+		chart := plot.Chart(series, opts)
+		if chart == "" {
+			continue
+		}
+		if plotted {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "%s by step\n\n%s", au.Bold(metric), chart)
+		plotted = true
+	}
+	if !plotted {
+		return fmt.Errorf("None of the experiments have checkpoints with a numeric value of %s", metrics[0])
+	}
+	return nil
+}
+
+// metricSeries returns the values of a metric at each checkpoint of an
+// experiment, by step, or nil if none of the checkpoints have a numeric
+// value of the metric
+func metricSeries(exp *project.Experiment, metric string) *plot.Series {
+	checkpoints := make([]*project.Checkpoint, len(exp.Checkpoints))
+	copy(checkpoints, exp.Checkpoints)
+	sort.SliceStable(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Step < checkpoints[j].Step
+	})
+	series := &plot.Series{Name: exp.ShortID()}
+	for i, value := range project.CheckpointMetricValues(checkpoints, metric) {
+		if !math.IsNaN(value) && !math.IsInf(value, 0) {
+			series.X = append(series.X, float64(checkpoints[i].Step))
+			series.Y = append(series.Y, value)
+		}
+	}
+	if len(series.X) == 0 {
+		return nil
+	}
+	return series
+}
+
+// primaryMetricName returns the name of the primary metric of the first
+// experiment that has one, or an empty string if none of them do
+func primaryMetricName(experiments []*project.Experiment) string {
+	for _, exp := range experiments {
+		for _, chk := range exp.Checkpoints {
+			if chk.PrimaryMetric != nil && chk.PrimaryMetric.Name != "" {
+				return chk.PrimaryMetric.Name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"path"
+	"testing"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/config"
+	"github.com/replicate/keepsake/golang/pkg/plot"
+	"github.com/replicate/keepsake/golang/pkg/project"
+)
+
+func TestWritePlots(t *testing.T) {
+	workingDir := t.TempDir()
+	repo := createShowTestData(t, workingDir, &config.Config{})
+	proj := project.NewProject(repo, workingDir)
+	experiments, err := proj.Experiments()
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	err = writePlots(aurora.NewAurora(false), out, experiments, []string{"metric-1", "metric-3"}, plot.Options{Width: 30, Height: 3, ASCII: true})
+	require.NoError(t, err)
+	expected := `
+metric-1 by step
+
+ 0.1 +******
+     |      ************
+0.01 +                  ******
+     +------------------------
+      10         15         20
+      * 1eeeeee
+
+metric-3 by step
+
+0.55 +
+     |*
+0.45 +
+     +------------------------
+      5
+      * 2eeeeee
+`
+	require.Equal(t, expected[1:], out.String())
+
+	err = writePlots(aurora.NewAurora(false), out, experiments, []string{"missing"}, plot.Options{Width: 30, Height: 3})
+	require.EqualError(t, err, "None of the experiments have checkpoints with a numeric value of missing")
+}
+
+func TestPlotMetrics(t *testing.T) {
+	workingDir := t.TempDir()
+	createShowTestData(t, workingDir, &config.Config{})
+	opts := plotOpts{repositoryURL: "file://" + path.Join(workingDir, ".keepsake"), width: 30, height: 3}
+
+	// the primary metric is plotted by default, and a checkpoint ID plots
+	// its experiment
+	out := new(bytes.Buffer)
+	require.NoError(t, plotMetrics(opts, []string{"3ccc", "1eee"}, out))
+	require.Contains(t, out.String(), "metric-1")
+	require.Contains(t, out.String(), "• 1eeeeee\n")
+	require.NotContains(t, out.String(), "2eeeeee")
+
+	err := plotMetrics(opts, []string{"2eee"}, out)
+	require.EqualError(t, err, "The experiments don't have a primary metric, so pass the metric to plot with --metric")
+
+	opts.height = 1
+	err = plotMetrics(opts, []string{"1eee"}, out)
+	require.EqualError(t, err, "The height of the charts must be at least 2 lines")
+}
@@ -20,6 +20,7 @@ func newPsCommand() *cobra.Command {
 	addListFormatFlags(cmd)
 	addListFilterFlag(cmd)
 	addListSortFlag(cmd)
+	addListColumnFlags(cmd)
 
 	return cmd
 }
@@ -29,22 +30,14 @@ func listRunningExperiments(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	format, allParams, err := parseListFormatFlags(cmd)
+	opts, err := parseListFlags(cmd)
 	if err != nil {
 		return err
 	}
-	filters, err := parseListFilterFlag(cmd)
+	opts.Filters.SetExclusive("status", param.OperatorEqual, param.String("running"))
+	proj, err := getProject(repositoryURL, projectDir)
 	if err != nil {
 		return err
 	}
-	sortKey, err := parseListSortFlag(cmd)
-	if err != nil {
-		return err
-	}
-	filters.SetExclusive("status", param.OperatorEqual, param.String("running"))
-	repo, err := getRepository(repositoryURL, projectDir)
-	if err != nil {
-		return err
-	}
-	return list.Experiments(repo, format, allParams, filters, sortKey)
+	return list.Experiments(proj, opts)
 }
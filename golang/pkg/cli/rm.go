@@ -42,11 +42,10 @@ func removeExperimentOrCheckpoint(cmd *cobra.Command, prefixes []string) error {
 	if err != nil {
 		return err
 	}
-	repo, err := getRepository(repositoryURL, projectDir)
+	proj, err := getProject(repositoryURL, projectDir)
 	if err != nil {
 		return err
 	}
-	proj := project.NewProject(repo, projectDir)
 	if err != nil {
 		return err
 	}
@@ -27,12 +27,14 @@ To learn how to get started, go to ` + global.WebURL + `/docs/tutorial`,
 				console.SetLevel(console.DebugLevel)
 			}
 			console.SetColor(global.Color)
+			startCommandTracing(cmd)
 
 			if err := analytics.TrackCommand(cmd.Name()); err != nil {
 				console.Debug("analytics error: %s", err)
 			}
 		},
 		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			finishTracing(nil)
 		},
 	}
 	setPersistentFlags(&rootCmd)
@@ -40,12 +42,15 @@ To learn how to get started, go to ` + global.WebURL + `/docs/tutorial`,
 
 	rootCmd.AddCommand(
 		newAnalyticsCommand(),
+		newCatCommand(),
 		newCheckoutCommand(),
 		newRmCommand(),
 		newDiffCommand(),
 		newFeedbackCommand(),
+		newFilesCommand(),
 		newGenerateDocsCommand(&rootCmd),
 		newListCommand(),
+		newPlotCommand(),
 		newPsCommand(),
 		newShowCommand(),
 	)
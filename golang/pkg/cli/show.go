@@ -15,6 +15,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/output"
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/plot"
 	"github.com/replicate/keepsake/golang/pkg/project"
 	"github.com/replicate/keepsake/golang/pkg/slices"
 )
@@ -24,6 +27,8 @@ var timezone = time.Local
 type showOpts struct {
 	all           bool
 	json          bool
+	format        string
+	sparkline     string
 	repositoryURL string
 }
 
@@ -37,10 +42,21 @@ func newShowCommand() *cobra.Command {
 			return show(opts, args, os.Stdout)
 		}),
 		Args: cobra.ExactArgs(1),
+		Example: `Show an experiment and its checkpoints:
+$ keepsake show 1eeeeee
+
+Draw how the metric "val_loss" changed over the checkpoints of an experiment:
+$ keepsake show 1eeeeee --sparkline val_loss
+
+Write the params and metrics of each checkpoint of an experiment as CSV:
+$ keepsake show 1eeeeee --format csv
+`,
 	}
 
 	cmd.Flags().BoolVar(&opts.all, "all", false, "Show all information")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Print output in JSON format")
+	cmd.Flags().StringVar(&opts.format, "format", "", "Output format: "+output.FormatNames+", with a row for each checkpoint and a column for each param and metric")
+	cmd.Flags().StringVar(&opts.sparkline, "sparkline", "", "Metric to draw as a sparkline next to each checkpoint of an experiment")
 	addRepositoryURLFlagVar(cmd, &opts.repositoryURL)
 
 	return cmd
@@ -48,15 +64,28 @@ func newShowCommand() *cobra.Command {
 
 func show(opts showOpts, args []string, out io.Writer) error {
 	prefix := args[0]
+	var format output.Format
+	if opts.format != "" {
+		if opts.json {
+			return fmt.Errorf("Cannot use the --format flag in combination with --json")
+		}
+		var ok bool
+		format, ok = output.ParseFormat(opts.format)
+		if !ok {
+			return fmt.Errorf("Unknown format %q, it must be %s", opts.format, output.FormatNames)
+		}
+	}
+	if opts.sparkline != "" && (opts.json || opts.format != "") {
+		return fmt.Errorf("Cannot use the --sparkline flag in combination with --json or --format")
+	}
 	repositoryURL, projectDir, err := getRepositoryURLFromStringOrConfig(opts.repositoryURL)
 	if err != nil {
 		return err
 	}
-	repo, err := getRepository(repositoryURL, projectDir)
+	proj, err := getProject(repositoryURL, projectDir)
 	if err != nil {
 		return err
 	}
-	proj := project.NewProject(repo, projectDir)
 	result, err := proj.CheckpointOrExperimentFromPrefix(prefix)
 	if err != nil {
 		return err
@@ -73,10 +102,25 @@ func show(opts showOpts, args []string, out io.Writer) error {
 		return enc.Encode(result.Experiment)
 	}
 
+	if opts.format != "" {
+		checkpoints := result.Experiment.Checkpoints
+		if result.Checkpoint != nil {
+			checkpoints = []*project.Checkpoint{result.Checkpoint}
+		}
+		running, err := proj.ExperimentIsRunning(result.Experiment.ID)
+		if err != nil {
+			return err
+		}
+		return checkpointTable(result.Experiment, checkpoints, running).Write(out, format)
+	}
+
 	if result.Checkpoint != nil {
+		if opts.sparkline != "" {
+			return fmt.Errorf("The --sparkline flag can only be used when showing an experiment")
+		}
 		return showCheckpoint(au, out, proj, result.Experiment, result.Checkpoint, opts.all)
 	}
-	return showExperiment(au, out, proj, result.Experiment, opts.all)
+	return showExperiment(au, out, proj, result.Experiment, opts.all, opts.sparkline)
 }
 
 func showCheckpoint(au aurora.Aurora, out io.Writer, proj *project.Project, exp *project.Experiment, com *project.Checkpoint, all bool) error {
@@ -107,7 +151,10 @@ func showCheckpoint(au aurora.Aurora, out io.Writer, proj *project.Project, exp
 	return w.Flush()
 }
 
-func showExperiment(au aurora.Aurora, out io.Writer, proj *project.Project, exp *project.Experiment, all bool) error {
+// showExperiment displays an experiment and a table of its checkpoints. If
+// sparkline is the name of a metric, the table has a column where it is
+// drawn as a sparkline up to each checkpoint.
+func showExperiment(au aurora.Aurora, out io.Writer, proj *project.Project, exp *project.Experiment, all bool, sparkline string) error {
 	experimentRunning, err := proj.ExperimentIsRunning(exp.ID)
 	if err != nil {
 		return err
@@ -139,9 +186,14 @@ func showExperiment(au aurora.Aurora, out io.Writer, proj *project.Project, exp
 			headings = append(headings, strings.ToUpper(label))
 		}
 	}
+	var sparklines []string
+	if sparkline != "" {
+		headings = append(headings, "SPARKLINE("+strings.ToUpper(sparkline)+")")
+		sparklines = plot.SparklinePrefixes(project.CheckpointMetricValues(exp.Checkpoints, sparkline), plot.SparklineWidth())
+	}
 	fmt.Fprintf(cw, "%s\n", strings.Join(headings, "\t"))
 
-	for _, checkpoint := range exp.Checkpoints {
+	for i, checkpoint := range exp.Checkpoints {
 		columns := []string{checkpoint.ShortID(), strconv.FormatInt(checkpoint.Step, 10), console.FormatTime(checkpoint.Created)}
 		for _, label := range labelNames {
 			val := checkpoint.Metrics[label]
@@ -152,18 +204,99 @@ func showExperiment(au aurora.Aurora, out io.Writer, proj *project.Project, exp
 			}
 			columns = append(columns, s)
 		}
+		if sparklines != nil {
+			columns = append(columns, sparklines[i])
+		}
 		fmt.Fprintf(cw, "%s\n", strings.Join(columns, "\t"))
 	}
 	if err := cw.Flush(); err != nil {
 		return err
 	}
 
+	if err := writeLoggedMetrics(au, out, proj, exp); err != nil {
+		return err
+	}
+
 	fmt.Fprintf(out, "\n")
 	fmt.Fprintf(out, "To see more details about a checkpoint, run:\n")
 	fmt.Fprintf(out, "  keepsake show <checkpoint ID>\n")
 	return nil
 }
 
+// checkpointTable returns a table with a row for each checkpoint, or a
+// single row if there aren't any checkpoints, and a column for each param
+// and metric. Params that have the same name as another column are suffixed
+// with " (param)", and metrics with " (metric)".
+func checkpointTable(exp *project.Experiment, checkpoints []*project.Checkpoint, running bool) *output.Table {
+	columns := []string{"experiment", "checkpoint", "created", "step", "path", "primary_metric", "status", "host", "user", "command"}
+	taken := map[string]bool{}
+	for _, name := range columns {
+		taken[name] = true
+	}
+	params := exp.Params.Flatten()
+	paramNames := slices.StringKeys(params)
+	for _, name := range paramNames {
+		heading := name
+		if taken[name] {
+			heading += " (param)"
+		}
+		columns = append(columns, heading)
+		taken[heading] = true
+		taken[name] = true
+	}
+	metricSet := map[string]bool{}
+	for _, chk := range checkpoints {
+		for name := range chk.Metrics.Flatten() {
+			metricSet[name] = true
+		}
+	}
+	metricNames := slices.StringKeys(metricSet)
+	for _, name := range metricNames {
+		if taken[name] {
+			name += " (metric)"
+		}
+		columns = append(columns, name)
+	}
+
+	status := "stopped"
+	if running {
+		status = "running"
+	}
+	table := output.NewTable(columns...)
+	addRow := func(chk *project.Checkpoint) {
+		row := []param.Value{param.String(exp.ID), param.None(), param.Time(exp.Created), param.None(), param.None(), param.None()}
+		if chk != nil {
+			row = []param.Value{param.String(exp.ID), param.String(chk.ID), param.Time(chk.Created), param.Int(chk.Step), param.String(chk.Path), param.None()}
+			if chk.PrimaryMetric != nil {
+				row[5] = param.String(chk.PrimaryMetric.Name)
+			}
+		}
+		row = append(row, param.String(status), param.String(exp.Host), param.String(exp.User), param.String(exp.Command))
+		for _, name := range paramNames {
+			row = append(row, params[name])
+		}
+		var metrics param.ValueMap
+		if chk != nil {
+			metrics = chk.Metrics.Flatten()
+		}
+		for _, name := range metricNames {
+			if val, ok := metrics[name]; ok {
+				row = append(row, val)
+			} else {
+				row = append(row, param.None())
+			}
+		}
+		table.AddRow(row...)
+	}
+	if len(checkpoints) == 0 {
+		addRow(nil)
+	}
+	for _, chk := range checkpoints {
+		addRow(chk)
+	}
+	return table
+}
+
 func isInterestingPythonPackage(pkg string) bool {
 	switch pkg {
 	case
@@ -262,3 +395,32 @@ func writeCheckpointMetrics(au aurora.Aurora, w *tabwriter.Writer, proj *project
 	}
 	return nil
 }
+
+// writeLoggedMetrics summarises the metric series logged for an experiment
+// without checkpoints. Nothing is written if no metrics have been logged.
+func writeLoggedMetrics(au aurora.Aurora, out io.Writer, proj *project.Project, exp *project.Experiment) error {
+	metrics, err := proj.ExperimentMetrics(exp.ID)
+	if err != nil {
+		return err
+	}
+	summaries := metrics.Summaries()
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(out, "\n%s\n", au.Bold("Logged metrics"))
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "NAME\tPOINTS\tSTEPS\tMIN\tMAX\tLAST\n")
+	for _, summary := range summaries {
+		fmt.Fprintf(w, "%s\t%d\t%d-%d\t%s\t%s\t%s\n",
+			summary.Name,
+			summary.Count,
+			summary.FirstStep,
+			summary.LastStep,
+			param.Float(summary.Min).ShortString(10, 5),
+			param.Float(summary.Max).ShortString(10, 5),
+			param.Float(summary.Last).ShortString(10, 5),
+		)
+	}
+	return w.Flush()
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/replicate/keepsake/golang/pkg/config"
+	"github.com/replicate/keepsake/golang/pkg/output"
 	"github.com/replicate/keepsake/golang/pkg/param"
 	"github.com/replicate/keepsake/golang/pkg/project"
 	"github.com/replicate/keepsake/golang/pkg/repository"
@@ -195,7 +197,7 @@ func TestShowExperiment(t *testing.T) {
 
 	out := new(bytes.Buffer)
 	au := aurora.NewAurora(false)
-	err = showExperiment(au, out, proj, result.Experiment, false)
+	err = showExperiment(au, out, proj, result.Experiment, false, "")
 	require.NoError(t, err)
 	actual := out.String()
 
@@ -235,7 +237,7 @@ To see more details about a checkpoint, run:
 
 	// --all
 	out = new(bytes.Buffer)
-	err = showExperiment(au, out, proj, result.Experiment, true)
+	err = showExperiment(au, out, proj, result.Experiment, true, "")
 	require.NoError(t, err)
 	actual = out.String()
 
@@ -287,3 +289,97 @@ To see more details about a checkpoint, run:
 	require.Equal(t, "1ccccccccc", exp.Checkpoints[0].ID)
 
 }
+
+func TestShowExperimentLoggedMetrics(t *testing.T) {
+	workingDir := t.TempDir()
+
+	repo := createShowTestData(t, workingDir, &config.Config{})
+	proj := project.NewProject(repo, workingDir)
+	require.NoError(t, proj.LogMetrics("1eeeeeeeee", []project.MetricPoint{
+		{Step: 0, Metrics: map[string]param.Value{"loss": param.Float(0.9)}},
+		{Step: 1, Metrics: map[string]param.Value{"loss": param.Float(0.5)}},
+		{Step: 2, Metrics: map[string]param.Value{"loss": param.Float(0.6)}},
+	}))
+	result, err := proj.CheckpointOrExperimentFromPrefix("1eee")
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	err = showExperiment(aurora.NewAurora(false), out, proj, result.Experiment, false, "")
+	require.NoError(t, err)
+	actual := testutil.TrimRightLines(out.String())
+
+	require.Contains(t, actual, `
+Logged metrics
+NAME  POINTS  STEPS  MIN  MAX  LAST
+loss  3       0-2    0.5  0.9  0.6
+
+To see more details about a checkpoint, run:
+`)
+}
+
+func TestShowSparkline(t *testing.T) {
+	workingDir := t.TempDir()
+	repo := createShowTestData(t, workingDir, &config.Config{})
+	proj := project.NewProject(repo, workingDir)
+	result, err := proj.CheckpointOrExperimentFromPrefix("1eee")
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	err = showExperiment(aurora.NewAurora(false), out, proj, result.Experiment, false, "metric-1")
+	require.NoError(t, err)
+	actual := testutil.TrimRightLines(out.String())
+	require.Contains(t, actual, `
+Checkpoints
+ID       STEP  CREATED     METRIC-1     METRIC-2  SPARKLINE(METRIC-1)
+1cccccc  10    2006-01-02  0.1          2         █
+2cccccc  20    2006-01-02  0.01 (best)  2         █▁
+3cccccc  20    2006-01-02  0.02         2         █▁▂
+`)
+
+	repositoryURL := "file://" + path.Join(workingDir, ".keepsake")
+	err = show(showOpts{repositoryURL: repositoryURL, sparkline: "metric-1"}, []string{"1ccc"}, out)
+	require.EqualError(t, err, "The --sparkline flag can only be used when showing an experiment")
+	err = show(showOpts{repositoryURL: repositoryURL, sparkline: "metric-1", json: true}, []string{"1eee"}, out)
+	require.EqualError(t, err, "Cannot use the --sparkline flag in combination with --json or --format")
+}
+
+func TestShowFormat(t *testing.T) {
+	workingDir := t.TempDir()
+	createShowTestData(t, workingDir, &config.Config{})
+	repositoryURL := "file://" + path.Join(workingDir, ".keepsake")
+
+	out := new(bytes.Buffer)
+	err := show(showOpts{repositoryURL: repositoryURL, format: "csv"}, []string{"1eee"}, out)
+	require.NoError(t, err)
+	expected := `
+experiment,checkpoint,created,step,path,primary_metric,status,host,user,command,param-1,param-2,metric-1,metric-2
+1eeeeeeeee,1ccccccccc,2006-01-02T14:59:05Z,10,data,metric-1,running,10.1.1.1,andreas,train.py --gamma=1.2 -x,100,hello,0.1,2
+1eeeeeeeee,2ccccccccc,2006-01-02T15:00:05Z,20,data,metric-1,running,10.1.1.1,andreas,train.py --gamma=1.2 -x,100,hello,0.01,2
+1eeeeeeeee,3ccccccccc,2006-01-02T15:01:05Z,20,data,metric-1,running,10.1.1.1,andreas,train.py --gamma=1.2 -x,100,hello,0.02,2
+`
+	require.Equal(t, expected[1:], out.String())
+
+	out = new(bytes.Buffer)
+	err = show(showOpts{repositoryURL: repositoryURL, format: "jsonl"}, []string{"4cc"}, out)
+	require.NoError(t, err)
+	require.Equal(t, `{"experiment":"2eeeeeeeee","checkpoint":"4ccccccccc","created":"2006-01-02T15:02:05Z","step":5,"path":"data","primary_metric":null,"status":"stopped","host":"10.1.1.2","user":"andreas","command":"","param-1":200,"param-2":"hello","param-3":"hi","metric-3":0.5}
+`, out.String())
+
+	err = show(showOpts{repositoryURL: repositoryURL, format: "xml"}, []string{"1eee"}, out)
+	require.EqualError(t, err, `Unknown format "xml", it must be csv, tsv, markdown or jsonl`)
+}
+
+func TestCheckpointTableNameCollisions(t *testing.T) {
+	exp := &project.Experiment{
+		ID:      "1eeeeeeeee",
+		Created: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		Params:  param.ValueMap{"step": param.Int(3), "lr": param.Float(0.1)},
+	}
+	chk := &project.Checkpoint{ID: "1ccccccccc", Step: 10, Metrics: param.ValueMap{"lr": param.Float(0.2)}}
+	out := new(bytes.Buffer)
+	err := checkpointTable(exp, []*project.Checkpoint{chk}, false).Write(out, output.FormatCSV)
+	require.NoError(t, err)
+	lines := strings.Split(out.String(), "\n")
+	require.Equal(t, "experiment,checkpoint,created,step,path,primary_metric,status,host,user,command,lr,step (param),lr (metric)", lines[0])
+	require.True(t, strings.HasSuffix(lines[1], ",0.1,3,0.2"), lines[1])
+}
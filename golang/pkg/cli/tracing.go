@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/replicate/keepsake/golang/pkg/config"
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/global"
+	"github.com/replicate/keepsake/golang/pkg/tracing"
+)
+
+var (
+	// commandContext contains the span of the command that is running, so
+	// that repository operations are traced as part of it
+	commandContext = context.Background()
+
+	commandSpan       trace.Span
+	stopTracing       = func() {}
+	finishTracingOnce sync.Once
+)
+
+// getTracingExporter returns the exporter in KEEPSAKE_TRACE, or else the
+// one in keepsake.yaml. Relative file paths in keepsake.yaml are relative
+// to the project directory.
+func getTracingExporter() string {
+	if exporter := os.Getenv(tracing.EnvironmentVariable); exporter != "" {
+		return exporter
+	}
+	conf, projectDir, err := config.FindConfigInWorkingDir(global.ProjectDirectory)
+	if err != nil || conf == nil {
+		// commands that need the config report errors loading it themselves
+		return ""
+	}
+	if strings.HasPrefix(conf.Tracing, "file:") {
+		path := strings.TrimPrefix(conf.Tracing, "file:")
+		if path != "" && !filepath.IsAbs(path) {
+			return "file:" + filepath.Join(projectDir, path)
+		}
+	}
+	return conf.Tracing
+}
+
+// setupTracing sets up tracing for serviceName. Spans are exported when
+// finishTracing() is called. Errors are shown as warnings, so a typo in the
+// tracing config doesn't stop commands from running.
+func setupTracing(serviceName string) {
+	stop, err := tracing.Setup(getTracingExporter(), serviceName)
+	if err != nil {
+		console.Warn("Tracing is disabled: %s", err)
+		return
+	}
+	stopTracing = stop
+}
+
+// startCommandTracing sets up tracing for the keepsake CLI and starts a
+// span for cmd, which is ended by finishTracing()
+func startCommandTracing(cmd *cobra.Command) {
+	setupTracing("keepsake")
+	commandContext, commandSpan = tracing.Start(context.Background(), cmd.CommandPath())
+}
+
+// finishTracing ends the span for the command that is running, marking it
+// as failed if err is not nil, and exports the spans that have been
+// recorded
+func finishTracing(err error) {
+	finishTracingOnce.Do(func() {
+		if commandSpan != nil {
+			tracing.End(commandSpan, err)
+		}
+		stopTracing()
+	})
+}
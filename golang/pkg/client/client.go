@@ -0,0 +1,288 @@
+// Package client is a Go client for keepsake-daemon.
+//
+// It is the Go equivalent of python/keepsake/daemon.py: Start() runs a
+// daemon for a project and connects to it over a UNIX socket, and
+// Connect() connects to a daemon that is already running, for example one
+// that is shared between several processes over TCP.
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+	"github.com/replicate/keepsake/golang/pkg/servicepb/convert"
+)
+
+// the number of experiments requested per page by ListExperiments
+const listPageSize = 100
+
+// Client calls a Keepsake daemon.
+type Client struct {
+	conn    *grpc.ClientConn
+	daemon  servicepb.DaemonClient
+	project *servicepb.Project
+
+	// set if the daemon was started by Start()
+	process *daemonProcess
+}
+
+// New returns a client that uses an existing connection to a daemon.
+// Closing the client closes the connection.
+func New(conn *grpc.ClientConn) *Client {
+	return &Client{
+		conn:   conn,
+		daemon: servicepb.NewDaemonClient(conn),
+	}
+}
+
+// WithProject returns a client that makes requests for the project in
+// projectDir that is stored in repositoryURL, rather than for the project
+// the daemon was started with. The returned client shares its connection
+// with c, so only one of them should be closed.
+func (c *Client) WithProject(repositoryURL string, projectDir string) *Client {
+	clone := *c
+	clone.project = &servicepb.Project{RepositoryURL: repositoryURL, Directory: projectDir}
+	return &clone
+}
+
+// Daemon returns the underlying gRPC client, for calls that this package
+// doesn't wrap.
+func (c *Client) Daemon() servicepb.DaemonClient {
+	return c.daemon
+}
+
+// Close closes the connection. If the daemon was started by Start(), it
+// is stopped, after it has finished any uploads that are in progress.
+func (c *Client) Close() error {
+	err := c.conn.Close()
+	if c.process != nil {
+		if stopErr := c.process.stop(); stopErr != nil && err == nil {
+			err = stopErr
+		}
+	}
+	return err
+}
+
+// CreateExperiment creates an experiment. Files in args.Path are uploaded
+// in the background. Unless disableHeartbeat is set, the daemon keeps the
+// experiment marked as running until StopExperiment is called.
+func (c *Client) CreateExperiment(ctx context.Context, args project.CreateExperimentArgs, disableHeartbeat bool, quiet bool) (*project.Experiment, error) {
+	reply, err := c.daemon.CreateExperiment(ctx, &servicepb.CreateExperimentRequest{
+		Experiment: &servicepb.Experiment{
+			Path:           args.Path,
+			Command:        args.Command,
+			Params:         convert.ValueMapToPb(args.Params),
+			PythonPackages: args.PythonPackages,
+			PythonVersion:  args.PythonVersion,
+		},
+		DisableHeartbeat: disableHeartbeat,
+		Quiet:            quiet,
+		Project:          c.project,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return convert.ExperimentFromPb(reply.Experiment), nil
+}
+
+// CreateCheckpoint creates a checkpoint. Files in args.Path are uploaded
+// in the background. The checkpoint isn't added to its experiment until
+// the experiment is saved with SaveExperiment.
+func (c *Client) CreateCheckpoint(ctx context.Context, args project.CreateCheckpointArgs, quiet bool) (*project.Checkpoint, error) {
+	reply, err := c.daemon.CreateCheckpoint(ctx, &servicepb.CreateCheckpointRequest{
+		Checkpoint: &servicepb.Checkpoint{
+			Path:          args.Path,
+			Step:          args.Step,
+			Metrics:       convert.ValueMapToPb(args.Metrics),
+			PrimaryMetric: convert.PrimaryMetricToPb(args.PrimaryMetric),
+		},
+		Quiet:   quiet,
+		Project: c.project,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return convert.CheckpointFromPb(reply.Checkpoint), nil
+}
+
+// SaveExperiment saves an experiment's metadata, including its checkpoints
+func (c *Client) SaveExperiment(ctx context.Context, exp *project.Experiment, quiet bool) (*project.Experiment, error) {
+	reply, err := c.daemon.SaveExperiment(ctx, &servicepb.SaveExperimentRequest{
+		Experiment: convert.ExperimentToPb(exp),
+		Quiet:      quiet,
+		Project:    c.project,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return convert.ExperimentFromPb(reply.Experiment), nil
+}
+
+// StopExperiment stops an experiment's heartbeat, so it is no longer
+// marked as running
+func (c *Client) StopExperiment(ctx context.Context, experimentID string) error {
+	_, err := c.daemon.StopExperiment(ctx, &servicepb.StopExperimentRequest{
+		ExperimentID: experimentID,
+		Project:      c.project,
+	})
+	return convertError(err)
+}
+
+// LogMetrics appends metric points to an experiment's metric series
+func (c *Client) LogMetrics(ctx context.Context, experimentID string, points []project.MetricPoint) error {
+	_, err := c.daemon.LogMetrics(ctx, &servicepb.LogMetricsRequest{
+		ExperimentID: experimentID,
+		Points:       convert.MetricPointsToPb(points),
+		Project:      c.project,
+	})
+	return convertError(err)
+}
+
+// GetExperiment returns the experiment whose ID starts with prefix
+func (c *Client) GetExperiment(ctx context.Context, prefix string) (*project.Experiment, error) {
+	reply, err := c.daemon.GetExperiment(ctx, &servicepb.GetExperimentRequest{
+		ExperimentIDPrefix: prefix,
+		Project:            c.project,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return convert.ExperimentFromPb(reply.Experiment), nil
+}
+
+// ListExperiments returns the experiments that match filters, sorted by
+// sortKey. Both use the same syntax as `keepsake ls`, and may be empty.
+func (c *Client) ListExperiments(ctx context.Context, filters []string, sortKey string) ([]*project.Experiment, error) {
+	experiments := []*project.Experiment{}
+	req := &servicepb.ListExperimentsRequest{
+		Filters:  filters,
+		Sort:     sortKey,
+		PageSize: listPageSize,
+		Project:  c.project,
+	}
+	for {
+		reply, err := c.daemon.ListExperiments(ctx, req)
+		if err != nil {
+			return nil, convertError(err)
+		}
+		for _, expPb := range reply.Experiments {
+			experiments = append(experiments, convert.ExperimentFromPb(expPb))
+		}
+		if reply.NextPageToken == "" {
+			return experiments, nil
+		}
+		req.PageToken = reply.NextPageToken
+	}
+}
+
+// DeleteExperiment deletes an experiment and its checkpoints
+func (c *Client) DeleteExperiment(ctx context.Context, experimentID string) error {
+	_, err := c.daemon.DeleteExperiment(ctx, &servicepb.DeleteExperimentRequest{
+		ExperimentID: experimentID,
+		Project:      c.project,
+	})
+	return convertError(err)
+}
+
+// CheckoutCheckpoint copies the files of the checkpoint whose ID starts
+// with prefix, and of its experiment, into outputDir
+func (c *Client) CheckoutCheckpoint(ctx context.Context, prefix string, outputDir string, quiet bool) error {
+	_, err := c.daemon.CheckoutCheckpoint(ctx, &servicepb.CheckoutCheckpointRequest{
+		CheckpointIDPrefix: prefix,
+		OutputDirectory:    outputDir,
+		Quiet:              quiet,
+		Project:            c.project,
+	})
+	return convertError(err)
+}
+
+// GetCheckpoint returns the checkpoint whose ID starts with prefix, and
+// its experiment
+func (c *Client) GetCheckpoint(ctx context.Context, prefix string) (*project.Checkpoint, *project.Experiment, error) {
+	reply, err := c.daemon.GetCheckpoint(ctx, &servicepb.GetCheckpointRequest{
+		CheckpointIDPrefix: prefix,
+		Project:            c.project,
+	})
+	if err != nil {
+		return nil, nil, convertError(err)
+	}
+	return convert.CheckpointFromPb(reply.Checkpoint), convert.ExperimentFromPb(reply.Experiment), nil
+}
+
+// DeleteCheckpoint deletes a checkpoint's files and removes it from its
+// experiment
+func (c *Client) DeleteCheckpoint(ctx context.Context, checkpointID string) error {
+	_, err := c.daemon.DeleteCheckpoint(ctx, &servicepb.DeleteCheckpointRequest{
+		CheckpointID: checkpointID,
+		Project:      c.project,
+	})
+	return convertError(err)
+}
+
+// ListCheckpointFiles returns the paths of the files saved with the
+// checkpoint whose ID starts with prefix
+func (c *Client) ListCheckpointFiles(ctx context.Context, prefix string) ([]string, error) {
+	reply, err := c.daemon.ListCheckpointFiles(ctx, &servicepb.ListCheckpointFilesRequest{
+		CheckpointIDPrefix: prefix,
+		Project:            c.project,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return reply.Paths, nil
+}
+
+// CheckoutPath copies a single file or directory from the experiment or
+// checkpoint whose ID starts with prefix into outputDir
+func (c *Client) CheckoutPath(ctx context.Context, prefix string, path string, outputDir string, quiet bool) error {
+	_, err := c.daemon.CheckoutPath(ctx, &servicepb.CheckoutPathRequest{
+		IdPrefix:        prefix,
+		Path:            path,
+		OutputDirectory: outputDir,
+		Quiet:           quiet,
+		Project:         c.project,
+	})
+	return convertError(err)
+}
+
+// DiffCheckpoints returns the differences between two checkpoints, like
+// `keepsake diff`. Experiment IDs can be passed to compare their best or
+// latest checkpoints.
+func (c *Client) DiffCheckpoints(ctx context.Context, leftPrefix string, rightPrefix string) ([]*project.DiffSection, error) {
+	reply, err := c.daemon.DiffCheckpoints(ctx, &servicepb.DiffCheckpointsRequest{
+		LeftIDPrefix:  leftPrefix,
+		RightIDPrefix: rightPrefix,
+		Project:       c.project,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return convert.DiffSectionsFromPb(reply.Sections), nil
+}
+
+// Drain stops the daemon from accepting requests that write to the
+// repository, and waits until it has saved everything it was given, or until
+// ctx's deadline. It returns descriptions of the work that hasn't been saved.
+// The daemon must be restarted to accept writes again.
+func (c *Client) Drain(ctx context.Context) ([]string, error) {
+	reply, err := c.daemon.Drain(ctx, &servicepb.DrainRequest{})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return reply.PendingWork, nil
+}
+
+// ExperimentIsRunning returns true if the experiment's heartbeat is recent
+func (c *Client) ExperimentIsRunning(ctx context.Context, experimentID string) (bool, error) {
+	reply, err := c.daemon.GetExperimentStatus(ctx, &servicepb.GetExperimentStatusRequest{
+		ExperimentID: experimentID,
+		Project:      c.project,
+	})
+	if err != nil {
+		return false, convertError(err)
+	}
+	return reply.Status == servicepb.GetExperimentStatusReply_RUNNING, nil
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/errors"
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/repository"
+	"github.com/replicate/keepsake/golang/pkg/shared"
+)
+
+// startTestServer serves a daemon in process on a UNIX socket, and returns
+// a client connected to it. Requests without a project use a temporary
+// disk repository, and other projects use the file:// repository URL
+// they are given.
+func startTestServer(t *testing.T) *Client {
+	defaultRepoDir := t.TempDir()
+	projGetter := func(repositoryURL string, projectDir string) (*project.Project, error) {
+		repoDir := defaultRepoDir
+		if repositoryURL != "" {
+			repoDir = strings.TrimPrefix(repositoryURL, "file://")
+		}
+		repo, err := repository.NewDiskRepository(repoDir)
+		if err != nil {
+			return nil, err
+		}
+		return project.NewProject(repo, projectDir), nil
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "daemon.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	srv := shared.NewServer(projGetter)
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	t.Cleanup(srv.Shutdown)
+
+	client, err := Connect(context.Background(), "unix://"+socketPath, ConnectOptions{})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestClient(t *testing.T) {
+	client := startTestServer(t)
+	ctx := context.Background()
+
+	exp, err := client.CreateExperiment(ctx, project.CreateExperimentArgs{
+		Command: "train.py",
+		Params:  param.ValueMap{"lr": param.Float(0.01)},
+	}, false, true)
+	require.NoError(t, err)
+	require.Equal(t, "train.py", exp.Command)
+
+	chk, err := client.CreateCheckpoint(ctx, project.CreateCheckpointArgs{
+		Step:          10,
+		Metrics:       param.ValueMap{"loss": param.Float(0.1)},
+		PrimaryMetric: &project.PrimaryMetric{Name: "loss", Goal: project.GoalMinimize},
+	}, true)
+	require.NoError(t, err)
+	exp.Checkpoints = append(exp.Checkpoints, chk)
+	_, err = client.SaveExperiment(ctx, exp, true)
+	require.NoError(t, err)
+
+	require.NoError(t, client.LogMetrics(ctx, exp.ID, []project.MetricPoint{
+		{Step: 1, Metrics: param.ValueMap{"loss": param.Float(0.5)}},
+	}))
+
+	got, err := client.GetExperiment(ctx, exp.ID[:5])
+	require.NoError(t, err)
+	require.Equal(t, exp.ID, got.ID)
+	require.Equal(t, param.Float(0.01), got.Params["lr"])
+	require.Equal(t, chk.ID, got.BestCheckpoint().ID)
+
+	running, err := client.ExperimentIsRunning(ctx, exp.ID)
+	require.NoError(t, err)
+	require.True(t, running)
+	require.NoError(t, client.StopExperiment(ctx, exp.ID))
+	running, err = client.ExperimentIsRunning(ctx, exp.ID)
+	require.NoError(t, err)
+	require.False(t, running)
+
+	experiments, err := client.ListExperiments(ctx, []string{"lr = 0.01"}, "")
+	require.NoError(t, err)
+	require.Len(t, experiments, 1)
+	experiments, err = client.ListExperiments(ctx, []string{"lr > 0.01"}, "")
+	require.NoError(t, err)
+	require.Empty(t, experiments)
+
+	require.NoError(t, client.DeleteExperiment(ctx, exp.ID))
+	experiments, err = client.ListExperiments(ctx, nil, "")
+	require.NoError(t, err)
+	require.Empty(t, experiments)
+}
+
+func TestClientWithProject(t *testing.T) {
+	client := startTestServer(t)
+	ctx := context.Background()
+
+	otherRepoDir := t.TempDir()
+	other := client.WithProject("file://"+otherRepoDir, t.TempDir())
+	_, err := other.CreateExperiment(ctx, project.CreateExperimentArgs{}, true, true)
+	require.NoError(t, err)
+
+	experiments, err := other.ListExperiments(ctx, nil, "")
+	require.NoError(t, err)
+	require.Len(t, experiments, 1)
+	experiments, err = client.ListExperiments(ctx, nil, "")
+	require.NoError(t, err)
+	require.Empty(t, experiments)
+}
+
+func TestClientErrors(t *testing.T) {
+	client := startTestServer(t)
+	ctx := context.Background()
+
+	// a repository from the future
+	repoDir := t.TempDir()
+	repo, err := repository.NewDiskRepository(repoDir)
+	require.NoError(t, err)
+	require.NoError(t, repo.Put(repository.SpecPath, []byte(`{"version": 1000}`)))
+
+	_, err = client.WithProject("file://"+repoDir, t.TempDir()).CreateExperiment(ctx, project.CreateExperimentArgs{}, true, true)
+	require.Error(t, err)
+	require.Equal(t, errors.CodeIncompatibleRepositoryVersion, errors.Code(err))
+	require.Contains(t, err.Error(), "is using a newer storage mechanism")
+
+	_, err = client.GetExperiment(ctx, "nonexistent")
+	require.EqualError(t, err, "Experiment not found: nonexistent")
+	require.True(t, errors.IsDoesNotExist(err))
+
+	// errors without codes keep their message
+	_, err = client.ListExperiments(ctx, []string{"lr"}, "")
+	require.Error(t, err)
+	require.Equal(t, "", errors.Code(err))
+	require.NotContains(t, err.Error(), "rpc error")
+}
+
+func TestStartDaemonThatExits(t *testing.T) {
+	_, err := Start(context.Background(), StartOptions{DaemonPath: "false"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Daemon exited")
+}
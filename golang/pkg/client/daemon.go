@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// how long to wait for a daemon started by Start() to accept connections
+const startTimeout = 15 * time.Second
+
+// StartOptions configures a daemon started by Start()
+type StartOptions struct {
+	// DaemonPath is the path to the daemon binary. If empty, keepsake-daemon
+	// or keepsake-shared is looked up on the PATH.
+	DaemonPath string
+
+	// RepositoryURL and ProjectDir are passed to the daemon as -R and -D.
+	// If both are empty, the daemon finds keepsake.yaml in the working directory.
+	RepositoryURL string
+	ProjectDir    string
+
+	// Debug enables the daemon's debug output
+	Debug bool
+
+	// The daemon's output is written to Stdout and Stderr, which default
+	// to this process's stdout and stderr
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+type daemonProcess struct {
+	cmd       *exec.Cmd
+	exited    chan struct{}
+	socketDir string
+}
+
+// Start starts a daemon listening on a temporary UNIX socket, and connects
+// to it. Close() stops the daemon.
+func Start(ctx context.Context, opts StartOptions) (*Client, error) {
+	daemonPath, err := findDaemon(opts.DaemonPath)
+	if err != nil {
+		return nil, err
+	}
+	socketDir, err := os.MkdirTemp("", "keepsake-daemon-")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create directory for daemon socket: %w", err)
+	}
+	socketPath := filepath.Join(socketDir, "daemon.sock")
+
+	args := []string{}
+	if opts.RepositoryURL != "" {
+		args = append(args, "-R", opts.RepositoryURL)
+	}
+	if opts.ProjectDir != "" {
+		args = append(args, "-D", opts.ProjectDir)
+	}
+	if opts.Debug {
+		args = append(args, "-v")
+	}
+	args = append(args, socketPath)
+
+	cmd := exec.Command(daemonPath, args...)
+	cmd.Stdout = opts.Stdout
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	cmd.Stderr = opts.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(socketDir)
+		return nil, fmt.Errorf("Failed to start daemon: %w", err)
+	}
+	process := &daemonProcess{cmd: cmd, exited: make(chan struct{}), socketDir: socketDir}
+	go func() {
+		_ = cmd.Wait()
+		close(process.exited)
+	}()
+
+	// give up straight away if the daemon exits, rather than waiting for the timeout
+	dialCtx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-process.exited:
+			cancel()
+		case <-dialCtx.Done():
+		}
+	}()
+
+	client, err := Connect(dialCtx, socketPath, ConnectOptions{})
+	if err != nil {
+		_ = process.stop()
+		select {
+		case <-process.exited:
+			return nil, fmt.Errorf("Daemon exited before it accepted connections: %s", cmd.ProcessState)
+		default:
+		}
+		return nil, err
+	}
+	client.process = process
+	return client, nil
+}
+
+func findDaemon(daemonPath string) (string, error) {
+	if daemonPath != "" {
+		return daemonPath, nil
+	}
+	for _, name := range []string{"keepsake-daemon", "keepsake-shared"} {
+		if p, err := exec.LookPath(name); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("Could not find keepsake-daemon on your PATH")
+}
+
+// stop asks the daemon to exit, and waits for it to finish any uploads
+// that are in progress
+func (p *daemonProcess) stop() error {
+	defer os.RemoveAll(p.socketDir)
+	select {
+	case <-p.exited:
+		return nil
+	default:
+	}
+	if err := p.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		return fmt.Errorf("Failed to stop daemon: %w", err)
+	}
+	<-p.exited
+	return nil
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/replicate/keepsake/golang/pkg/errors"
+)
+
+// convertError turns errors returned by the daemon back into the errors
+// the daemon's project returned, so callers can use errors.Code() and
+// errors.IsDoesNotExist() etc. Errors that didn't come from the daemon's
+// project, for example connection errors, are returned as gRPC errors.
+func convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch st.Code() {
+	case codes.Internal:
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.ErrorInfo); ok {
+				return errors.FromCode(info.Reason, st.Message())
+			}
+		}
+	case codes.Unknown:
+		// errors without a code
+		return fmt.Errorf("%s", st.Message())
+	}
+	return err
+}
@@ -5,6 +5,17 @@ type Config struct {
 	Repository string `json:"repository"`
 
 	Storage string `json:"storage"` // deprecated
+
+	// Tracing exports OpenTelemetry traces, either "otlp" or
+	// "file:<path>". KEEPSAKE_TRACE overrides it.
+	Tracing string `json:"tracing,omitempty"`
+
+	// Computed are named expressions computed from params and metrics,
+	// which can be used like params and metrics in `keepsake ls`
+	Computed map[string]string `json:"computed,omitempty"`
+
+	// Columns are the columns `keepsake ls` displays by default
+	Columns []string `json:"columns,omitempty"`
 }
 
 func getDefaultConfig(workingDir string) *Config {
@@ -14,6 +14,7 @@ import (
 	"github.com/replicate/keepsake/golang/pkg/errors"
 	"github.com/replicate/keepsake/golang/pkg/files"
 	"github.com/replicate/keepsake/golang/pkg/global"
+	"github.com/replicate/keepsake/golang/pkg/param"
 	"github.com/replicate/keepsake/golang/pkg/slices"
 )
 
@@ -126,6 +127,10 @@ func Parse(text []byte, dir string) (conf *Config, err error) {
 		return nil, fmt.Errorf("Missing required field in keepsake.yaml: repository")
 	}
 
+	if _, err := param.ParseComputedFields(conf.Computed); err != nil {
+		return nil, fmt.Errorf("Invalid computed field in keepsake.yaml: %s", err)
+	}
+
 	return conf, nil
 }
 
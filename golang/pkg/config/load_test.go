@@ -130,6 +130,37 @@ func TestStorageBackwardsCompatible(t *testing.T) {
 	}, conf)
 }
 
+func TestParseComputed(t *testing.T) {
+	conf, err := Parse([]byte(`
+repository: s3://foobar
+computed:
+  gap: train_acc - val_acc
+  throughput: tokens_per_sec * gpus
+`), "")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"gap":        "train_acc - val_acc",
+		"throughput": "tokens_per_sec * gpus",
+	}, conf.Computed)
+
+	_, err = Parse([]byte(`
+repository: s3://foobar
+computed:
+  gap: train_acc -
+`), "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Failed to parse computed field "gap"`)
+
+	_, err = Parse([]byte(`
+repository: s3://foobar
+computed:
+  a: b + 1
+  b: a * 2
+`), "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "b -> a -> b")
+}
+
 func TestDeprecatedRepositoryBackwardsCompatible(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "keepsake-test")
 	require.NoError(t, err)
@@ -79,6 +79,12 @@ where VERSION is an integer.`, rootURL, specPath, err),
 	}
 }
 
+// FromCode returns an error with the given code and message, for example
+// to recreate an error that was sent over the network
+func FromCode(code string, msg string) error {
+	return &codedError{code: code, msg: msg}
+}
+
 func Code(err error) string {
 	if cerr, ok := err.(CodedError); ok {
 		return cerr.Code()
@@ -0,0 +1,36 @@
+// Package listenaddress parses the addresses that keepsake-daemon listens
+// on and that clients connect to
+package listenaddress
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Parse splits a listen address into a network and an address
+// that can be passed to net.Listen. Bare paths are treated as UNIX sockets.
+// Clients parse the address they connect to in the same way.
+func Parse(s string) (network string, address string, err error) {
+	if s == "" {
+		return "", "", fmt.Errorf("Missing listen address")
+	}
+	if !strings.Contains(s, "://") {
+		return "unix", s, nil
+	}
+	scheme := s[:strings.Index(s, "://")]
+	address = strings.TrimPrefix(s, scheme+"://")
+	switch scheme {
+	case "unix":
+		if address == "" {
+			return "", "", fmt.Errorf("Missing socket path in listen address: %s", s)
+		}
+		return "unix", address, nil
+	case "tcp":
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return "", "", fmt.Errorf("Invalid listen address %s, it must be in the format tcp://<host>:<port>", s)
+		}
+		return "tcp", address, nil
+	}
+	return "", "", fmt.Errorf("Unknown listen address scheme %q, it must be either unix:// or tcp://", scheme)
+}
@@ -0,0 +1,39 @@
+package listenaddress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		input   string
+		network string
+		address string
+	}{
+		{"/tmp/keepsake.sock", "unix", "/tmp/keepsake.sock"},
+		{"keepsake.sock", "unix", "keepsake.sock"},
+		{"unix:///tmp/keepsake.sock", "unix", "/tmp/keepsake.sock"},
+		{"tcp://0.0.0.0:7531", "tcp", "0.0.0.0:7531"},
+		{"tcp://:7531", "tcp", ":7531"},
+		{"tcp://[::1]:7531", "tcp", "[::1]:7531"},
+	} {
+		network, address, err := Parse(tt.input)
+		require.NoError(t, err)
+		require.Equal(t, tt.network, network, tt.input)
+		require.Equal(t, tt.address, address, tt.input)
+	}
+}
+
+func TestParseBad(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"unix://",
+		"tcp://localhost",
+		"http://localhost:7531",
+	} {
+		_, _, err := Parse(input)
+		require.Error(t, err, input)
+	}
+}
@@ -0,0 +1,226 @@
+// Package output writes tables of values in formats that are easy to read
+// with other tools or paste elsewhere, like CSV and Markdown. It is shared
+// by the commands that list, show and compare experiments.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/replicate/keepsake/golang/pkg/param"
+)
+
+type Format int
+
+const (
+	FormatCSV Format = iota
+	FormatTSV
+	FormatMarkdown
+	FormatJSONLines
+)
+
+// FormatNames lists the names of the formats, for help text
+const FormatNames = "csv, tsv, markdown or jsonl"
+
+var formats = map[string]Format{
+	"csv":      FormatCSV,
+	"tsv":      FormatTSV,
+	"markdown": FormatMarkdown,
+	"md":       FormatMarkdown,
+	"jsonl":    FormatJSONLines,
+}
+
+// ParseFormat returns the format called name, e.g. "csv". ok is false if
+// there isn't a format called name.
+func ParseFormat(name string) (format Format, ok bool) {
+	format, ok = formats[strings.ToLower(name)]
+	return format, ok
+}
+
+// Table is rows of values in named columns
+type Table struct {
+	Columns []string
+	Rows    [][]param.Value
+}
+
+func NewTable(columns ...string) *Table {
+	return &Table{Columns: columns, Rows: [][]param.Value{}}
+}
+
+// AddRow adds a row with a value for each column. Missing values are None.
+func (t *Table) AddRow(values ...param.Value) {
+	row := make([]param.Value, len(t.Columns))
+	for i := range row {
+		if i < len(values) {
+			row[i] = values[i]
+		} else {
+			row[i] = param.None()
+		}
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// Write writes the table to w in format
+func (t *Table) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatCSV:
+		return t.writeCSV(w)
+	case FormatTSV:
+		return t.writeTSV(w)
+	case FormatMarkdown:
+		return t.writeMarkdown(w)
+	case FormatJSONLines:
+		return t.writeJSONLines(w)
+	}
+	panic(fmt.Sprintf("Unknown format: %d", format))
+}
+
+func (t *Table) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			record[i] = cell(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// tsvEscaper escapes the characters that separate fields and rows in TSV,
+// like PostgreSQL's text format does, rather than quoting fields like CSV
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func (t *Table) writeTSV(w io.Writer) error {
+	lines := make([]string, 0, len(t.Rows)+1)
+	fields := make([]string, len(t.Columns))
+	for i, name := range t.Columns {
+		fields[i] = tsvEscaper.Replace(name)
+	}
+	lines = append(lines, strings.Join(fields, "\t"))
+	for _, row := range t.Rows {
+		fields := make([]string, len(row))
+		for i, v := range row {
+			fields[i] = tsvEscaper.Replace(cell(v))
+		}
+		lines = append(lines, strings.Join(fields, "\t"))
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+func (t *Table) writeMarkdown(w io.Writer) error {
+	rows := make([][]string, 0, len(t.Rows)+1)
+	header := make([]string, len(t.Columns))
+	for i, name := range t.Columns {
+		header[i] = markdownEscaper.Replace(name)
+	}
+	rows = append(rows, header)
+	for _, row := range t.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			if v.Type() == param.TypeDuration {
+				cells[i] = v.String()
+			} else {
+				cells[i] = markdownEscaper.Replace(cell(v))
+			}
+		}
+		rows = append(rows, cells)
+	}
+
+	// pad columns so the table is readable before it is rendered
+	widths := make([]int, len(t.Columns))
+	for _, row := range rows {
+		for i, c := range row {
+			if n := utf8.RuneCountInString(c); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	for i := range widths {
+		if widths[i] < 3 {
+			widths[i] = 3
+		}
+	}
+	separator := make([]string, len(widths))
+	for i, width := range widths {
+		separator[i] = strings.Repeat("-", width)
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		writeMarkdownRow(&b, row, widths)
+		if i == 0 {
+			writeMarkdownRow(&b, separator, widths)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownRow(b *strings.Builder, cells []string, widths []int) {
+	b.WriteString("|")
+	for i, c := range cells {
+		b.WriteString(" " + c + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c)) + " |")
+	}
+	b.WriteString("\n")
+}
+
+// writeJSONLines writes each row as a JSON object on its own line, with
+// keys in the order of the columns
+func (t *Table) writeJSONLines(w io.Writer) error {
+	var b strings.Builder
+	for _, row := range t.Rows {
+		b.WriteString("{")
+		for i, v := range row {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			key, err := json.Marshal(t.Columns[i])
+			if err != nil {
+				return err
+			}
+			value, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			b.Write(key)
+			b.WriteString(":")
+			b.Write(value)
+		}
+		b.WriteString("}\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// cell returns a value as a field in CSV or TSV. None is empty, durations
+// are seconds like in JSON, and objects are JSON.
+func cell(v param.Value) string {
+	switch v.Type() {
+	case param.TypeNone:
+		return ""
+	case param.TypeFloat:
+		f := v.FloatVal()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	case param.TypeDuration:
+		return strconv.FormatFloat(v.DurationVal().Seconds(), 'f', -1, 64)
+	}
+	return v.String()
+}
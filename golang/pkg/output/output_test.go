@@ -0,0 +1,77 @@
+package output
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/param"
+)
+
+func createTestTable() *Table {
+	created := time.Date(2021, 3, 15, 12, 0, 0, 0, time.UTC)
+	t := NewTable("id", "created", "duration", "optimizer.lr", "note", "layers")
+	t.AddRow(param.String("1eeeeee"), param.Time(created), param.Duration(90*time.Minute), param.Float(0.01), param.String("a, b | c"), param.Object([]interface{}{1.0, 2.0}))
+	t.AddRow(param.String("2eeeeee"), param.Time(created.Add(time.Hour)), param.None(), param.Float(math.NaN()), param.String("tab\there\nline"))
+	return t
+}
+
+func TestWriteCSV(t *testing.T) {
+	out := new(bytes.Buffer)
+	require.NoError(t, createTestTable().Write(out, FormatCSV))
+	expected := `id,created,duration,optimizer.lr,note,layers
+1eeeeee,2021-03-15T12:00:00Z,5400,0.01,"a, b | c","[1,2]"
+2eeeeee,2021-03-15T13:00:00Z,,NaN,"tab	here
+line",
+`
+	require.Equal(t, expected, out.String())
+}
+
+func TestWriteTSV(t *testing.T) {
+	out := new(bytes.Buffer)
+	require.NoError(t, createTestTable().Write(out, FormatTSV))
+	expected := "id\tcreated\tduration\toptimizer.lr\tnote\tlayers\n" +
+		"1eeeeee\t2021-03-15T12:00:00Z\t5400\t0.01\ta, b | c\t[1,2]\n" +
+		"2eeeeee\t2021-03-15T13:00:00Z\t\tNaN\ttab\\there\\nline\t\n"
+	require.Equal(t, expected, out.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	out := new(bytes.Buffer)
+	require.NoError(t, createTestTable().Write(out, FormatMarkdown))
+	expected := `
+| id      | created              | duration | optimizer.lr | note             | layers |
+| ------- | -------------------- | -------- | ------------ | ---------------- | ------ |
+| 1eeeeee | 2021-03-15T12:00:00Z | 1h30m0s  | 0.01         | a, b \| c        | [1,2]  |
+| 2eeeeee | 2021-03-15T13:00:00Z |          | NaN          | tab	here<br>line |        |
+`
+	require.Equal(t, expected[1:], out.String())
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	out := new(bytes.Buffer)
+	require.NoError(t, createTestTable().Write(out, FormatJSONLines))
+	expected := `{"id":"1eeeeee","created":"2021-03-15T12:00:00Z","duration":5400,"optimizer.lr":0.01,"note":"a, b | c","layers":[1,2]}
+{"id":"2eeeeee","created":"2021-03-15T13:00:00Z","duration":null,"optimizer.lr":"[NaN]","note":"tab\there\nline","layers":null}
+`
+	require.Equal(t, expected, out.String())
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, expected := range map[string]Format{
+		"csv":      FormatCSV,
+		"TSV":      FormatTSV,
+		"markdown": FormatMarkdown,
+		"md":       FormatMarkdown,
+		"jsonl":    FormatJSONLines,
+	} {
+		format, ok := ParseFormat(name)
+		require.True(t, ok, name)
+		require.Equal(t, expected, format, name)
+	}
+	_, ok := ParseFormat("xml")
+	require.False(t, ok)
+}
@@ -0,0 +1,104 @@
+package param
+
+import (
+	"math"
+	"strings"
+)
+
+// Aggregation is a function that combines the values of a metric across
+// checkpoints, used in filter and sort names like "max(val_acc)"
+type Aggregation string
+
+const (
+	AggregationMin   Aggregation = "min"
+	AggregationMax   Aggregation = "max"
+	AggregationMean  Aggregation = "mean"
+	AggregationFirst Aggregation = "first"
+	AggregationLast  Aggregation = "last"
+	AggregationCount Aggregation = "count"
+)
+
+var aggregations = map[Aggregation]bool{
+	AggregationMin:   true,
+	AggregationMax:   true,
+	AggregationMean:  true,
+	AggregationFirst: true,
+	AggregationLast:  true,
+	AggregationCount: true,
+}
+
+// ParseAggregation splits a name like "max(val_acc)" into the aggregation
+// and the name it is applied to. ok is false if name isn't an aggregation.
+// The argument can be quoted, and it is empty for "count()".
+func ParseAggregation(name string) (agg Aggregation, arg string, ok bool) {
+	open := strings.IndexByte(name, '(')
+	if open < 0 || !strings.HasSuffix(name, ")") {
+		return "", "", false
+	}
+	agg = Aggregation(strings.ToLower(strings.TrimSpace(name[:open])))
+	if !aggregations[agg] {
+		return "", "", false
+	}
+	arg = strings.TrimSpace(name[open+1 : len(name)-1])
+	if len(arg) >= 2 && (arg[0] == '"' || arg[0] == '\'') && arg[len(arg)-1] == arg[0] {
+		arg = arg[1 : len(arg)-1]
+	}
+	return agg, arg, true
+}
+
+// Aggregate combines values, which are in chronological order. Values that
+// are None are ignored, so count is the number of values that are set.
+// min, max and mean only use numbers and ignore NaN. The result is None
+// if there are no values to combine.
+func Aggregate(agg Aggregation, values []Value) Value {
+	set := []Value{}
+	for _, v := range values {
+		if !v.IsNone() {
+			set = append(set, v)
+		}
+	}
+
+	switch agg {
+	case AggregationCount:
+		return Int(int64(len(set)))
+	case AggregationFirst:
+		if len(set) == 0 {
+			return None()
+		}
+		return set[0]
+	case AggregationLast:
+		if len(set) == 0 {
+			return None()
+		}
+		return set[len(set)-1]
+	}
+
+	numbers := []Value{}
+	for _, v := range set {
+		if isNumber(v) && !math.IsNaN(toFloat(v)) {
+			numbers = append(numbers, v)
+		}
+	}
+	if len(numbers) == 0 {
+		return None()
+	}
+
+	switch agg {
+	case AggregationMin, AggregationMax:
+		result := numbers[0]
+		for _, v := range numbers[1:] {
+			if agg == AggregationMin && toFloat(v) < toFloat(result) ||
+				agg == AggregationMax && toFloat(v) > toFloat(result) {
+				result = v
+			}
+		}
+		return result
+	case AggregationMean:
+		sum := 0.0
+		for _, v := range numbers {
+			sum += toFloat(v)
+		}
+		return Float(sum / float64(len(numbers)))
+	}
+	panic("Unknown aggregation: " + string(agg))
+}
@@ -0,0 +1,48 @@
+package param
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAggregation(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		agg  Aggregation
+		arg  string
+		ok   bool
+	}{
+		{"max(val_acc)", AggregationMax, "val_acc", true},
+		{"MIN( loss )", AggregationMin, "loss", true},
+		{`last("learning rate")`, AggregationLast, "learning rate", true},
+		{"mean(optimizer.lr)", AggregationMean, "optimizer.lr", true},
+		{"count()", AggregationCount, "", true},
+		{"val_acc", "", "", false},
+		{"median(val_acc)", "", "", false},
+		{"max(val_acc", "", "", false},
+	} {
+		agg, arg, ok := ParseAggregation(tt.name)
+		require.Equal(t, tt.ok, ok, tt.name)
+		require.Equal(t, tt.agg, agg, tt.name)
+		require.Equal(t, tt.arg, arg, tt.name)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	values := []Value{None(), Float(0.5), Int(2), Float(math.NaN()), Float(0.1), String("foo"), None()}
+	require.Equal(t, Float(0.1), Aggregate(AggregationMin, values))
+	require.Equal(t, Int(2), Aggregate(AggregationMax, values))
+	require.Equal(t, Float(0.65), Aggregate(AggregationMean, []Value{Float(0.5), None(), Float(0.8)}))
+	require.Equal(t, Float(0.5), Aggregate(AggregationFirst, values))
+	require.Equal(t, String("foo"), Aggregate(AggregationLast, values))
+	require.Equal(t, Int(5), Aggregate(AggregationCount, values))
+
+	for _, agg := range []Aggregation{AggregationMin, AggregationMax, AggregationMean, AggregationFirst, AggregationLast} {
+		require.Equal(t, None(), Aggregate(agg, []Value{}), agg)
+		require.Equal(t, None(), Aggregate(agg, []Value{None()}), agg)
+	}
+	require.Equal(t, None(), Aggregate(AggregationMax, []Value{String("foo")}))
+	require.Equal(t, Int(0), Aggregate(AggregationCount, []Value{None()}))
+}
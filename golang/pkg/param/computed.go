@@ -0,0 +1,114 @@
+package param
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/replicate/keepsake/golang/pkg/slices"
+)
+
+// ComputedFields are named expressions, like "gap" for
+// "train_acc - val_acc", that filters, sorting and columns can use like any
+// other name. A nil *ComputedFields has no fields.
+type ComputedFields struct {
+	expressions map[string]*Expression
+}
+
+func NewComputedFields() *ComputedFields {
+	return &ComputedFields{expressions: map[string]*Expression{}}
+}
+
+// ParseComputedFields parses a map of names to expressions, like the
+// "computed" section of keepsake.yaml
+func ParseComputedFields(fields map[string]string) (*ComputedFields, error) {
+	computed := NewComputedFields()
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := computed.Add(name, fields[name]); err != nil {
+			return nil, err
+		}
+	}
+	return computed, nil
+}
+
+// Add parses expression and adds it as the field name, replacing any
+// field with the same name. Fields can use other fields, but not in a
+// cycle.
+func (c *ComputedFields) Add(name string, expression string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("Missing name for computed field %q", expression)
+	}
+	e, err := ParseExpression(expression)
+	if err != nil {
+		return fmt.Errorf("Failed to parse computed field %q: %w", name, err)
+	}
+	previous, hadPrevious := c.expressions[name]
+	c.expressions[name] = e
+	if cycle := c.findCycle([]string{name}); cycle != nil {
+		if hadPrevious {
+			c.expressions[name] = previous
+		} else {
+			delete(c.expressions, name)
+		}
+		if len(cycle) == 2 {
+			return fmt.Errorf("Computed field %q refers to itself", name)
+		}
+		return fmt.Errorf("Computed fields refer to each other in a cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findCycle returns the path of fields from path[0] back to itself, if
+// there is one, following the fields that the last field in path uses
+func (c *ComputedFields) findCycle(path []string) []string {
+	for _, name := range c.expressions[path[len(path)-1]].Names() {
+		if name == path[0] {
+			return append(path, name)
+		}
+		if _, ok := c.expressions[name]; !ok || slices.ContainsString(path, name) {
+			continue
+		}
+		if cycle := c.findCycle(append(path, name)); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// Names returns the sorted names of the fields
+func (c *ComputedFields) Names() []string {
+	if c == nil {
+		return []string{}
+	}
+	names := make([]string, 0, len(c.expressions))
+	for name := range c.expressions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetValue returns the value of the field name for obj, which names in
+// the field's expression are looked up in. ok is false if there is no
+// field called name. The value is None if the expression can't be
+// evaluated for obj, e.g. if it subtracts a string from a number.
+func (c *ComputedFields) GetValue(name string, obj ValueGetter) (v Value, ok bool) {
+	if c == nil {
+		return None(), false
+	}
+	e, ok := c.expressions[name]
+	if !ok {
+		return None(), false
+	}
+	v, err := e.Evaluate(obj)
+	if err != nil {
+		return None(), true
+	}
+	return v, true
+}
@@ -0,0 +1,76 @@
+package param
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputedFields(t *testing.T) {
+	computed, err := ParseComputedFields(map[string]string{
+		"gap":         "train_acc - val_acc",
+		"gap_percent": "gap * 100",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"gap", "gap_percent"}, computed.Names())
+
+	obj := testObject{"train_acc": Float(0.9), "val_acc": Float(0.8)}
+	getter := computedTestObject{obj, computed}
+	v, ok := computed.GetValue("gap_percent", getter)
+	require.True(t, ok)
+	require.InDelta(t, 10, v.FloatVal(), 1e-9)
+
+	_, ok = computed.GetValue("train_acc", getter)
+	require.False(t, ok)
+
+	// errors evaluating a field make it None
+	require.NoError(t, computed.Add("bad", "train_acc + model"))
+	getter.obj["model"] = String("resnet")
+	v, ok = computed.GetValue("bad", getter)
+	require.True(t, ok)
+	require.True(t, v.IsNone())
+
+	// nil has no fields
+	var none *ComputedFields
+	_, ok = none.GetValue("gap", obj)
+	require.False(t, ok)
+	require.Equal(t, []string{}, none.Names())
+}
+
+func TestComputedFieldsCycles(t *testing.T) {
+	computed := NewComputedFields()
+	err := computed.Add("a", "a + 1")
+	require.EqualError(t, err, `Computed field "a" refers to itself`)
+
+	require.NoError(t, computed.Add("a", "b + 1"))
+	require.NoError(t, computed.Add("b", "c * 2"))
+	err = computed.Add("c", "a - 1")
+	require.EqualError(t, err, "Computed fields refer to each other in a cycle: c -> a -> b -> c")
+	require.Equal(t, []string{"a", "b"}, computed.Names())
+
+	// replacing a field with one that makes a cycle keeps the old one
+	err = computed.Add("b", "a * 2")
+	require.Error(t, err)
+	e := computed.expressions["b"]
+	require.Equal(t, "c * 2", e.String())
+
+	err = computed.Add("", "a")
+	require.EqualError(t, err, `Missing name for computed field "a"`)
+	err = computed.Add("d", "a +")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Failed to parse computed field "d"`)
+}
+
+// computedTestObject gets the values of computed fields, like
+// project.ListExperiment does
+type computedTestObject struct {
+	obj      testObject
+	computed *ComputedFields
+}
+
+func (o computedTestObject) GetValue(name string) Value {
+	if v, ok := o.computed.GetValue(name, o); ok {
+		return v
+	}
+	return o.obj.GetValue(name)
+}
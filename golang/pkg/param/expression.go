@@ -0,0 +1,517 @@
+package param
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expressions compute values from params and metrics, e.g.
+// "train_acc - val_acc". They share names, quoting and aggregations with
+// filters, and are parsed with this grammar:
+//
+//   expression = term { ( "+" | "-" ) term }
+//   term       = unary { ( "*" | "/" | "%" ) unary }
+//   unary      = "-" unary | primary
+//   primary    = number | name | function "(" expression ")" | "(" expression ")"
+//   name       = path | aggregation "(" [ path ] ")"
+//
+// Names that contain spaces or operators, like "metric-1", must be quoted.
+
+const expressionHelp = `Expressions can use the operators +, -, *, / and %, parentheses,
+numbers, and the names of params, metrics and fields like "step" and
+"duration", e.g. train_acc - val_acc
+
+Names that contain spaces or operators must be quoted, e.g. "metric-1" * 2
+
+Metrics can be aggregated over all the checkpoints of an experiment with
+min, max, mean, first, last and count, e.g. max(val_acc) - min(val_acc).
+The functions abs, sqrt, log, exp and round can be applied to expressions.`
+
+// Expression is an arithmetic expression, parsed with ParseExpression
+type Expression struct {
+	text string
+	root exprNode
+}
+
+type exprNode interface {
+	evaluate(obj ValueGetter) (Value, error)
+	// addNames adds the names the node refers to to names
+	addNames(names map[string]bool)
+}
+
+type numberNode struct {
+	value Value
+}
+
+type nameNode struct {
+	name string
+}
+
+type binaryNode struct {
+	operator    byte
+	left, right exprNode
+}
+
+type negateNode struct {
+	expr exprNode
+}
+
+type functionNode struct {
+	name string
+	expr exprNode
+}
+
+var mathFunctions = map[string]func(Value) (Value, error){
+	"abs":   abs,
+	"sqrt":  floatFunction("sqrt", math.Sqrt),
+	"log":   floatFunction("log", math.Log),
+	"exp":   floatFunction("exp", math.Exp),
+	"round": round,
+}
+
+var numberRegex = regexp.MustCompile(`^(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?`)
+
+// ParseExpression parses an arithmetic expression like
+// "train_acc - val_acc" or "tokens_per_sec * gpus"
+func ParseExpression(s string) (*Expression, error) {
+	p := &filterParser{input: s, expression: true}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.atEnd() {
+		if p.peek() == ')' {
+			return nil, p.errorf(`Unexpected ")"`)
+		}
+		return nil, p.errorf("Expected an operator or the end of the expression")
+	}
+	return &Expression{text: strings.TrimSpace(s), root: root}, nil
+}
+
+// NameExpression returns an expression that is just the value of name,
+// which doesn't have to be quoted even if it contains operators
+func NameExpression(name string) *Expression {
+	return &Expression{text: name, root: &nameNode{name: name}}
+}
+
+// Evaluate computes the value of the expression, getting the values of
+// names from obj. If any value it uses is None, the result is None.
+// Dividing by zero also results in None.
+func (e *Expression) Evaluate(obj ValueGetter) (Value, error) {
+	v, err := e.root.evaluate(obj)
+	if err != nil {
+		return None(), fmt.Errorf("Error evaluating %s: %s", e.text, err)
+	}
+	return v, nil
+}
+
+// Names returns the sorted names that the expression uses
+func (e *Expression) Names() []string {
+	names := map[string]bool{}
+	e.root.addNames(names)
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (e *Expression) String() string {
+	return e.text
+}
+
+func (p *filterParser) parseSum() (exprNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		operator := p.peek()
+		if operator != '+' && operator != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *filterParser) parseProduct() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		operator := p.peek()
+		if operator != '*' && operator != '/' && operator != '%' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *filterParser) parseUnary() (exprNode, error) {
+	p.skipSpace()
+	if p.peek() == '-' {
+		p.pos++
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &negateNode{expr: e}, nil
+	}
+	return p.parseOperand()
+}
+
+func (p *filterParser) parseOperand() (exprNode, error) {
+	p.skipSpace()
+	start := p.pos
+	c := p.peek()
+	switch {
+	case p.atEnd():
+		return nil, p.errorf("Expected a name or a number")
+	case c == '(':
+		p.pos++
+		e, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectClose(start); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case isQuote(c):
+		name, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		return &nameNode{name: name}, nil
+	}
+
+	if match := numberRegex.FindString(p.input[p.pos:]); match != "" {
+		end := p.pos + len(match)
+		// names can start with digits, e.g. "1st_layer_units"
+		if end == len(p.input) || !isNameChar(p.input[end]) {
+			p.pos = end
+			return &numberNode{value: parseNumber(match)}, nil
+		}
+	}
+
+	for !p.atEnd() && (isNameChar(p.peek()) || p.peek() == '[') {
+		if p.peek() == '[' {
+			if err := p.skipBrackets(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, p.errorf("Expected a name or a number")
+	}
+	if p.peek() == '(' {
+		return p.parseFunction(start)
+	}
+	return &nameNode{name: p.input[start:p.pos]}, nil
+}
+
+// parseFunction parses a function call, where the function name starts at
+// start and the "(" is next in the input. Aggregations take a name, and
+// other functions take an expression.
+func (p *filterParser) parseFunction(start int) (exprNode, error) {
+	function := strings.ToLower(p.input[start:p.pos])
+	if aggregations[Aggregation(function)] {
+		name, err := p.parseAggregation(start)
+		if err != nil {
+			return nil, err
+		}
+		return &nameNode{name: name}, nil
+	}
+	if _, ok := mathFunctions[function]; !ok {
+		return nil, p.errorfAt(start, "Unknown function %q, it must be one of abs, sqrt, log, exp, round, min, max, mean, first, last or count", p.input[start:p.pos])
+	}
+	open := p.pos
+	p.pos++
+	e, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectClose(open); err != nil {
+		return nil, err
+	}
+	return &functionNode{name: function, expr: e}, nil
+}
+
+// expectClose skips over the ")" that closes the "(" at open
+func (p *filterParser) expectClose(open int) error {
+	p.skipSpace()
+	if p.peek() == ')' {
+		p.pos++
+		return nil
+	}
+	if p.atEnd() {
+		return p.errorf(`Missing ")" to close the "(" in column %d`, (&ParseError{Input: p.input, Pos: open}).Column())
+	}
+	return p.errorf(`Expected an operator or ")"`)
+}
+
+// skipBrackets skips over an index or quoted key in a path, like "[0]" or
+// `["beta.1"]`
+func (p *filterParser) skipBrackets() error {
+	open := p.pos
+	p.pos++
+	if isQuote(p.peek()) {
+		if _, err := p.parseQuoted(); err != nil {
+			return err
+		}
+	}
+	for !p.atEnd() && p.peek() != ']' {
+		p.pos++
+	}
+	if p.atEnd() {
+		return p.errorf(`Missing "]" to close the "[" in column %d`, (&ParseError{Input: p.input, Pos: open}).Column())
+	}
+	p.pos++
+	return nil
+}
+
+func parseNumber(s string) Value {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return Int(i)
+	}
+	f, _ := strconv.ParseFloat(s, 64)
+	return Float(f)
+}
+
+// isNameChar returns true if c can be part of an unquoted name in an
+// expression. Bytes of multi-byte characters are allowed.
+func isNameChar(c byte) bool {
+	return c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func (n *numberNode) evaluate(obj ValueGetter) (Value, error) {
+	return n.value, nil
+}
+
+func (n *numberNode) addNames(names map[string]bool) {}
+
+func (n *nameNode) evaluate(obj ValueGetter) (Value, error) {
+	return obj.GetValue(n.name), nil
+}
+
+func (n *nameNode) addNames(names map[string]bool) {
+	names[n.name] = true
+}
+
+func (n *binaryNode) evaluate(obj ValueGetter) (Value, error) {
+	left, err := n.left.evaluate(obj)
+	if err != nil {
+		return None(), err
+	}
+	right, err := n.right.evaluate(obj)
+	if err != nil {
+		return None(), err
+	}
+	return arithmetic(n.operator, left, right)
+}
+
+func (n *binaryNode) addNames(names map[string]bool) {
+	n.left.addNames(names)
+	n.right.addNames(names)
+}
+
+func (n *negateNode) evaluate(obj ValueGetter) (Value, error) {
+	v, err := n.expr.evaluate(obj)
+	if err != nil {
+		return None(), err
+	}
+	switch v.Type() {
+	case TypeNone:
+		return v, nil
+	case TypeInt:
+		return Int(-v.IntVal()), nil
+	case TypeFloat:
+		return Float(-v.FloatVal()), nil
+	case TypeDuration:
+		return Duration(-v.DurationVal()), nil
+	}
+	return None(), fmt.Errorf("Cannot negate a value of type %s", v.Type())
+}
+
+func (n *negateNode) addNames(names map[string]bool) {
+	n.expr.addNames(names)
+}
+
+func (n *functionNode) evaluate(obj ValueGetter) (Value, error) {
+	v, err := n.expr.evaluate(obj)
+	if err != nil || v.IsNone() {
+		return v, err
+	}
+	return mathFunctions[n.name](v)
+}
+
+func (n *functionNode) addNames(names map[string]bool) {
+	n.expr.addNames(names)
+}
+
+// arithmetic applies operator to a and b. Numbers, strings, times and
+// durations can be combined in the ways that make sense, e.g. subtracting
+// two times results in a duration. Dividing by zero results in None.
+func arithmetic(operator byte, a Value, b Value) (Value, error) {
+	if a.IsNone() || b.IsNone() {
+		return None(), nil
+	}
+	switch {
+	case isNumber(a) && isNumber(b):
+		return numberArithmetic(operator, a, b), nil
+	case a.Type() == TypeString && b.Type() == TypeString && operator == '+':
+		return String(a.StringVal() + b.StringVal()), nil
+	}
+	if v, ok := timeArithmetic(operator, a, b); ok {
+		return v, nil
+	}
+	return None(), fmt.Errorf("Cannot use %q with values of type %s and %s", string(operator), a.Type(), b.Type())
+}
+
+func numberArithmetic(operator byte, a Value, b Value) Value {
+	// dividing ints results in a float, like in Python 3
+	if a.Type() == TypeInt && b.Type() == TypeInt && operator != '/' {
+		x, y := a.IntVal(), b.IntVal()
+		switch operator {
+		case '+':
+			return Int(x + y)
+		case '-':
+			return Int(x - y)
+		case '*':
+			return Int(x * y)
+		case '%':
+			if y == 0 {
+				return None()
+			}
+			return Int(x % y)
+		}
+	}
+	x, y := toFloat(a), toFloat(b)
+	switch operator {
+	case '+':
+		return Float(x + y)
+	case '-':
+		return Float(x - y)
+	case '*':
+		return Float(x * y)
+	case '/':
+		if y == 0 {
+			return None()
+		}
+		return Float(x / y)
+	case '%':
+		if y == 0 {
+			return None()
+		}
+		return Float(math.Mod(x, y))
+	}
+	panic("Unknown operator: " + string(operator))
+}
+
+// timeArithmetic combines times and durations, and multiplies and divides
+// durations by numbers. ok is false if the operator can't be used with a
+// and b.
+func timeArithmetic(operator byte, a Value, b Value) (v Value, ok bool) {
+	ta, tb := a.Type(), b.Type()
+	switch {
+	case ta == TypeTime && tb == TypeTime && operator == '-':
+		return Duration(a.TimeVal().Sub(b.TimeVal())), true
+	case ta == TypeTime && tb == TypeDuration && operator == '+':
+		return Time(a.TimeVal().Add(b.DurationVal())), true
+	case ta == TypeTime && tb == TypeDuration && operator == '-':
+		return Time(a.TimeVal().Add(-b.DurationVal())), true
+	case ta == TypeDuration && tb == TypeTime && operator == '+':
+		return Time(b.TimeVal().Add(a.DurationVal())), true
+	case ta == TypeDuration && tb == TypeDuration:
+		x, y := a.DurationVal(), b.DurationVal()
+		switch operator {
+		case '+':
+			return Duration(x + y), true
+		case '-':
+			return Duration(x - y), true
+		case '/':
+			if y == 0 {
+				return None(), true
+			}
+			return Float(float64(x) / float64(y)), true
+		}
+	case ta == TypeDuration && isNumber(b):
+		x, y := float64(a.DurationVal()), toFloat(b)
+		switch operator {
+		case '*':
+			return Duration(time.Duration(x * y)), true
+		case '/':
+			if y == 0 {
+				return None(), true
+			}
+			return Duration(time.Duration(x / y)), true
+		}
+	case isNumber(a) && tb == TypeDuration && operator == '*':
+		return Duration(time.Duration(toFloat(a) * float64(b.DurationVal()))), true
+	}
+	return None(), false
+}
+
+func abs(v Value) (Value, error) {
+	switch v.Type() {
+	case TypeInt:
+		if v.IntVal() < 0 {
+			return Int(-v.IntVal()), nil
+		}
+		return v, nil
+	case TypeFloat:
+		return Float(math.Abs(v.FloatVal())), nil
+	case TypeDuration:
+		if v.DurationVal() < 0 {
+			return Duration(-v.DurationVal()), nil
+		}
+		return v, nil
+	}
+	return None(), fmt.Errorf("Cannot use abs() on a value of type %s", v.Type())
+}
+
+func round(v Value) (Value, error) {
+	switch v.Type() {
+	case TypeInt:
+		return v, nil
+	case TypeFloat:
+		f := math.Round(v.FloatVal())
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return Float(f), nil
+		}
+		return Int(int64(f)), nil
+	}
+	return None(), fmt.Errorf("Cannot use round() on a value of type %s", v.Type())
+}
+
+// floatFunction returns a function that applies fn to numbers
+func floatFunction(name string, fn func(float64) float64) func(Value) (Value, error) {
+	return func(v Value) (Value, error) {
+		if !isNumber(v) {
+			return None(), fmt.Errorf("Cannot use %s() on a value of type %s", name, v.Type())
+		}
+		return Float(fn(toFloat(v))), nil
+	}
+}
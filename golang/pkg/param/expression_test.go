@@ -0,0 +1,147 @@
+package param
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	created := time.Date(2021, 3, 15, 12, 0, 0, 0, time.UTC)
+	obj := testObject{
+		"train_acc":        Float(0.9),
+		"val_acc":          Float(0.75),
+		"tokens_per_sec":   Int(1000),
+		"gpus":             Int(8),
+		"metric-1":         Float(0.5),
+		"optimizer.lr":     Float(0.01),
+		"layers[0].units":  Int(64),
+		"max(val_acc)":     Float(0.8),
+		"count()":          Int(3),
+		"model":            String("resnet"),
+		"size":             String("50"),
+		"created":          Time(created),
+		"duration":         Duration(2 * time.Hour),
+		"1st_layer_units":  Int(32),
+		"missing_metric_2": None(),
+	}
+	for _, tt := range []struct {
+		input    string
+		expected Value
+	}{
+		{"train_acc - val_acc", Float(0.9 - 0.75)},
+		{"tokens_per_sec * gpus", Int(8000)},
+		{"tokens_per_sec / gpus", Float(125)},
+		{"tokens_per_sec % 3", Int(1)},
+		{"1 + 2 * 3", Int(7)},
+		{"(1 + 2) * 3", Int(9)},
+		{"10 - 4 - 3", Int(3)},
+		{"-gpus", Int(-8)},
+		{"- -gpus", Int(8)},
+		{"2 * -1.5", Float(-3)},
+		{"1e-3 * 1000", Float(1)},
+		{".5 + 1", Float(1.5)},
+		{`"metric-1" * 2`, Float(1)},
+		{"optimizer.lr * 10", Float(0.1)},
+		{"layers[0].units + 1", Int(65)},
+		{"1st_layer_units", Int(32)},
+		{"max(val_acc) - val_acc", Float(0.8 - 0.75)},
+		{"MAX(val_acc)", Float(0.8)},
+		{"count() * 2", Int(6)},
+		{"model + size", String("resnet50")},
+		{"abs(val_acc - train_acc)", Float(0.9 - 0.75)},
+		{"abs(-gpus)", Int(8)},
+		{"sqrt(gpus * 2)", Float(4)},
+		{"round(train_acc * 10)", Int(9)},
+		{"exp(0)", Float(1)},
+		{"log(exp(2))", Float(2)},
+		{"duration / 2", Duration(time.Hour)},
+		{"duration * 1.5", Duration(3 * time.Hour)},
+		{"duration / duration", Float(1)},
+		{"-duration", Duration(-2 * time.Hour)},
+		{"created + duration", Time(created.Add(2 * time.Hour))},
+		{"created - created", Duration(0)},
+		{"missing + 1", None()},
+		{"missing_metric_2 * gpus", None()},
+		{"abs(missing)", None()},
+		{"gpus / 0", None()},
+		{"gpus % 0", None()},
+		{"duration / 0", None()},
+	} {
+		e, err := ParseExpression(tt.input)
+		require.NoError(t, err, tt.input)
+		actual, err := e.Evaluate(obj)
+		require.NoError(t, err, tt.input)
+		if tt.expected.Type() == TypeFloat && actual.Type() == TypeFloat {
+			require.InDelta(t, tt.expected.FloatVal(), actual.FloatVal(), 1e-9, tt.input)
+		} else {
+			require.Equal(t, tt.expected, actual, tt.input)
+		}
+	}
+
+	e, err := ParseExpression("log(-1)")
+	require.NoError(t, err)
+	actual, err := e.Evaluate(obj)
+	require.NoError(t, err)
+	require.True(t, math.IsNaN(actual.FloatVal()))
+}
+
+func TestEvaluateExpressionErrors(t *testing.T) {
+	obj := testObject{
+		"model":   String("resnet"),
+		"gpus":    Int(8),
+		"enabled": Bool(true),
+		"created": Time(time.Now()),
+	}
+	for _, tt := range []struct {
+		input   string
+		message string
+	}{
+		{"model - gpus", `Error evaluating model - gpus: Cannot use "-" with values of type string and int`},
+		{"enabled + 1", `Error evaluating enabled + 1: Cannot use "+" with values of type bool and int`},
+		{"created + created", `Error evaluating created + created: Cannot use "+" with values of type time and time`},
+		{"-model", "Error evaluating -model: Cannot negate a value of type string"},
+		{"sqrt(model)", "Error evaluating sqrt(model): Cannot use sqrt() on a value of type string"},
+	} {
+		e, err := ParseExpression(tt.input)
+		require.NoError(t, err, tt.input)
+		_, err = e.Evaluate(obj)
+		require.EqualError(t, err, tt.message)
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	for _, tt := range []struct {
+		input   string
+		column  int
+		message string
+	}{
+		{"", 1, "Expected a name or a number"},
+		{"train_acc -", 12, "Expected a name or a number"},
+		{"train_acc val_acc", 11, "Expected an operator or the end of the expression"},
+		{"(train_acc - val_acc", 21, `Missing ")" to close the "(" in column 1`},
+		{"train_acc)", 10, `Unexpected ")"`},
+		{"abs(train_acc val_acc)", 15, `Expected an operator or ")"`},
+		{"median(val_acc)", 1, `Unknown function "median", it must be one of abs, sqrt, log, exp, round, min, max, mean, first, last or count`},
+		{"max()", 5, "Expected a name in max()"},
+		{`"metric-1 * 2`, 1, "Unterminated quoted string"},
+		{"layers[0.units", 15, `Missing "]" to close the "[" in column 7`},
+		{"a * * b", 5, "Expected a name or a number"},
+	} {
+		_, err := ParseExpression(tt.input)
+		require.Error(t, err, tt.input)
+		parseErr, ok := err.(*ParseError)
+		require.True(t, ok, tt.input)
+		require.Equal(t, tt.message, parseErr.Message, tt.input)
+		require.Equal(t, tt.column, parseErr.Column(), tt.input)
+		require.Contains(t, err.Error(), "Failed to parse expression", tt.input)
+	}
+}
+
+func TestExpressionNames(t *testing.T) {
+	e, err := ParseExpression(`abs(train_acc - val_acc) / max(val_acc) + "metric-1" * train_acc`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"max(val_acc)", "metric-1", "train_acc", "val_acc"}, e.Names())
+}
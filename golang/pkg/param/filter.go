@@ -4,9 +4,6 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-
-	"github.com/araddon/dateparse"
-	"github.com/replicate/keepsake/golang/pkg/console"
 )
 
 type ValueGetter interface {
@@ -14,13 +11,40 @@ type ValueGetter interface {
 }
 
 type Filters struct {
-	filters []*filter
+	exprs []expr
+}
+
+// expr is a filter expression, either a single filter or filters combined
+// with and, or and not
+type expr interface {
+	matches(obj ValueGetter) (bool, error)
+	// hasName returns true if the expression filters on name
+	hasName(name string) bool
+}
+
+type andExpr struct {
+	left, right expr
+}
+
+type orExpr struct {
+	left, right expr
+}
+
+type notExpr struct {
+	expr expr
 }
 
 type filter struct {
 	name     string
 	operator Operator
 	value    Value
+	// pattern is the compiled regular expression of the ~, !~ and glob
+	// operators, so it isn't compiled for every experiment
+	pattern *regexp.Regexp
+	// texts are the value of the contains operator, or the items in the
+	// list of the in and not in operators, as they were written in the
+	// filter, so strings like "1.10" aren't changed to the number 1.1
+	texts []string
 }
 
 type Operator int
@@ -32,10 +56,21 @@ const (
 	OperatorGreaterOrEqual
 	OperatorLessThan
 	OperatorLessOrEqual
+	// OperatorMatches matches values against a regular expression
+	OperatorMatches
+	OperatorNotMatches
+	// OperatorGlob matches values against a shell-style pattern like "resnet*"
+	OperatorGlob
+	// OperatorIn matches values that are equal to any of the values in a list
+	OperatorIn
+	OperatorNotIn
+	// OperatorContains matches strings that contain a substring, lists that
+	// contain a value, and objects that contain a key
+	OperatorContains
 )
 
-var parseRegex = regexp.MustCompile("^([-a-zA-Z0-9_ ]*[-a-zA-Z0-9_]+) *([<>=!]+) *(.+)$")
-
+// MakeFilters parses filter expressions. An object matches the filters if
+// it matches all of the expressions.
 func MakeFilters(strings []string) (*Filters, error) {
 	filters := &Filters{}
 	for _, s := range strings {
@@ -47,35 +82,35 @@ func MakeFilters(strings []string) (*Filters, error) {
 }
 
 // SetExclusive sets a filter exclusively, deleting any previous
-// filters with that name
+// filter expressions that use that name
 func (fs *Filters) SetExclusive(name string, operator Operator, value Value) {
-	filters := []*filter{{
+	exprs := []expr{&filter{
 		name:     name,
 		operator: operator,
 		value:    value,
 	}}
-	for _, f := range fs.filters {
-		if f.name != name {
-			filters = append(filters, f)
+	for _, e := range fs.exprs {
+		if !e.hasName(name) {
+			exprs = append(exprs, e)
 		}
 	}
-	fs.filters = filters
+	fs.exprs = exprs
 }
 
 func (fs *Filters) appendParsed(s string) error {
-	f, err := parse(s)
+	e, err := parse(s)
 	if err != nil {
 		return err
 	}
-	fs.filters = append(fs.filters, f)
+	fs.exprs = append(fs.exprs, e)
 	return nil
 }
 
 func (fs *Filters) Matches(obj ValueGetter) (bool, error) {
-	for _, f := range fs.filters {
-		match, err := f.matches(obj)
+	for _, e := range fs.exprs {
+		match, err := e.matches(obj)
 		if err != nil {
-			return false, fmt.Errorf("Error applying filter to %s: %s", f.name, err)
+			return false, err
 		}
 		if !match {
 			return false, nil
@@ -84,8 +119,72 @@ func (fs *Filters) Matches(obj ValueGetter) (bool, error) {
 	return true, nil
 }
 
+func (e *andExpr) matches(obj ValueGetter) (bool, error) {
+	match, err := e.left.matches(obj)
+	if err != nil || !match {
+		return false, err
+	}
+	return e.right.matches(obj)
+}
+
+func (e *andExpr) hasName(name string) bool {
+	return e.left.hasName(name) || e.right.hasName(name)
+}
+
+func (e *orExpr) matches(obj ValueGetter) (bool, error) {
+	match, err := e.left.matches(obj)
+	if err != nil || match {
+		return match, err
+	}
+	return e.right.matches(obj)
+}
+
+func (e *orExpr) hasName(name string) bool {
+	return e.left.hasName(name) || e.right.hasName(name)
+}
+
+func (e *notExpr) matches(obj ValueGetter) (bool, error) {
+	match, err := e.expr.matches(obj)
+	if err != nil {
+		return false, err
+	}
+	return !match, nil
+}
+
+func (e *notExpr) hasName(name string) bool {
+	return e.expr.hasName(name)
+}
+
+func (f *filter) hasName(name string) bool {
+	return f.name == name
+}
+
 func (f *filter) matches(obj ValueGetter) (bool, error) {
-	value := obj.GetValue(f.name)
+	match, err := f.compare(obj.GetValue(f.name))
+	if err != nil {
+		return false, fmt.Errorf("Error applying filter to %s: %s", f.name, err)
+	}
+	return match, nil
+}
+
+func (f *filter) compare(value Value) (bool, error) {
+	switch f.operator {
+	case OperatorMatches, OperatorGlob:
+		return matchesPattern(value, f.pattern), nil
+	case OperatorNotMatches:
+		return !matchesPattern(value, f.pattern), nil
+	case OperatorIn:
+		return isIn(value, f.value, f.texts), nil
+	case OperatorNotIn:
+		return !isIn(value, f.value, f.texts), nil
+	case OperatorContains:
+		text := f.value.String()
+		if len(f.texts) > 0 {
+			text = f.texts[0]
+		}
+		return contains(value, f.value, text)
+	}
+
 	if f.value.IsNone() {
 		if f.operator == OperatorEqual {
 			return value.IsNone(), nil
@@ -110,57 +209,160 @@ func (f *filter) matches(obj ValueGetter) (bool, error) {
 	panic("Unknown operator")
 }
 
-func parse(s string) (*filter, error) {
-	parseErr := fmt.Errorf(`Failed to parse filter: "%s".
-
-Filters must be in the format "<name> <operator> <value>",
-where <operator> can be
-  "=" (equal),
-  "!=" (not equal),
-  "<" (less than),
-  "<=" (less than or equal),
-  ">" (greater than), or
-  ">=" (greater than or equal)`, s)
-
-	s = strings.TrimSpace(s)
-	matches := parseRegex.FindStringSubmatch(s)
-	if len(matches) == 0 {
-		return nil, parseErr
-	}
-	name := matches[1]
-	operator := matches[2]
-	value := matches[3]
-
-	f := filter{name: name}
-	switch operator {
-	case "=":
-		f.operator = OperatorEqual
-	case "!=":
-		f.operator = OperatorNotEqual
-	case "<":
-		f.operator = OperatorLessThan
-	case "<=":
-		f.operator = OperatorLessOrEqual
-	case ">":
-		f.operator = OperatorGreaterThan
-	case ">=":
-		f.operator = OperatorGreaterOrEqual
-	default:
-		return nil, parseErr
-	}
-
-	// This is a hack, see https://github.com/replicate/keepsake/issues/341
-	if f.name == "started" || f.name == "created" {
-		if f.name == "started" {
-			console.Warn("The filter name 'started' is deprecated, please use 'created' instead")
+// matchesPattern returns true if the regular expression re matches part of
+// value. Numbers and booleans are matched in their JSON form, and lists
+// match if any of their items match.
+func matchesPattern(value Value, re *regexp.Regexp) bool {
+	if value.IsNone() {
+		return false
+	}
+	if items, ok := listItems(value); ok {
+		for _, item := range items {
+			if v := fromInterface(item); !v.IsNone() && re.MatchString(v.String()) {
+				return true
+			}
 		}
-		t, err := dateparse.ParseLocal(value)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to parse created time: %s", err)
+		return false
+	}
+	return re.MatchString(value.String())
+}
+
+// compilePattern compiles the regular expression of the ~, !~ and glob
+// operators from the pattern as it was written in the filter
+func compilePattern(operator Operator, pattern string) (*regexp.Regexp, error) {
+	if operator == OperatorGlob {
+		return regexp.Compile(globToRegexp(pattern))
+	}
+	return regexp.Compile(pattern)
+}
+
+// globToRegexp converts a shell-style pattern to a regular expression that
+// matches a whole string. "*" matches any sequence of characters, including
+// "/", "?" matches any single character, "[...]" matches a character class,
+// and a backslash escapes the next character.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("(?s)^")
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := i + 1
+			if end < len(runes) && (runes[end] == '!' || runes[end] == '^') {
+				end++
+			}
+			if end < len(runes) && runes[end] == ']' {
+				end++
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				// no closing bracket, so match it literally
+				b.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := runes[i+1 : end]
+			b.WriteString("[")
+			if len(class) > 0 && class[0] == '!' {
+				b.WriteString("^")
+				class = class[1:]
+			}
+			b.WriteString(strings.ReplaceAll(string(class), `\`, `\\`))
+			b.WriteString("]")
+			i = end
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// isIn returns true if value is equal to any of the items in list. If
+// itemTexts are the items as they were written, strings are also equal to
+// items that were written the same, so "1.10" is in [1.10].
+func isIn(value Value, list Value, itemTexts []string) bool {
+	items, _ := listItems(list)
+	for i, item := range items {
+		if looselyEqual(value, item) {
+			return true
+		}
+		if i < len(itemTexts) && value.Type() == TypeString && value.StringVal() == itemTexts[i] {
+			return true
 		}
-		f.value = Float(float64(t.Unix()))
-	} else {
-		f.value = ParseFromString(value)
 	}
-	return &f, nil
+	return false
+}
+
+// contains returns true if a string contains item as a substring, a list
+// contains item, or an object has item as a key. text is item as it was
+// written, which substrings and keys are matched with. Numbers and booleans
+// are strings in their JSON form, so "step contains 5" matches 5, 15 and 50.
+func contains(value Value, item Value, text string) (bool, error) {
+	switch value.Type() {
+	case TypeNone:
+		return false, nil
+	case TypeString, TypeInt, TypeFloat, TypeBool:
+		return strings.Contains(value.String(), text), nil
+	case TypeObject:
+		if items, ok := listItems(value); ok {
+			for _, obj := range items {
+				v := fromInterface(obj)
+				if looselyEqual(v, item) || v.Type() == TypeString && v.StringVal() == text {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		if m, ok := value.ObjectVal().(map[string]interface{}); ok {
+			_, ok := m[text]
+			return ok, nil
+		}
+	}
+	return false, fmt.Errorf("Cannot use contains on a value of type %s", value.Type())
+}
+
+// listItems returns the items in value if it is a list
+func listItems(value Value) ([]interface{}, bool) {
+	if value.Type() != TypeObject {
+		return nil, false
+	}
+	items, ok := value.ObjectVal().([]interface{})
+	return items, ok
+}
+
+// looselyEqual compares values like Value.Equal, except that ints are equal
+// to floats with the same value, and values of different types aren't an
+// error. Either value can be an item of an object.
+func looselyEqual(a interface{}, b interface{}) bool {
+	va, vb := fromInterface(a), fromInterface(b)
+	if isNumber(va) && isNumber(vb) {
+		return toFloat(va) == toFloat(vb)
+	}
+	if va.Type() != vb.Type() {
+		return false
+	}
+	equal, err := va.Equal(vb)
+	return err == nil && equal
+}
+
+func isNumber(v Value) bool {
+	return v.Type() == TypeInt || v.Type() == TypeFloat
+}
+
+func toFloat(v Value) float64 {
+	if v.Type() == TypeInt {
+		return float64(v.IntVal())
+	}
+	return v.FloatVal()
 }
@@ -0,0 +1,646 @@
+package param
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+)
+
+// Filters are parsed with this grammar, where keywords are case insensitive:
+//
+//   expression = or
+//   or         = and { "or" and }
+//   and        = not { "and" not }
+//   not        = "not" not | primary
+//   primary    = "(" expression ")" | clause
+//   clause     = name operator value | name ( "in" | "not in" ) list
+//   name       = path | function "(" [ path ] ")"
+//   list       = "[" [ value { "," value } ] "]"
+//
+// Names and values can be quoted with double or single quotes, and a
+// backslash escapes the next character inside quotes. Quoted values are
+// always strings. Unquoted values are parsed with ParseFromString() and run
+// until the end of the filter, a closing parenthesis, or "and" or "or"
+// followed by another clause, so existing filters like "optimizer = adam"
+// and "command = python train.py and eval" keep working. Items in lists
+// don't need to be quoted either, e.g. "model in [resnet50, vit]". Patterns,
+// substrings and list items are also matched against strings as they were
+// written, so "version glob 1.10" and "version in [1.10]" don't match 1.1.
+
+const filterHelp = `Filters must be in the format "<name> <operator> <value>",
+where <operator> can be
+  "=" (equal),
+  "!=" (not equal),
+  "<" (less than),
+  "<=" (less than or equal),
+  ">" (greater than),
+  ">=" (greater than or equal),
+  "~" (matches a regular expression),
+  "!~" (doesn't match a regular expression),
+  "glob" (matches a pattern like "resnet*"),
+  "in" (equal to any value in a list like [resnet50, vit]),
+  "not in" (not equal to any value in a list), or
+  "contains" (a string or number contains a substring, or a list contains a value).
+
+Filters can be combined with "and", "or", "not" and parentheses, e.g.
+  (optimizer = adam or optimizer = adamw) and not status = running
+
+Names and values that contain spaces or special characters can be quoted,
+e.g. "learning rate" > 0.01
+
+Metrics can be aggregated over all the checkpoints of an experiment with
+min, max, mean, first, last and count, e.g. max(val_acc) > 0.9
+
+"created" can be compared to dates like 2020-01-31, relative times like
+"3 days ago", and now, today, yesterday, this week, last week, this month,
+last month, this year or last year. "duration" is the time from when an
+experiment was created to its last checkpoint or heartbeat, and can be
+compared to durations like "2h30m" or "3 days".`
+
+var operators = map[string]Operator{
+	"=":  OperatorEqual,
+	"!=": OperatorNotEqual,
+	"<":  OperatorLessThan,
+	"<=": OperatorLessOrEqual,
+	">":  OperatorGreaterThan,
+	">=": OperatorGreaterOrEqual,
+	"~":  OperatorMatches,
+	"!~": OperatorNotMatches,
+}
+
+// wordOperators are operators made of keywords, which must be separated
+// from names by spaces. Each is a list of keywords, longest first.
+var wordOperators = []struct {
+	keywords []string
+	operator Operator
+}{
+	{[]string{"not", "in"}, OperatorNotIn},
+	{[]string{"in"}, OperatorIn},
+	{[]string{"glob"}, OperatorGlob},
+	{[]string{"contains"}, OperatorContains},
+}
+
+// ParseError is returned when a filter can't be parsed
+type ParseError struct {
+	// Input is the filter that failed to parse
+	Input string
+	// Pos is the byte offset in Input where the error is
+	Pos     int
+	Message string
+	// expression is true if Input is an expression rather than a filter
+	expression bool
+}
+
+// Column returns the position of the error in characters, starting at 1
+func (e *ParseError) Column() int {
+	return utf8.RuneCountInString(e.Input[:e.Pos]) + 1
+}
+
+func (e *ParseError) Error() string {
+	what, help := "filter", filterHelp
+	if e.expression {
+		what, help = "expression", expressionHelp
+	}
+	return fmt.Sprintf(`Failed to parse %s: %s at position %d:
+
+  %s
+  %s^
+
+%s`, what, e.Message, e.Column(), e.Input, strings.Repeat(" ", e.Column()-1), help)
+}
+
+type filterParser struct {
+	input string
+	pos   int
+	// depth is the number of parentheses that are open
+	depth int
+	// expression is true when parsing an expression, see expression.go
+	expression bool
+	// speculative is true when trying whether the rest of the input is a
+	// clause, so nothing is logged
+	speculative bool
+	// parsedOperator is true once the operator of a clause has been parsed
+	parsedOperator bool
+}
+
+func parse(s string) (expr, error) {
+	p := &filterParser{input: s}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.atEnd() {
+		if p.peek() == ')' {
+			return nil, p.errorf(`Unexpected ")"`)
+		}
+		return nil, p.errorf(`Expected "and", "or" or the end of the filter`)
+	}
+	return e, nil
+}
+
+func (p *filterParser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (expr, error) {
+	p.skipSpace()
+	// "not" followed by an operator is a name, e.g. "not = 1"
+	if end, ok := p.keywordAt(p.pos, "not"); ok && !isOperatorChar(p.peekAfterSpace(end)) {
+		p.pos = end
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: e}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *filterParser) parsePrimary() (expr, error) {
+	p.skipSpace()
+	if p.peek() != '(' {
+		return p.parseClause()
+	}
+	open := p.pos
+	p.pos++
+	p.depth++
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.peek() != ')' {
+		if p.atEnd() {
+			return nil, p.errorf(`Missing ")" to close the "(" in column %d`, (&ParseError{Input: p.input, Pos: open}).Column())
+		}
+		return nil, p.errorf(`Expected "and", "or" or ")"`)
+	}
+	p.pos++
+	p.depth--
+	return e, nil
+}
+
+func (p *filterParser) parseClause() (expr, error) {
+	p.skipSpace()
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	start := p.pos
+	operator, err := p.parseOperator(name)
+	if err != nil {
+		return nil, err
+	}
+	operatorString := strings.Join(strings.Fields(p.input[start:p.pos]), " ")
+	p.parsedOperator = true
+
+	p.skipSpace()
+	valuePos := p.pos
+	var value Value
+	var text string
+	var texts []string
+	if operator == OperatorIn || operator == OperatorNotIn {
+		if p.peek() != '[' {
+			return nil, p.errorf("Expected a list like [a, b] after %q", operatorString)
+		}
+		value, texts, err = p.parseList()
+	} else {
+		value, text, err = p.parseValue()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// patterns are always strings, so "version glob 1.10" isn't parsed
+	// as the number 1.1
+	var pattern *regexp.Regexp
+	switch operator {
+	case OperatorMatches, OperatorNotMatches:
+		value = String(text)
+		if pattern, err = compilePattern(operator, text); err != nil {
+			return nil, p.errorfAt(valuePos, "Invalid regular expression: %s", err)
+		}
+	case OperatorGlob:
+		value = String(text)
+		if pattern, err = compilePattern(operator, text); err != nil {
+			return nil, p.errorfAt(valuePos, "Invalid pattern: %s", err)
+		}
+	case OperatorContains:
+		texts = []string{text}
+	}
+
+	if err := checkTimeValue(name, operator, value); err != nil {
+		return nil, p.errorfAt(valuePos, "%s", err)
+	}
+
+	if name == "started" && !p.speculative {
+		console.Warn("The filter name 'started' is deprecated, please use 'created' instead")
+	}
+	return &filter{name: name, operator: operator, value: value, pattern: pattern, texts: texts}, nil
+}
+
+func (p *filterParser) parseName() (string, error) {
+	if isQuote(p.peek()) {
+		return p.parseQuoted()
+	}
+	start := p.pos
+	for !p.atEnd() {
+		c := p.peek()
+		if c == '(' && p.pos > start && !isSpace(p.input[p.pos-1]) {
+			return p.parseAggregation(start)
+		}
+		if isOperatorChar(c) || isQuote(c) || c == '(' || c == ')' {
+			break
+		}
+		if isSpace(c) && p.pos > start {
+			if _, ok := p.wordOperatorAt(p.pos); ok {
+				break
+			}
+		}
+		p.pos++
+	}
+	name := strings.TrimSpace(p.input[start:p.pos])
+	if name == "" {
+		if p.atEnd() {
+			return "", p.errorfAt(start, "Expected a filter")
+		}
+		return "", p.errorfAt(start, "Expected a name")
+	}
+	return name, nil
+}
+
+// parseAggregation parses a name like "max(val_acc)", where the function
+// name starts at start and the "(" is next in the input
+func (p *filterParser) parseAggregation(start int) (string, error) {
+	function := strings.TrimSpace(p.input[start:p.pos])
+	agg := Aggregation(strings.ToLower(function))
+	if !aggregations[agg] {
+		return "", p.errorfAt(start, "Unknown function %q, it must be one of min, max, mean, first, last or count", function)
+	}
+	open := p.pos
+	p.pos++
+	p.skipSpace()
+	argStart := p.pos
+	if isQuote(p.peek()) {
+		if _, err := p.parseQuoted(); err != nil {
+			return "", err
+		}
+	} else {
+		for !p.atEnd() && p.peek() != ')' {
+			p.pos++
+		}
+	}
+	arg := strings.TrimSpace(p.input[argStart:p.pos])
+	p.skipSpace()
+	if p.peek() != ')' {
+		if p.atEnd() {
+			return "", p.errorf(`Missing ")" to close the "(" in column %d`, (&ParseError{Input: p.input, Pos: open}).Column())
+		}
+		return "", p.errorf(`Expected ")"`)
+	}
+	p.pos++
+	if arg == "" && agg != AggregationCount {
+		return "", p.errorfAt(open+1, "Expected a name in %s()", agg)
+	}
+	return string(agg) + "(" + arg + ")", nil
+}
+
+// parseValue parses a value, and also returns it as it was written, without
+// any quotes
+func (p *filterParser) parseValue() (Value, string, error) {
+	if isQuote(p.peek()) {
+		s, err := p.parseQuoted()
+		if err != nil {
+			return Value{}, "", err
+		}
+		return String(s), s, nil
+	}
+
+	start := p.pos
+	// depth of JSON brackets, inside which "and", "or" and ")" are part of
+	// the value
+	brackets := 0
+	inString := false
+	for !p.atEnd() {
+		c := p.peek()
+		if inString {
+			if c == '\\' {
+				p.pos++
+			} else if c == '"' {
+				inString = false
+			}
+			p.pos++
+			continue
+		}
+		if brackets > 0 {
+			switch c {
+			case '"':
+				inString = true
+			case '[', '{':
+				brackets++
+			case ']', '}':
+				brackets--
+			}
+			p.pos++
+			continue
+		}
+		if c == '[' || c == '{' {
+			brackets++
+		} else if c == ')' && p.depth > 0 {
+			break
+		} else if isSpace(c) {
+			end := p.pos
+			for end < len(p.input) && isSpace(p.input[end]) {
+				end++
+			}
+			if p.clauseAfterKeyword(end, "and") || p.clauseAfterKeyword(end, "or") {
+				break
+			}
+		}
+		p.pos++
+	}
+	text := strings.TrimSpace(p.input[start:p.pos])
+	if text == "" {
+		return Value{}, "", p.errorfAt(start, "Expected a value")
+	}
+	return ParseFromString(text), text, nil
+}
+
+// clauseAfterKeyword returns true if keyword is at pos in the input and is
+// followed by another clause. Nothing, a "(", or a name and operator after
+// the keyword are always the start of another clause, so errors in it are
+// reported. Otherwise, a value like "python train.py and eval" contains the
+// keyword.
+func (p *filterParser) clauseAfterKeyword(pos int, keyword string) bool {
+	end, ok := p.keywordAt(pos, keyword)
+	if !ok {
+		return false
+	}
+	if c := p.peekAfterSpace(end); c == 0 || c == '(' {
+		return true
+	}
+	trial := *p
+	trial.pos = end
+	trial.speculative = true
+	trial.parsedOperator = false
+	_, err := trial.parseNot()
+	return err == nil || trial.parsedOperator
+}
+
+// parseOperator parses the operator after the name of a clause
+func (p *filterParser) parseOperator(name string) (Operator, error) {
+	start := p.pos
+	for !p.atEnd() && isOperatorChar(p.peek()) {
+		p.pos++
+	}
+	if start != p.pos {
+		operator, ok := operators[p.input[start:p.pos]]
+		if !ok {
+			return 0, p.errorfAt(start, "Unknown operator %q", p.input[start:p.pos])
+		}
+		return operator, nil
+	}
+	if end, ok := p.wordOperatorAt(p.pos); ok {
+		operator := wordOperators[end.index].operator
+		p.pos = end.pos
+		return operator, nil
+	}
+	return 0, p.errorf("Expected an operator after %q", name)
+}
+
+type wordOperatorEnd struct {
+	index int
+	pos   int
+}
+
+// wordOperatorAt returns the index in wordOperators and the end of the
+// word operator after any spaces at pos, if there is one
+func (p *filterParser) wordOperatorAt(pos int) (wordOperatorEnd, bool) {
+	for pos < len(p.input) && isSpace(p.input[pos]) {
+		pos++
+	}
+	for i, w := range wordOperators {
+		end := pos
+		ok := true
+		for j, keyword := range w.keywords {
+			if j > 0 {
+				for end < len(p.input) && isSpace(p.input[end]) {
+					end++
+				}
+			}
+			if end, ok = p.keywordAt(end, keyword); !ok {
+				break
+			}
+		}
+		if ok {
+			return wordOperatorEnd{index: i, pos: end}, true
+		}
+	}
+	return wordOperatorEnd{}, false
+}
+
+// parseList parses a list of values in square brackets. Items are parsed
+// like values, except they are also separated by commas. It also returns
+// the items as they were written.
+func (p *filterParser) parseList() (Value, []string, error) {
+	open := p.pos
+	p.pos++
+	items := []interface{}{}
+	texts := []string{}
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.pos++
+		return Object(items), texts, nil
+	}
+	for {
+		p.skipSpace()
+		item, text, err := p.parseListItem()
+		if err != nil {
+			return Value{}, nil, err
+		}
+		items = append(items, item)
+		texts = append(texts, text)
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return Object(items), texts, nil
+		default:
+			if p.atEnd() {
+				return Value{}, nil, p.errorf(`Missing "]" to close the "[" in column %d`, (&ParseError{Input: p.input, Pos: open}).Column())
+			}
+			return Value{}, nil, p.errorf(`Expected "," or "]"`)
+		}
+	}
+}
+
+func (p *filterParser) parseListItem() (Value, string, error) {
+	if isQuote(p.peek()) {
+		s, err := p.parseQuoted()
+		if err != nil {
+			return Value{}, "", err
+		}
+		return String(s), s, nil
+	}
+	start := p.pos
+	brackets := 0
+	inString := false
+	for !p.atEnd() {
+		c := p.peek()
+		if inString {
+			if c == '\\' {
+				p.pos++
+			} else if c == '"' {
+				inString = false
+			}
+		} else if c == '"' && brackets > 0 {
+			inString = true
+		} else if c == '[' || c == '{' {
+			brackets++
+		} else if (c == ']' || c == '}') && brackets > 0 {
+			brackets--
+		} else if (c == ',' || c == ']') && brackets == 0 {
+			break
+		}
+		p.pos++
+	}
+	text := strings.TrimSpace(p.input[start:p.pos])
+	if text == "" {
+		return Value{}, "", p.errorfAt(start, "Expected a value")
+	}
+	return ParseFromString(text), text, nil
+}
+
+// parseQuoted parses a string in double or single quotes
+func (p *filterParser) parseQuoted() (string, error) {
+	start := p.pos
+	quote := p.peek()
+	p.pos++
+	var b strings.Builder
+	for !p.atEnd() {
+		c := p.peek()
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && p.pos+1 < len(p.input):
+			b.WriteByte(p.input[p.pos+1])
+			p.pos += 2
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorfAt(start, "Unterminated quoted string")
+}
+
+// acceptKeyword skips over keyword if it's next in the input
+func (p *filterParser) acceptKeyword(keyword string) bool {
+	p.skipSpace()
+	end, ok := p.keywordAt(p.pos, keyword)
+	if ok {
+		p.pos = end
+	}
+	return ok
+}
+
+// keywordAt returns the end of keyword if it's at pos in the input, as a
+// whole word
+func (p *filterParser) keywordAt(pos int, keyword string) (end int, ok bool) {
+	end = pos + len(keyword)
+	if end > len(p.input) || !strings.EqualFold(p.input[pos:end], keyword) {
+		return 0, false
+	}
+	if end < len(p.input) && !isKeywordEnd(p.input[end]) {
+		return 0, false
+	}
+	return end, true
+}
+
+func (p *filterParser) skipSpace() {
+	for !p.atEnd() && isSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *filterParser) peekAfterSpace(pos int) byte {
+	for pos < len(p.input) && isSpace(p.input[pos]) {
+		pos++
+	}
+	if pos == len(p.input) {
+		return 0
+	}
+	return p.input[pos]
+}
+
+func (p *filterParser) atEnd() bool {
+	return p.pos >= len(p.input)
+}
+
+// peek returns the next byte, or 0 at the end of the input
+func (p *filterParser) peek() byte {
+	if p.atEnd() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *filterParser) errorf(format string, a ...interface{}) *ParseError {
+	return p.errorfAt(p.pos, format, a...)
+}
+
+func (p *filterParser) errorfAt(pos int, format string, a ...interface{}) *ParseError {
+	return &ParseError{Input: p.input, Pos: pos, Message: fmt.Sprintf(format, a...), expression: p.expression}
+}
+
+// isKeywordEnd returns true if c can follow a keyword
+func isKeywordEnd(c byte) bool {
+	return isSpace(c) || isQuote(c) || c == '(' || c == '['
+}
+
+func isOperatorChar(c byte) bool {
+	return c == '<' || c == '>' || c == '=' || c == '!' || c == '~'
+}
+
+func isQuote(c byte) bool {
+	return c == '"' || c == '\''
+}
+
+func isSpace(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsSpace(rune(c))
+}
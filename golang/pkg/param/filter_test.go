@@ -1,6 +1,8 @@
 package param
 
 import (
+	"encoding/json"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -11,18 +13,18 @@ func TestParseTypesGood(t *testing.T) {
 		input    string
 		expected filter
 	}{
-		{"foo=0.001", filter{"foo", OperatorEqual, Float(0.001)}},
-		{"foo = bar", filter{"foo", OperatorEqual, String("bar")}},
-		{"f = bar", filter{"f", OperatorEqual, String("bar")}},
-		{" foo=bar ", filter{"foo", OperatorEqual, String("bar")}},
-		{"foo = 1", filter{"foo", OperatorEqual, Int(1)}},
-		{"foo = 1.5", filter{"foo", OperatorEqual, Float(1.5)}},
-		{"foo = true", filter{"foo", OperatorEqual, Bool(true)}},
-		{"foo = false", filter{"foo", OperatorEqual, Bool(false)}},
-		{"foo = null", filter{"foo", OperatorEqual, None()}},
-		{"foo = None", filter{"foo", OperatorEqual, None()}},
-		{`foo = {"foo": "bar"}`, filter{"foo", OperatorEqual, Object(map[string]interface{}{"foo": "bar"})}},
-		{`foo = ["foo", "bar"]`, filter{"foo", OperatorEqual, Object([]interface{}{"foo", "bar"})}},
+		{"foo=0.001", filter{"foo", OperatorEqual, Float(0.001), nil, nil}},
+		{"foo = bar", filter{"foo", OperatorEqual, String("bar"), nil, nil}},
+		{"f = bar", filter{"f", OperatorEqual, String("bar"), nil, nil}},
+		{" foo=bar ", filter{"foo", OperatorEqual, String("bar"), nil, nil}},
+		{"foo = 1", filter{"foo", OperatorEqual, Int(1), nil, nil}},
+		{"foo = 1.5", filter{"foo", OperatorEqual, Float(1.5), nil, nil}},
+		{"foo = true", filter{"foo", OperatorEqual, Bool(true), nil, nil}},
+		{"foo = false", filter{"foo", OperatorEqual, Bool(false), nil, nil}},
+		{"foo = null", filter{"foo", OperatorEqual, None(), nil, nil}},
+		{"foo = None", filter{"foo", OperatorEqual, None(), nil, nil}},
+		{`foo = {"foo": "bar"}`, filter{"foo", OperatorEqual, Object(map[string]interface{}{"foo": "bar"}), nil, nil}},
+		{`foo = ["foo", "bar"]`, filter{"foo", OperatorEqual, Object([]interface{}{"foo", "bar"}), nil, nil}},
 	} {
 		actual, err := parse(tt.input)
 		require.NoError(t, err)
@@ -35,14 +37,14 @@ func TestParseOperatorsGood(t *testing.T) {
 		input    string
 		expected filter
 	}{
-		{"foo = bar", filter{"foo", OperatorEqual, String("bar")}},
-		{"foo != bar", filter{"foo", OperatorNotEqual, String("bar")}},
-		{"foo < bar", filter{"foo", OperatorLessThan, String("bar")}},
-		{"foo <= bar", filter{"foo", OperatorLessOrEqual, String("bar")}},
-		{"foo > bar", filter{"foo", OperatorGreaterThan, String("bar")}},
-		{"foo >= bar", filter{"foo", OperatorGreaterOrEqual, String("bar")}},
-		{"foo foo >= bar", filter{"foo foo", OperatorGreaterOrEqual, String("bar")}},
-		{"foo >= bar bar", filter{"foo", OperatorGreaterOrEqual, String("bar bar")}},
+		{"foo = bar", filter{"foo", OperatorEqual, String("bar"), nil, nil}},
+		{"foo != bar", filter{"foo", OperatorNotEqual, String("bar"), nil, nil}},
+		{"foo < bar", filter{"foo", OperatorLessThan, String("bar"), nil, nil}},
+		{"foo <= bar", filter{"foo", OperatorLessOrEqual, String("bar"), nil, nil}},
+		{"foo > bar", filter{"foo", OperatorGreaterThan, String("bar"), nil, nil}},
+		{"foo >= bar", filter{"foo", OperatorGreaterOrEqual, String("bar"), nil, nil}},
+		{"foo foo >= bar", filter{"foo foo", OperatorGreaterOrEqual, String("bar"), nil, nil}},
+		{"foo >= bar bar", filter{"foo", OperatorGreaterOrEqual, String("bar bar"), nil, nil}},
 	} {
 		actual, err := parse(tt.input)
 		require.NoError(t, err)
@@ -64,3 +66,304 @@ func TestParseBad(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestParseQuoted(t *testing.T) {
+	for _, tt := range []struct {
+		input    string
+		expected filter
+	}{
+		{`"learning rate" > 0.01`, filter{"learning rate", OperatorGreaterThan, Float(0.01), nil, nil}},
+		{`'a=b' = c`, filter{"a=b", OperatorEqual, String("c"), nil, nil}},
+		{`foo = "1"`, filter{"foo", OperatorEqual, String("1"), nil, nil}},
+		{`foo = "rock and roll"`, filter{"foo", OperatorEqual, String("rock and roll"), nil, nil}},
+		{`foo = 'it\'s'`, filter{"foo", OperatorEqual, String("it's"), nil, nil}},
+		{`foo = ""`, filter{"foo", OperatorEqual, String(""), nil, nil}},
+	} {
+		actual, err := parse(tt.input)
+		require.NoError(t, err, tt.input)
+		require.Equal(t, &tt.expected, actual, tt.input)
+	}
+}
+
+func TestParseExpressions(t *testing.T) {
+	adam := &filter{"optimizer", OperatorEqual, String("adam"), nil, nil}
+	adamw := &filter{"optimizer", OperatorEqual, String("adamw"), nil, nil}
+	running := &filter{"status", OperatorEqual, String("running"), nil, nil}
+
+	for _, tt := range []struct {
+		input    string
+		expected expr
+	}{
+		{"optimizer = adam and status = running", &andExpr{adam, running}},
+		{"optimizer = adam AND status = running", &andExpr{adam, running}},
+		{"optimizer = adam or optimizer = adamw", &orExpr{adam, adamw}},
+		{"not status = running", &notExpr{running}},
+		{"not not status = running", &notExpr{&notExpr{running}}},
+		{"(status = running)", running},
+		{"not(status = running)", &notExpr{running}},
+		// and binds tighter than or
+		{"optimizer = adam or optimizer = adamw and status = running", &orExpr{adam, &andExpr{adamw, running}}},
+		{"optimizer = adam and optimizer = adamw or status = running", &orExpr{&andExpr{adam, adamw}, running}},
+		{
+			"(optimizer = adam or optimizer = adamw) and not status = running",
+			&andExpr{&orExpr{adam, adamw}, &notExpr{running}},
+		},
+		{
+			`foo = ["a", "b"] or foo = {"a": "x)"}`,
+			&orExpr{
+				&filter{"foo", OperatorEqual, Object([]interface{}{"a", "b"}), nil, nil},
+				&filter{"foo", OperatorEqual, Object(map[string]interface{}{"a": "x)"}), nil, nil},
+			},
+		},
+		// outside parentheses, ")" is part of a value
+		{"foo = f(x)", &filter{"foo", OperatorEqual, String("f(x)"), nil, nil}},
+		{"notes = bar", &filter{"notes", OperatorEqual, String("bar"), nil, nil}},
+		{"not = bar", &filter{"not", OperatorEqual, String("bar"), nil, nil}},
+		{"foo = android", &filter{"foo", OperatorEqual, String("android"), nil, nil}},
+		// "and" and "or" that aren't followed by a clause are part of a value
+		{"command = python train.py and eval", &filter{"command", OperatorEqual, String("python train.py and eval"), nil, nil}},
+		{"notes = rock or roll", &filter{"notes", OperatorEqual, String("rock or roll"), nil, nil}},
+		{
+			"command = train.py and eval and (status = running)",
+			&andExpr{&filter{"command", OperatorEqual, String("train.py and eval"), nil, nil}, running},
+		},
+	} {
+		actual, err := parse(tt.input)
+		require.NoError(t, err, tt.input)
+		require.Equal(t, tt.expected, actual, tt.input)
+	}
+}
+
+func TestParseErrorPositions(t *testing.T) {
+	for _, tt := range []struct {
+		input   string
+		column  int
+		message string
+	}{
+		{"", 1, "Expected a filter"},
+		{"= bar", 1, "Expected a name"},
+		{"foo", 4, `Expected an operator after "foo"`},
+		{"foo =", 6, "Expected a value"},
+		{"foo >> bar", 5, `Unknown operator ">>"`},
+		{"foo = bar and", 14, "Expected a filter"},
+		{"(foo = bar", 11, `Missing ")" to close the "(" in column 1`},
+		{"(foo = bar))", 12, `Unexpected ")"`},
+		{`foo = "bar" baz`, 13, `Expected "and", "or" or the end of the filter`},
+		{`foo = "bar`, 7, "Unterminated quoted string"},
+		{`a = 1 and (b = "2" c`, 20, `Expected "and", "or" or ")"`},
+		{"größe = 1 or", 13, "Expected a filter"},
+		// a clause after "and" is an error rather than part of the value
+		{"foo = bar and model in vit", 24, `Expected a list like [a, b] after "in"`},
+	} {
+		_, err := parse(tt.input)
+		require.Error(t, err, tt.input)
+		parseErr, ok := err.(*ParseError)
+		require.True(t, ok, tt.input)
+		require.Equal(t, tt.message, parseErr.Message, tt.input)
+		require.Equal(t, tt.column, parseErr.Column(), tt.input)
+	}
+}
+
+type testObject map[string]Value
+
+func (o testObject) GetValue(name string) Value {
+	if v, ok := o[name]; ok {
+		return v
+	}
+	return None()
+}
+
+func TestFiltersMatches(t *testing.T) {
+	filters, err := MakeFilters([]string{
+		"(optimizer = adam or optimizer = adamw) and not status = running",
+		"step >= 10",
+	})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		obj      testObject
+		expected bool
+	}{
+		{testObject{"optimizer": String("adam"), "status": String("stopped"), "step": Int(10)}, true},
+		{testObject{"optimizer": String("adamw"), "status": String("stopped"), "step": Int(20)}, true},
+		{testObject{"optimizer": String("sgd"), "status": String("stopped"), "step": Int(20)}, false},
+		{testObject{"optimizer": String("adam"), "status": String("running"), "step": Int(20)}, false},
+		{testObject{"optimizer": String("adam"), "status": String("stopped"), "step": Int(5)}, false},
+	} {
+		match, err := filters.Matches(tt.obj)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, match, tt.obj)
+	}
+}
+
+func TestSetExclusive(t *testing.T) {
+	filters, err := MakeFilters([]string{"status = stopped or optimizer = adam", "step > 1"})
+	require.NoError(t, err)
+	filters.SetExclusive("status", OperatorEqual, String("running"))
+
+	match, err := filters.Matches(testObject{"status": String("running"), "optimizer": String("sgd"), "step": Int(2)})
+	require.NoError(t, err)
+	require.True(t, match)
+	match, err = filters.Matches(testObject{"status": String("running"), "step": Int(1)})
+	require.NoError(t, err)
+	require.False(t, match)
+}
+
+func TestParseMatchOperators(t *testing.T) {
+	for _, tt := range []struct {
+		input    string
+		expected filter
+	}{
+		{`command ~ "train_large"`, filter{"command", OperatorMatches, String("train_large"), regexp.MustCompile("train_large"), nil}},
+		{`command ~ ^python .*\.py$`, filter{"command", OperatorMatches, String(`^python .*\.py$`), regexp.MustCompile(`^python .*\.py$`), nil}},
+		{"command !~ large", filter{"command", OperatorNotMatches, String("large"), regexp.MustCompile("large"), nil}},
+		{"model glob resnet*", filter{"model", OperatorGlob, String("resnet*"), regexp.MustCompile(globToRegexp("resnet*")), nil}},
+		{"version glob 1.10", filter{"version", OperatorGlob, String("1.10"), regexp.MustCompile(globToRegexp("1.10")), nil}},
+		{"version ~ ^1.0$", filter{"version", OperatorMatches, String("^1.0$"), regexp.MustCompile("^1.0$"), nil}},
+		{"model in [resnet50, vit]", filter{"model", OperatorIn, Object([]interface{}{String("resnet50"), String("vit")}), nil, []string{"resnet50", "vit"}}},
+		{`model IN ["resnet50", 'vit b']`, filter{"model", OperatorIn, Object([]interface{}{String("resnet50"), String("vit b")}), nil, []string{"resnet50", "vit b"}}},
+		{"layers in [1, 2.5, [3, 4]]", filter{"layers", OperatorIn, Object([]interface{}{Int(1), Float(2.5), Object([]interface{}{json.Number("3"), json.Number("4")})}), nil, []string{"1", "2.5", "[3, 4]"}}},
+		{"version in [1.10, 2.0]", filter{"version", OperatorIn, Object([]interface{}{Float(1.1), Float(2)}), nil, []string{"1.10", "2.0"}}},
+		{"model not in []", filter{"model", OperatorNotIn, Object([]interface{}{}), nil, []string{}}},
+		{"tags contains baseline", filter{"tags", OperatorContains, String("baseline"), nil, []string{"baseline"}}},
+		{"layer sizes contains 64", filter{"layer sizes", OperatorContains, Int(64), nil, []string{"64"}}},
+		{"version contains 1.10", filter{"version", OperatorContains, Float(1.1), nil, []string{"1.10"}}},
+		{"index = 1", filter{"index", OperatorEqual, Int(1), nil, nil}},
+		{"max(val_acc) > 0.9", filter{"max(val_acc)", OperatorGreaterThan, Float(0.9), nil, nil}},
+		{"MIN( loss )<=1", filter{"min(loss)", OperatorLessOrEqual, Int(1), nil, nil}},
+		{`last("learning rate") < 1e-4`, filter{`last("learning rate")`, OperatorLessThan, Float(1e-4), nil, nil}},
+		{"count() in [1, 2]", filter{"count()", OperatorIn, Object([]interface{}{Int(1), Int(2)}), nil, []string{"1", "2"}}},
+	} {
+		actual, err := parse(tt.input)
+		require.NoError(t, err, tt.input)
+		require.Equal(t, &tt.expected, actual, tt.input)
+	}
+}
+
+func TestParseMatchOperatorsBad(t *testing.T) {
+	for _, tt := range []struct {
+		input   string
+		column  int
+		message string
+	}{
+		{"command ~ (", 11, "Invalid regular expression: error parsing regexp: missing closing ): `(`"},
+		{"model in resnet50", 10, `Expected a list like [a, b] after "in"`},
+		{"model not  in vit", 15, `Expected a list like [a, b] after "not in"`},
+		{"model in [a b", 14, `Missing "]" to close the "[" in column 10`},
+		{"model in [a, ]", 14, "Expected a value"},
+		{"model in [\"a\" b]", 15, `Expected "," or "]"`},
+		{"model ~= a", 7, `Unknown operator "~="`},
+		{"median(loss) < 1", 1, `Unknown function "median", it must be one of min, max, mean, first, last or count`},
+		{"max(loss < 1", 13, `Missing ")" to close the "(" in column 4`},
+		{"max() < 1", 5, "Expected a name in max()"},
+		{`max("loss" x) < 1`, 12, `Expected ")"`},
+	} {
+		_, err := parse(tt.input)
+		require.Error(t, err, tt.input)
+		parseErr, ok := err.(*ParseError)
+		require.True(t, ok, tt.input)
+		require.Equal(t, tt.message, parseErr.Message, tt.input)
+		require.Equal(t, tt.column, parseErr.Column(), tt.input)
+	}
+}
+
+func TestGlobToRegexp(t *testing.T) {
+	for _, tt := range []struct {
+		glob     string
+		input    string
+		expected bool
+	}{
+		{"resnet*", "resnet50", true},
+		{"resnet*", "a resnet50", false},
+		{"*train*", "python scripts/train.py", true},
+		{"resnet??", "resnet50", true},
+		{"resnet??", "resnet101", false},
+		{"resnet[0-9]*", "resnet50", true},
+		{"resnet[!0-9]*", "resnet50", false},
+		{"a.b", "aXb", false},
+		{`a\*`, "a*", true},
+		{`a\*`, "ab", false},
+		{"a[", "a[", true},
+	} {
+		re := regexp.MustCompile(globToRegexp(tt.glob))
+		require.Equal(t, tt.expected, matchesPattern(String(tt.input), re), "%s %s", tt.glob, tt.input)
+	}
+}
+
+func TestMatchOperators(t *testing.T) {
+	obj := testObject{
+		"command":   String("python train_large.py"),
+		"model":     String("resnet50"),
+		"lr":        Float(0.01),
+		"layers":    Int(3),
+		"tags":      Object([]interface{}{"baseline", "gpu"}),
+		"sizes":     Object([]interface{}{64.0, 128.0}),
+		"optimizer": Object(map[string]interface{}{"name": "adam", "lr": 0.01}),
+		"version":   String("1.10"),
+		"versions":  Object([]interface{}{"1.10", "2.0"}),
+		"configs":   Object(map[string]interface{}{"1.10": "a"}),
+	}
+	for _, tt := range []struct {
+		filter   string
+		expected bool
+	}{
+		{`command ~ "train_large"`, true},
+		{"command ~ ^train", false},
+		{"command !~ ^train", true},
+		{"missing ~ foo", false},
+		{"missing !~ foo", true},
+		{"lr ~ ^0\\.01$", true},
+		{"tags ~ ^gpu$", true},
+		{"tags ~ ^cpu$", false},
+		{"model glob resnet*", true},
+		{"model glob vit*", false},
+		{"layers glob 3", true},
+		{"model in [resnet50, vit]", true},
+		{"model in [vit]", false},
+		{"model not in [vit]", true},
+		{"layers in [1, 3]", true},
+		{"layers in [3.0]", true},
+		{"lr in [0.01, 0.1]", true},
+		{"lr in [foo, 0.01]", true},
+		{"missing in [null]", true},
+		{"tags in [[baseline, gpu]]", false},
+		{`tags in [["baseline", "gpu"]]`, true},
+		{"command contains train", true},
+		{"command contains eval", false},
+		{"tags contains gpu", true},
+		{"tags contains tpu", false},
+		{"sizes contains 64", true},
+		{"optimizer contains name", true},
+		{"optimizer contains momentum", false},
+		{"missing contains foo", false},
+		// numbers are matched in their JSON form
+		{"layers contains 3", true},
+		{"layers contains 4", false},
+		{"lr contains .01", true},
+		{"lr contains 0.1", false},
+		{"(model in [resnet50] or model glob vit*) and not tags contains gpu", false},
+		// number-like patterns, substrings and list items match strings as
+		// they are written
+		{"version glob 1.10", true},
+		{"version glob 1.1", false},
+		{"version glob 1.1*", true},
+		{"version ~ ^1.10$", true},
+		{"version !~ ^1.10$", false},
+		{"version in [1.10]", true},
+		{"version in [1.1]", false},
+		{"version not in [1.10, 2.0]", false},
+		{"version contains 1.10", true},
+		{"version contains .10", true},
+		{"versions contains 1.10", true},
+		{"versions contains 2.0", true},
+		{"versions contains 1.1", false},
+		{"configs contains 1.10", true},
+		{"lr in [1.10]", false},
+	} {
+		filters, err := MakeFilters([]string{tt.filter})
+		require.NoError(t, err, tt.filter)
+		match, err := filters.Matches(obj)
+		require.NoError(t, err, tt.filter)
+		require.Equal(t, tt.expected, match, tt.filter)
+	}
+}
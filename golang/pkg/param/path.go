@@ -0,0 +1,197 @@
+package param
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Paths address values inside objects, e.g. "optimizer.lr" or
+// "layers[0].units". Keys that contain "." or "[" can be quoted, e.g.
+// `optimizer["beta.1"]`.
+
+// pathElement is either a key of an object or an index of a list
+type pathElement struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parsePath parses a path inside an object, like "lr", ".lr" or
+// "[0].units"
+func parsePath(path string) ([]pathElement, error) {
+	elements := []pathElement{}
+	i := 0
+	for i < len(path) {
+		switch path[i] {
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("Missing \"]\" in path %q", path)
+			}
+			inner := path[i+1 : i+end]
+			if len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0] {
+				elements = append(elements, pathElement{key: inner[1 : len(inner)-1]})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid index %q in path %q", inner, path)
+				}
+				elements = append(elements, pathElement{index: index, isIndex: true})
+			}
+			i += end + 1
+		default:
+			if path[i] == '.' {
+				i++
+			}
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("Missing key in path %q", path)
+			}
+			elements = append(elements, pathElement{key: path[i : i+end]})
+			i += end
+		}
+	}
+	return elements, nil
+}
+
+// GetPath returns the value at path inside v, e.g. "[0].units" if v is a
+// list of objects. The second return value is false if there is nothing
+// at path.
+func (v Value) GetPath(path string) (Value, bool) {
+	elements, err := parsePath(path)
+	if err != nil || v.Type() != TypeObject {
+		return None(), false
+	}
+	obj := v.ObjectVal()
+	for _, el := range elements {
+		switch o := obj.(type) {
+		case map[string]interface{}:
+			if el.isIndex {
+				return None(), false
+			}
+			next, ok := o[el.key]
+			if !ok {
+				return None(), false
+			}
+			obj = next
+		case []interface{}:
+			if !el.isIndex {
+				return None(), false
+			}
+			index := el.index
+			// negative indexes count from the end, like in Python
+			if index < 0 {
+				index += len(o)
+			}
+			if index < 0 || index >= len(o) {
+				return None(), false
+			}
+			obj = o[index]
+		default:
+			return None(), false
+		}
+	}
+	return fromInterface(obj), true
+}
+
+// GetPath returns the value for name, which is either a key in m or a path
+// inside one of its values, e.g. "optimizer.lr". Keys that contain "." or
+// "[" themselves take precedence over paths.
+func (m ValueMap) GetPath(name string) (Value, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	// try the longest key first
+	for i := len(name) - 1; i > 0; i-- {
+		if name[i] != '.' && name[i] != '[' {
+			continue
+		}
+		if v, ok := m[name[:i]]; ok {
+			return v.GetPath(name[i:])
+		}
+	}
+	return None(), false
+}
+
+// Flatten returns a copy of m where objects are replaced by the values
+// inside them, with paths as keys, e.g. {"optimizer": {"lr": 0.01}}
+// becomes {"optimizer.lr": 0.01}. Empty objects are kept as they are.
+func (m ValueMap) Flatten() ValueMap {
+	result := ValueMap{}
+	for k, v := range m {
+		flattenInto(result, k, v)
+	}
+	return result
+}
+
+func flattenInto(result ValueMap, path string, v Value) {
+	if v.Type() != TypeObject {
+		result[path] = v
+		return
+	}
+	switch o := v.ObjectVal().(type) {
+	case map[string]interface{}:
+		if len(o) == 0 {
+			break
+		}
+		keys := make([]string, 0, len(o))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			flattenInto(result, path+formatPathKey(k), fromInterface(o[k]))
+		}
+		return
+	case []interface{}:
+		if len(o) == 0 {
+			break
+		}
+		for i, item := range o {
+			flattenInto(result, fmt.Sprintf("%s[%d]", path, i), fromInterface(item))
+		}
+		return
+	}
+	result[path] = v
+}
+
+// formatPathKey returns the path element for key, quoting it if it can't
+// be written after a "."
+func formatPathKey(key string) string {
+	if key == "" || strings.ContainsAny(key, ".[]\"'") {
+		return "[" + strconv.Quote(key) + "]"
+	}
+	return "." + key
+}
+
+// fromInterface converts a value inside an object, as unmarshalled from
+// JSON, to a Value. Numbers are unmarshalled as json.Number, so they become
+// ints or floats in the same way as params, e.g. 1 is an int and 1.0 is a
+// float.
+func fromInterface(obj interface{}) Value {
+	switch o := obj.(type) {
+	case Value:
+		return o
+	case nil:
+		return None()
+	case bool:
+		return Bool(o)
+	case string:
+		return String(o)
+	case int:
+		return Int(int64(o))
+	case int64:
+		return Int(o)
+	case float64:
+		return Float(o)
+	case json.Number:
+		return ParseFromString(o.String())
+	}
+	return Object(obj)
+}
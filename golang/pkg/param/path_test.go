@@ -0,0 +1,116 @@
+package param
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePath(t *testing.T) {
+	for _, tt := range []struct {
+		path     string
+		expected []pathElement
+	}{
+		{"lr", []pathElement{{key: "lr"}}},
+		{".lr", []pathElement{{key: "lr"}}},
+		{"[0].units", []pathElement{{index: 0, isIndex: true}, {key: "units"}}},
+		{"a.b[-1][2]", []pathElement{{key: "a"}, {key: "b"}, {index: -1, isIndex: true}, {index: 2, isIndex: true}}},
+		{`["beta.1"].x`, []pathElement{{key: "beta.1"}, {key: "x"}}},
+	} {
+		elements, err := parsePath(tt.path)
+		require.NoError(t, err, tt.path)
+		require.Equal(t, tt.expected, elements, tt.path)
+	}
+
+	for _, path := range []string{"[0", "[foo]", "a..b", "a."} {
+		_, err := parsePath(path)
+		require.Error(t, err, path)
+	}
+}
+
+func TestValueGetPath(t *testing.T) {
+	layers := ParseFromString(`[{"units": 64, "activation": "relu"}, {"units": 10, "dropout": 0.5, "bias": true, "init": null}]`)
+	for _, tt := range []struct {
+		path     string
+		expected Value
+	}{
+		{"[0].units", Int(64)},
+		{"[0].activation", String("relu")},
+		{"[1].dropout", Float(0.5)},
+		{"[1].bias", Bool(true)},
+		{"[1].init", None()},
+		{"[-1].units", Int(10)},
+		{"[0]", Object(map[string]interface{}{"units": json.Number("64"), "activation": "relu"})},
+	} {
+		val, ok := layers.GetPath(tt.path)
+		require.True(t, ok, tt.path)
+		require.Equal(t, tt.expected, val, tt.path)
+	}
+
+	for _, path := range []string{"[2]", "[-3]", "[0].missing", "[0][0]", "units", "[0].units.foo"} {
+		_, ok := layers.GetPath(path)
+		require.False(t, ok, path)
+	}
+
+	_, ok := Int(1).GetPath("foo")
+	require.False(t, ok)
+}
+
+func TestValueMapGetPath(t *testing.T) {
+	m := ValueMap{
+		"optimizer": ParseFromString(`{"name": "adam", "lr": 0.01, "betas": [0.9, 0.999]}`),
+		"schedule":  ParseFromString(`{"lr": 1.0, "epochs": 10}`),
+		"a.b":       Int(1),
+		"a":         ParseFromString(`{"b": 2, "c": 3}`),
+	}
+	for _, tt := range []struct {
+		name     string
+		expected Value
+	}{
+		{"optimizer", m["optimizer"]},
+		{"optimizer.name", String("adam")},
+		{"optimizer.lr", Float(0.01)},
+		{"optimizer.betas[1]", Float(0.999)},
+		// whole numbers with a decimal point are floats, like params
+		{"schedule.lr", Float(1)},
+		{"schedule.epochs", Int(10)},
+		// keys containing "." take precedence
+		{"a.b", Int(1)},
+		{"a.c", Int(3)},
+	} {
+		val, ok := m.GetPath(tt.name)
+		require.True(t, ok, tt.name)
+		require.Equal(t, tt.expected, val, tt.name)
+	}
+
+	for _, name := range []string{"missing", "optimizer.missing", "missing.lr", "optimizer.betas[2]"} {
+		_, ok := m.GetPath(name)
+		require.False(t, ok, name)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	m := ValueMap{
+		"lr":        Float(0.01),
+		"optimizer": ParseFromString(`{"name": "adam", "betas": [0.9, 0.999], "weird.key": 1}`),
+		"layers":    ParseFromString(`[{"units": 64}]`),
+		"empty":     ParseFromString(`{}`),
+	}
+	require.Equal(t, ValueMap{
+		"lr":                     Float(0.01),
+		"optimizer.name":         String("adam"),
+		"optimizer.betas[0]":     Float(0.9),
+		"optimizer.betas[1]":     Float(0.999),
+		`optimizer["weird.key"]`: Int(1),
+		"layers[0].units":        Int(64),
+		"empty":                  m["empty"],
+	}, m.Flatten())
+
+	// flattened keys are paths that GetPath understands
+	for k, v := range m.Flatten() {
+		val, ok := m.GetPath(k)
+		require.True(t, ok, k)
+		require.Equal(t, v, val, k)
+	}
+}
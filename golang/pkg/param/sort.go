@@ -1,37 +1,197 @@
 package param
 
 import (
+	"fmt"
+	"math"
+	"regexp"
 	"strings"
 )
 
+// Sorter sorts by several keys. Later keys break ties in earlier ones.
 type Sorter struct {
-	Key        string
+	Keys []SortKey
+}
+
+type SortKey struct {
+	Name       string
 	Descending bool
+	// NullsFirst puts objects without a value for Name first, regardless
+	// of the direction. By default they are last.
+	NullsFirst bool
+}
+
+var (
+	nullsRegex     = regexp.MustCompile(`(?i)\s+nulls[\s-]+(first|last)$`)
+	directionRegex = regexp.MustCompile(`(?i)(\s+|-)(asc|desc)$`)
+)
+
+// NewSorter parses a comma-separated list of sort keys, e.g.
+// "val_loss,-step". Each key can be prefixed with "-" for a descending
+// sort, or "+" for ascending, which is the default. "-desc" and "-asc"
+// suffixes work too. Keys can be followed by "nulls first" or "nulls last".
+func NewSorter(sortString string) (*Sorter, error) {
+	sorter := &Sorter{}
+	for _, s := range SplitNames(sortString) {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, fmt.Errorf("Empty sort key in %q", sortString)
+		}
+		key := SortKey{}
+		if match := nullsRegex.FindStringSubmatch(s); match != nil {
+			key.NullsFirst = strings.EqualFold(match[1], "first")
+			s = strings.TrimSpace(s[:len(s)-len(match[0])])
+		}
+		if match := directionRegex.FindStringSubmatch(s); match != nil {
+			key.Descending = strings.EqualFold(match[2], "desc")
+			s = s[:len(s)-len(match[0])]
+		} else if strings.HasPrefix(s, "-") {
+			key.Descending = true
+			s = s[1:]
+		} else if strings.HasPrefix(s, "+") {
+			s = s[1:]
+		}
+		key.Name = strings.TrimSpace(s)
+		if key.Name == "" {
+			return nil, fmt.Errorf("Missing name in sort key %q", sortString)
+		}
+		sorter.Keys = append(sorter.Keys, key)
+	}
+	return sorter, nil
 }
 
-func NewSorter(sortString string) *Sorter {
-	key := sortString
-	desc := false
-	if strings.HasSuffix(sortString, "-desc") {
-		key = strings.TrimSuffix(sortString, "-desc")
-		desc = true
-	} else if strings.HasSuffix(sortString, "-asc") {
-		key = strings.TrimSuffix(sortString, "-asc")
+// SplitNames splits a list of names like "val_loss,max(acc)" on commas
+// that aren't inside parentheses, brackets or quotes
+func SplitNames(s string) []string {
+	keys := []string{}
+	depth := 0
+	var quote rune
+	start := 0
+	for i, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			keys = append(keys, s[start:i])
+			start = i + 1
+		}
 	}
-	return &Sorter{Key: key, Descending: desc}
+	return append(keys, s[start:])
 }
 
+// LessThan returns true if x sorts before y. Values of any type can be
+// compared, see Compare.
 func (s *Sorter) LessThan(x ValueGetter, y ValueGetter) bool {
-	xVal := x.GetValue(s.Key)
-	yVal := y.GetValue(s.Key)
-	var isLess bool
-	var err error
-	isLess, err = xVal.LessThan(yVal)
-	if err != nil {
-		panic(err)
-	}
-	if s.Descending {
-		return !isLess
-	}
-	return isLess
+	for _, key := range s.Keys {
+		if c := key.compare(x.GetValue(key.Name), y.GetValue(key.Name)); c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+func (k SortKey) compare(x Value, y Value) int {
+	switch {
+	case x.IsNone() && y.IsNone():
+		return 0
+	case x.IsNone():
+		if k.NullsFirst {
+			return -1
+		}
+		return 1
+	case y.IsNone():
+		if k.NullsFirst {
+			return 1
+		}
+		return -1
+	}
+	c := Compare(x, y)
+	if k.Descending {
+		return -c
+	}
+	return c
+}
+
+// typeOrder is the order of types in Compare. Ints and floats are both
+// numbers, so they are compared by value.
+var typeOrder = map[Type]int{
+	TypeNone:     0,
+	TypeBool:     1,
+	TypeInt:      2,
+	TypeFloat:    2,
+	TypeTime:     3,
+	TypeDuration: 4,
+	TypeString:   5,
+	TypeObject:   6,
+}
+
+// Compare returns -1 if a is less than b, 0 if they are equal, and 1 if a
+// is greater than b. It is a total order across all types: values of
+// different types are ordered none < bool < number < time < duration <
+// string < object. NaN is greater than all other numbers, and objects are
+// ordered by their JSON.
+func Compare(a Value, b Value) int {
+	ta, tb := typeOrder[a.Type()], typeOrder[b.Type()]
+	if ta != tb {
+		return compareInts(ta, tb)
+	}
+	switch a.Type() {
+	case TypeNone:
+		return 0
+	case TypeBool:
+		return compareInts(boolToInt(a.BoolVal()), boolToInt(b.BoolVal()))
+	case TypeInt, TypeFloat:
+		if a.Type() == TypeInt && b.Type() == TypeInt {
+			return compareInts64(a.IntVal(), b.IntVal())
+		}
+		fa, fb := toFloat(a), toFloat(b)
+		switch {
+		case math.IsNaN(fa) && math.IsNaN(fb):
+			return 0
+		case math.IsNaN(fa):
+			return 1
+		case math.IsNaN(fb):
+			return -1
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	case TypeTime:
+		return compareInts64(a.TimeVal().UnixNano(), b.TimeVal().UnixNano())
+	case TypeDuration:
+		return compareInts64(int64(a.DurationVal()), int64(b.DurationVal()))
+	case TypeString:
+		return strings.Compare(a.StringVal(), b.StringVal())
+	}
+	return strings.Compare(a.String(), b.String())
+}
+
+func compareInts(a, b int) int {
+	return compareInts64(int64(a), int64(b))
+}
+
+func compareInts64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,101 @@
+package param
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSorter(t *testing.T) {
+	for _, tt := range []struct {
+		input    string
+		expected []SortKey
+	}{
+		{"created", []SortKey{{Name: "created"}}},
+		{"created-desc", []SortKey{{Name: "created", Descending: true}}},
+		{"created-asc", []SortKey{{Name: "created"}}},
+		{"val_loss,-step", []SortKey{{Name: "val_loss"}, {Name: "step", Descending: true}}},
+		{" +val_loss , step DESC ", []SortKey{{Name: "val_loss"}, {Name: "step", Descending: true}}},
+		{"val_loss nulls first", []SortKey{{Name: "val_loss", NullsFirst: true}}},
+		{"-val_loss NULLS LAST", []SortKey{{Name: "val_loss", Descending: true}}},
+		{"val_loss-desc nulls-first", []SortKey{{Name: "val_loss", Descending: true, NullsFirst: true}}},
+		{"max(val_acc)-desc,layers[0].units", []SortKey{{Name: "max(val_acc)", Descending: true}, {Name: "layers[0].units"}}},
+		{`optimizer["a,b"]`, []SortKey{{Name: `optimizer["a,b"]`}}},
+	} {
+		sorter, err := NewSorter(tt.input)
+		require.NoError(t, err, tt.input)
+		require.Equal(t, tt.expected, sorter.Keys, tt.input)
+	}
+
+	for _, input := range []string{"", "a,,b", "a,", "-", "- nulls first"} {
+		_, err := NewSorter(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// in order
+	values := []Value{
+		None(),
+		Bool(false),
+		Bool(true),
+		Float(math.Inf(-1)),
+		Int(-1),
+		Float(0.5),
+		Int(1),
+		Float(1.5),
+		Float(math.NaN()),
+		Time(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Duration(time.Second),
+		String(""),
+		String("a"),
+		Object([]interface{}{1.0}),
+		Object(map[string]interface{}{"a": 1.0}),
+	}
+	for i, a := range values {
+		for j, b := range values {
+			expected := compareInts(i, j)
+			require.Equal(t, expected, Compare(a, b), "%s %s", a, b)
+		}
+	}
+	require.Equal(t, 0, Compare(Int(1), Float(1)))
+}
+
+func TestSorterLessThan(t *testing.T) {
+	objects := []testObject{
+		{"name": String("a"), "loss": Float(0.5), "step": Int(10)},
+		{"name": String("b"), "loss": None(), "step": Int(20)},
+		{"name": String("c"), "loss": Float(0.1), "step": Int(5)},
+		{"name": String("d"), "loss": Float(0.5), "step": Int(30)},
+		// mixed types don't panic
+		{"name": String("e"), "loss": String("n/a"), "step": Int(1)},
+		{"name": String("f"), "step": Int(40)},
+	}
+
+	for _, tt := range []struct {
+		sort     string
+		expected string
+	}{
+		{"loss", "cadebf"},
+		{"loss,-step", "cdaefb"},
+		{"loss nulls first", "bfcade"},
+		{"-loss", "eadcbf"},
+		{"-loss nulls first", "bfeadc"},
+		{"step", "ecabdf"},
+	} {
+		sorter, err := NewSorter(tt.sort)
+		require.NoError(t, err)
+		sorted := append([]testObject{}, objects...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorter.LessThan(sorted[i], sorted[j])
+		})
+		names := ""
+		for _, obj := range sorted {
+			names += obj["name"].StringVal()
+		}
+		require.Equal(t, tt.expected, names, tt.sort)
+	}
+}
@@ -0,0 +1,242 @@
+package param
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+)
+
+// now is the time that relative times like "3 days ago" are relative to.
+// It is a variable so tests can fix it.
+var now = time.Now
+
+const timeHelp = `it must be a date like 2020-01-31, a relative time like "3 days ago", ` +
+	`or one of now, today, yesterday, this week, last week, this month, last month, this year or last year`
+
+const durationHelp = `it must be a duration like "2h30m", "90 minutes" or "3 days"`
+
+func Time(v time.Time) Value {
+	return Value{timeVal: &v}
+}
+
+func Duration(v time.Duration) Value {
+	return Value{durationVal: &v}
+}
+
+func (v Value) TimeVal() time.Time {
+	if v.Type() != TypeTime {
+		panic(fmt.Sprintf("Can't use %s as time", v))
+	}
+	return *v.timeVal
+}
+
+func (v Value) DurationVal() time.Duration {
+	if v.Type() != TypeDuration {
+		panic(fmt.Sprintf("Can't use %s as duration", v))
+	}
+	return *v.durationVal
+}
+
+// ParseTime parses a time in a filter, relative to now. It can be an
+// absolute date and time in any format dateparse understands, a relative
+// time like "3 days ago", or a named anchor like "yesterday", which is the
+// start of that day. Weeks start on Monday.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	original := strings.TrimSpace(s)
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// days since Monday
+	weekday := (int(now.Weekday()) + 6) % 7
+	startOfWeek := startOfDay.AddDate(0, 0, -weekday)
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	startOfYear := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+
+	switch s {
+	case "now":
+		return now, nil
+	case "today":
+		return startOfDay, nil
+	case "yesterday":
+		return startOfDay.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return startOfDay.AddDate(0, 0, 1), nil
+	case "this week":
+		return startOfWeek, nil
+	case "last week":
+		return startOfWeek.AddDate(0, 0, -7), nil
+	case "this month":
+		return startOfMonth, nil
+	case "last month":
+		return startOfMonth.AddDate(0, -1, 0), nil
+	case "this year":
+		return startOfYear, nil
+	case "last year":
+		return startOfYear.AddDate(-1, 0, 0), nil
+	}
+
+	if strings.HasSuffix(s, " ago") {
+		d, err := ParseDuration(strings.TrimSuffix(s, " ago"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("Failed to parse time %q: %s", original, durationHelp)
+		}
+		return now.Add(-d), nil
+	}
+
+	t, err := dateparse.ParseIn(original, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Failed to parse time %q: %s", original, timeHelp)
+	}
+	return t, nil
+}
+
+var durationPartRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([a-z]+)\s*`)
+
+var durationUnits = map[string]time.Duration{
+	"s":       time.Second,
+	"sec":     time.Second,
+	"secs":    time.Second,
+	"second":  time.Second,
+	"seconds": time.Second,
+	"m":       time.Minute,
+	"min":     time.Minute,
+	"mins":    time.Minute,
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"h":       time.Hour,
+	"hr":      time.Hour,
+	"hrs":     time.Hour,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+	"d":       24 * time.Hour,
+	"day":     24 * time.Hour,
+	"days":    24 * time.Hour,
+	"w":       7 * 24 * time.Hour,
+	"week":    7 * 24 * time.Hour,
+	"weeks":   7 * 24 * time.Hour,
+	// months and years are approximate, because durations don't know what
+	// time they start at
+	"month":  30 * 24 * time.Hour,
+	"months": 30 * 24 * time.Hour,
+	"y":      365 * 24 * time.Hour,
+	"year":   365 * 24 * time.Hour,
+	"years":  365 * 24 * time.Hour,
+}
+
+// ParseDuration parses a duration like "2h30m", "90 minutes" or
+// "1 day 12 hours"
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if s == "" {
+		return 0, fmt.Errorf("Failed to parse duration %q: %s", s, durationHelp)
+	}
+	var total time.Duration
+	for rest := s; rest != ""; {
+		match := durationPartRegex.FindStringSubmatch(rest)
+		if match == nil {
+			return 0, fmt.Errorf("Failed to parse duration %q: %s", s, durationHelp)
+		}
+		unit, ok := durationUnits[match[2]]
+		if !ok {
+			return 0, fmt.Errorf("Failed to parse duration %q: unknown unit %q", s, match[2])
+		}
+		n, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("Failed to parse duration %q: %s", s, err)
+		}
+		total += time.Duration(n * float64(unit))
+		rest = rest[len(match[0]):]
+	}
+	return total, nil
+}
+
+// coerceTimes converts strings and numbers that are compared to times or
+// durations into times or durations, so filters like "created > 3 days ago"
+// and "duration > 2h" work. Numbers are dates or Unix timestamps when
+// compared to times, see toTime(), and seconds when compared to durations.
+func coerceTimes(v Value, other Value) (Value, Value, error) {
+	var err error
+	switch {
+	case v.Type() == TypeTime && other.Type() != TypeTime:
+		other, err = toTime(other)
+	case other.Type() == TypeTime && v.Type() != TypeTime:
+		v, err = toTime(v)
+	case v.Type() == TypeDuration && other.Type() != TypeDuration:
+		other, err = toDuration(other)
+	case other.Type() == TypeDuration && v.Type() != TypeDuration:
+		v, err = toDuration(v)
+	}
+	return v, other, err
+}
+
+// toTime converts a value to a time. Numbers are parsed as dates first, so
+// 2020 is the start of 2020 and 20200131 is the 31st of January 2020, and
+// otherwise they are Unix timestamps.
+func toTime(v Value) (Value, error) {
+	switch v.Type() {
+	case TypeString:
+		t, err := ParseTime(v.StringVal(), now())
+		if err != nil {
+			return v, err
+		}
+		return Time(t), nil
+	case TypeInt:
+		if t, err := ParseTime(v.String(), now()); err == nil {
+			return Time(t), nil
+		}
+		return Time(time.Unix(v.IntVal(), 0)), nil
+	case TypeFloat:
+		if t, err := ParseTime(v.String(), now()); err == nil {
+			return Time(t), nil
+		}
+		return Time(time.Unix(0, int64(v.FloatVal()*float64(time.Second)))), nil
+	}
+	return v, nil
+}
+
+// checkTimeValue returns an error if a filter compares created or duration
+// to a value that isn't a time or duration, so the mistake is reported when
+// the filter is parsed, with its position
+func checkTimeValue(name string, operator Operator, value Value) error {
+	switch operator {
+	case OperatorEqual, OperatorNotEqual, OperatorLessThan, OperatorLessOrEqual, OperatorGreaterThan, OperatorGreaterOrEqual:
+	default:
+		return nil
+	}
+	if value.IsNone() {
+		return nil
+	}
+	switch name {
+	case "created", "started":
+		if _, err := toTime(value); err != nil {
+			return fmt.Errorf("Invalid time %q", value.String())
+		}
+	case "duration":
+		if _, err := toDuration(value); err != nil {
+			return fmt.Errorf("Invalid duration %q", value.String())
+		}
+	}
+	return nil
+}
+
+func toDuration(v Value) (Value, error) {
+	switch v.Type() {
+	case TypeString:
+		d, err := ParseDuration(v.StringVal())
+		if err != nil {
+			return v, err
+		}
+		return Duration(d), nil
+	case TypeInt:
+		return Duration(time.Duration(v.IntVal()) * time.Second), nil
+	case TypeFloat:
+		return Duration(time.Duration(v.FloatVal() * float64(time.Second))), nil
+	}
+	return v, nil
+}
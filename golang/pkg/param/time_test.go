@@ -0,0 +1,181 @@
+package param
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTime(t *testing.T) {
+	loc := time.FixedZone("test", 8*60*60)
+	// a Wednesday
+	now := time.Date(2021, 3, 17, 15, 30, 0, 0, loc)
+
+	for _, tt := range []struct {
+		input    string
+		expected time.Time
+	}{
+		{"now", now},
+		{"today", time.Date(2021, 3, 17, 0, 0, 0, 0, loc)},
+		{"Yesterday", time.Date(2021, 3, 16, 0, 0, 0, 0, loc)},
+		{"tomorrow", time.Date(2021, 3, 18, 0, 0, 0, 0, loc)},
+		{"this week", time.Date(2021, 3, 15, 0, 0, 0, 0, loc)},
+		{"last  week", time.Date(2021, 3, 8, 0, 0, 0, 0, loc)},
+		{"this month", time.Date(2021, 3, 1, 0, 0, 0, 0, loc)},
+		{"last month", time.Date(2021, 2, 1, 0, 0, 0, 0, loc)},
+		{"this year", time.Date(2021, 1, 1, 0, 0, 0, 0, loc)},
+		{"last year", time.Date(2020, 1, 1, 0, 0, 0, 0, loc)},
+		{"3 days ago", now.Add(-3 * 24 * time.Hour)},
+		{"1 day 12 hours ago", now.Add(-36 * time.Hour)},
+		{"90m ago", now.Add(-90 * time.Minute)},
+		{"2h30m ago", now.Add(-150 * time.Minute)},
+		{"2 weeks ago", now.Add(-14 * 24 * time.Hour)},
+		{"2020-01-31", time.Date(2020, 1, 31, 0, 0, 0, 0, loc)},
+		{"2020-01-31 12:00", time.Date(2020, 1, 31, 12, 0, 0, 0, loc)},
+	} {
+		actual, err := ParseTime(tt.input, now)
+		require.NoError(t, err, tt.input)
+		require.True(t, tt.expected.Equal(actual), "%s: expected %s, got %s", tt.input, tt.expected, actual)
+	}
+
+	// a Sunday is the end of the week
+	sunday := time.Date(2021, 3, 21, 12, 0, 0, 0, loc)
+	actual, err := ParseTime("this week", sunday)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 3, 15, 0, 0, 0, 0, loc), actual)
+
+	for _, input := range []string{"", "soon", "3 fortnights ago", "ago"} {
+		_, err := ParseTime(input, now)
+		require.Error(t, err, input)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for _, tt := range []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"2h30m", 150 * time.Minute},
+		{"90 minutes", 90 * time.Minute},
+		{"1.5 hours", 90 * time.Minute},
+		{"3 days", 72 * time.Hour},
+		{"1 day 2 hours", 26 * time.Hour},
+		{"1d2h", 26 * time.Hour},
+		{"10 secs", 10 * time.Second},
+	} {
+		actual, err := ParseDuration(tt.input)
+		require.NoError(t, err, tt.input)
+		require.Equal(t, tt.expected, actual, tt.input)
+	}
+
+	for _, input := range []string{"", "soon", "3", "3 fortnights", "h"} {
+		_, err := ParseDuration(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestCompareTimes(t *testing.T) {
+	defer func(orig func() time.Time) { now = orig }(now)
+	fixed := time.Date(2021, 3, 17, 15, 30, 0, 0, time.UTC)
+	now = func() time.Time { return fixed }
+
+	created := Time(fixed.Add(-2 * 24 * time.Hour))
+	for _, tt := range []struct {
+		filter   string
+		expected bool
+	}{
+		{"created > 3 days ago", true},
+		{"created > 1 day ago", false},
+		{"created < yesterday", true},
+		{"created >= last week", true},
+		{"created > 2021-03-16", false},
+		{`created >= "2021-03-15 15:30"`, true},
+		{"created = 2021-03-15T15:30:00Z", true},
+		{"created > 1615000000", true},
+		{"created > 2020", true},
+		{"created < 2021", false},
+		{"duration > 2h", true},
+		{"duration <= 1 day 12 hours", true},
+		{"duration < 2 days", true},
+		{"duration < 1 day", false},
+		{"duration = 7200", false},
+		{"duration >= 129600", true},
+		{"duration > 129600", false},
+		{"missing > 3 days ago", false},
+	} {
+		filters, err := MakeFilters([]string{tt.filter})
+		require.NoError(t, err, tt.filter)
+		match, err := filters.Matches(testObject{
+			"created":  created,
+			"duration": Duration(36 * time.Hour),
+		})
+		require.NoError(t, err, tt.filter)
+		require.Equal(t, tt.expected, match, tt.filter)
+	}
+
+	_, err := toTime(String("soon"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Failed to parse time "soon"`)
+}
+
+func TestParseTimeValues(t *testing.T) {
+	defer func(orig func() time.Time) { now = orig }(now)
+	fixed := time.Date(2021, 3, 17, 15, 30, 0, 0, time.UTC)
+	now = func() time.Time { return fixed }
+
+	// numbers are dates if they can be, and Unix timestamps otherwise
+	for _, tt := range []struct {
+		value    Value
+		expected time.Time
+	}{
+		{Int(2020), time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Int(20200131), time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{Int(1615000000), time.Unix(1615000000, 0)},
+		{Int(5), time.Unix(5, 0)},
+		{Float(1615000000.5), time.Unix(1615000000, 5e8)},
+	} {
+		actual, err := toTime(tt.value)
+		require.NoError(t, err, tt.value.String())
+		require.True(t, tt.expected.Equal(actual.TimeVal()), "%s: %s", tt.value, actual.TimeVal())
+	}
+
+	// invalid times and durations are errors when the filter is parsed
+	for _, tt := range []struct {
+		input   string
+		column  int
+		message string
+	}{
+		{"created > soon", 11, `Invalid time "soon"`},
+		{"status = running and started < 3 fortnights ago", 32, `Invalid time "3 fortnights ago"`},
+		{"duration > forever", 12, `Invalid duration "forever"`},
+	} {
+		_, err := parse(tt.input)
+		require.Error(t, err, tt.input)
+		parseErr, ok := err.(*ParseError)
+		require.True(t, ok, tt.input)
+		require.Equal(t, tt.message, parseErr.Message, tt.input)
+		require.Equal(t, tt.column, parseErr.Column(), tt.input)
+	}
+	for _, input := range []string{"created > 2020", "created = null", "created ~ 2020", "duration != 1.5"} {
+		_, err := parse(input)
+		require.NoError(t, err, input)
+	}
+}
+
+func TestTimeString(t *testing.T) {
+	created := Time(time.Date(2021, 3, 17, 15, 30, 0, 0, time.UTC))
+	require.Equal(t, TypeTime, created.Type())
+	require.Equal(t, "2021-03-17T15:30:00Z", created.String())
+	data, err := created.MarshalJSON()
+	require.NoError(t, err)
+	require.Equal(t, `"2021-03-17T15:30:00Z"`, string(data))
+
+	d := Duration(90*time.Minute + 1500*time.Millisecond)
+	require.Equal(t, TypeDuration, d.Type())
+	require.Equal(t, "1h30m1.5s", d.String())
+	require.Equal(t, "1h30m2s", d.ShortString(20, 5))
+	data, err = d.MarshalJSON()
+	require.NoError(t, err)
+	require.Equal(t, "5401.5", string(data))
+}
@@ -1,12 +1,15 @@
 package param
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Type string
@@ -18,6 +21,10 @@ const (
 	TypeBool   Type = "bool"
 	TypeObject Type = "object"
 	TypeNone   Type = "none"
+	// TypeTime and TypeDuration aren't stored in params or metrics, but are
+	// used for fields like "created" and "duration"
+	TypeTime     Type = "time"
+	TypeDuration Type = "duration"
 
 	// hack in nan, +inf and -inf since json doesn't support
 	// them natively.
@@ -34,8 +41,10 @@ type Value struct {
 	stringVal *string
 	boolVal   *bool
 	// objectVal is anything that can't be unmarshalled into the above types, including lists
-	objectVal interface{}
-	isNone    bool
+	objectVal   interface{}
+	timeVal     *time.Time
+	durationVal *time.Duration
+	isNone      bool
 }
 
 func (v Value) MarshalJSON() ([]byte, error) {
@@ -59,6 +68,11 @@ func (v Value) MarshalJSON() ([]byte, error) {
 		return json.Marshal(v.stringVal)
 	case v.objectVal != nil:
 		return json.Marshal(v.objectVal)
+	case v.timeVal != nil:
+		return json.Marshal(v.timeVal)
+	case v.durationVal != nil:
+		// seconds, like Python's timedelta.total_seconds()
+		return json.Marshal(v.durationVal.Seconds())
 	case v.isNone:
 		return []byte("null"), nil
 	}
@@ -105,7 +119,21 @@ func (v *Value) UnmarshalJSON(data []byte) error {
 		v.stringVal = s
 		return nil
 	}
-	return json.Unmarshal(data, &v.objectVal)
+	return unmarshalObject(data, &v.objectVal)
+}
+
+// unmarshalObject unmarshals an object or list, keeping numbers inside it as
+// json.Number so whole numbers like 1.0 stay floats, see fromInterface()
+func unmarshalObject(data []byte, obj *interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(obj); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("Unexpected data after JSON value")
+	}
+	return nil
 }
 
 // ParseFromString attempts to turn a string passed to a filter into a value.
@@ -136,7 +164,7 @@ func ParseFromString(s string) Value {
 		v.boolVal = &val
 		return v
 	}
-	if json.Unmarshal(data, &v.objectVal) == nil {
+	if unmarshalObject(data, &v.objectVal) == nil {
 		return v
 	}
 	v.stringVal = &s
@@ -144,8 +172,13 @@ func ParseFromString(s string) Value {
 }
 
 func (v Value) String() string {
-	if v.Type() == TypeString {
+	switch v.Type() {
+	case TypeString:
 		return v.StringVal()
+	case TypeTime:
+		return v.TimeVal().Format(time.RFC3339)
+	case TypeDuration:
+		return v.DurationVal().String()
 	}
 	data, err := json.Marshal(v)
 	if err != nil {
@@ -187,6 +220,10 @@ func (v Value) ShortString(maxLength int, precision int) string {
 		return Truncate(v.StringVal(), maxLength)
 	case TypeObject:
 		return Truncate(v.String(), maxLength)
+	case TypeTime:
+		return v.TimeVal().Local().Format("2006-01-02 15:04:05")
+	case TypeDuration:
+		return v.DurationVal().Round(time.Second).String()
 	}
 	// Everything else doesn't get truncated (int, bool, none)
 	return v.String()
@@ -204,6 +241,10 @@ func (v Value) Type() Type {
 		return TypeString
 	case v.objectVal != nil:
 		return TypeObject
+	case v.timeVal != nil:
+		return TypeTime
+	case v.durationVal != nil:
+		return TypeDuration
 	case v.isNone:
 		return TypeNone
 	}
@@ -272,6 +313,10 @@ func (v Value) PythonString() string {
 			panic("Error marshaling object: " + err.Error())
 		}
 		return string(data)
+	case TypeTime:
+		return fmt.Sprintf("\"%s\"", v.TimeVal().Format(time.RFC3339))
+	case TypeDuration:
+		return fmt.Sprintf("%f", v.DurationVal().Seconds())
 	case TypeNone:
 		return "None"
 	}
@@ -279,6 +324,10 @@ func (v Value) PythonString() string {
 }
 
 func (v Value) Equal(other Value) (bool, error) {
+	v, other, err := coerceTimes(v, other)
+	if err != nil {
+		return false, err
+	}
 	if !v.IsNone() && other.IsNone() || v.IsNone() && !other.IsNone() {
 		return false, nil
 	}
@@ -296,6 +345,10 @@ func (v Value) Equal(other Value) (bool, error) {
 		return v.StringVal() == other.StringVal(), nil
 	case TypeObject:
 		return reflect.DeepEqual(v.ObjectVal(), other.ObjectVal()), nil
+	case TypeTime:
+		return v.TimeVal().Equal(other.TimeVal()), nil
+	case TypeDuration:
+		return v.DurationVal() == other.DurationVal(), nil
 	case TypeNone:
 		return other.IsNone(), nil
 	}
@@ -311,6 +364,10 @@ func (v Value) NotEqual(other Value) (bool, error) {
 }
 
 func (v Value) GreaterThan(other Value) (bool, error) {
+	v, other, err := coerceTimes(v, other)
+	if err != nil {
+		return false, err
+	}
 	// Special cases
 	if v.Type() == TypeFloat && other.Type() == TypeInt {
 		return v.FloatVal() > float64(other.IntVal()), nil
@@ -334,6 +391,10 @@ func (v Value) GreaterThan(other Value) (bool, error) {
 		return v.FloatVal() > other.FloatVal(), nil
 	case TypeString:
 		return v.StringVal() > other.StringVal(), nil
+	case TypeTime:
+		return v.TimeVal().After(other.TimeVal()), nil
+	case TypeDuration:
+		return v.DurationVal() > other.DurationVal(), nil
 	case TypeObject:
 		return false, nil
 	case TypeNone:
@@ -355,6 +416,10 @@ func (v Value) GreaterOrEqual(other Value) (bool, error) {
 }
 
 func (v Value) LessThan(other Value) (bool, error) {
+	v, other, err := coerceTimes(v, other)
+	if err != nil {
+		return false, err
+	}
 	// Special cases
 	if v.Type() == TypeFloat && other.Type() == TypeInt {
 		return v.FloatVal() < float64(other.IntVal()), nil
@@ -378,6 +443,10 @@ func (v Value) LessThan(other Value) (bool, error) {
 		return v.FloatVal() < other.FloatVal(), nil
 	case TypeString:
 		return v.StringVal() < other.StringVal(), nil
+	case TypeTime:
+		return v.TimeVal().Before(other.TimeVal()), nil
+	case TypeDuration:
+		return v.DurationVal() < other.DurationVal(), nil
 	case TypeNone:
 		return false, nil
 	}
@@ -0,0 +1,227 @@
+package plot
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Series is a line on a chart
+type Series struct {
+	Name string
+	X    []float64
+	Y    []float64
+}
+
+type Options struct {
+	// Width is the width of the whole chart, including the labels of the
+	// Y axis
+	Width int
+	// Height is the number of rows that lines are drawn in
+	Height int
+	// ASCII only uses ASCII characters, for terminals and fonts that can't
+	// display Unicode
+	ASCII bool
+}
+
+const minPlotWidth = 10
+
+type charset struct {
+	markers    []rune
+	axis       rune
+	tick       rune
+	corner     rune
+	horizontal rune
+}
+
+var unicodeCharset = charset{
+	markers:    []rune("•×+◆▲○■◇"),
+	axis:       '│',
+	tick:       '┤',
+	corner:     '└',
+	horizontal: '─',
+}
+
+var asciiCharset = charset{
+	markers:    []rune("*x+o#@%&"),
+	axis:       '|',
+	tick:       '+',
+	corner:     '+',
+	horizontal: '-',
+}
+
+// Chart draws series as a line chart, with labels on the Y axis to the left
+// and the X axis below, followed by a legend. Each series is drawn with its
+// own marker, and later series are drawn over earlier ones. Points that
+// aren't finite numbers are skipped, which breaks the line. An empty string
+// is returned if there aren't any points to draw.
+func Chart(series []*Series, opts Options) string {
+	chars := unicodeCharset
+	if opts.ASCII {
+		chars = asciiCharset
+	}
+	xs, ys := []float64{}, []float64{}
+	for _, s := range series {
+		for i := range s.X {
+			if isFinite(s.X[i]) && isFinite(s.Y[i]) {
+				xs = append(xs, s.X[i])
+				ys = append(ys, s.Y[i])
+			}
+		}
+	}
+	if len(xs) == 0 {
+		return ""
+	}
+	xMin, xMax := finiteRange(xs)
+	yMin, yMax := finiteRange(ys)
+	if yMin == yMax {
+		// give a flat line some room, so it is drawn in the middle
+		delta := math.Abs(yMin) / 10
+		if delta == 0 {
+			delta = 1
+		}
+		yMin, yMax = yMin-delta, yMax+delta
+	}
+
+	height := opts.Height
+	if height < 2 {
+		height = 2
+	}
+	yLabels := map[int]string{0: formatNumber(yMax), height - 1: formatNumber(yMin)}
+	if height >= 5 {
+		middle := (height - 1) / 2
+		yLabels[middle] = formatNumber(yMax - float64(middle)*(yMax-yMin)/float64(height-1))
+	}
+	labelWidth := 0
+	for _, label := range yLabels {
+		if len(label) > labelWidth {
+			labelWidth = len(label)
+		}
+	}
+	width := opts.Width - labelWidth - 2
+	if width < minPlotWidth {
+		width = minPlotWidth
+	}
+
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", width))
+	}
+	toCell := func(x, y float64) (col int, row int) {
+		if xMax > xMin {
+			col = int(math.Round((x - xMin) / (xMax - xMin) * float64(width-1)))
+		}
+		row = int(math.Round((yMax - y) / (yMax - yMin) * float64(height-1)))
+		return col, row
+	}
+	for i, s := range series {
+		marker := chars.markers[i%len(chars.markers)]
+		prevCol, prevRow, hasPrev := 0, 0, false
+		for j := range s.X {
+			if !isFinite(s.X[j]) || !isFinite(s.Y[j]) {
+				hasPrev = false
+				continue
+			}
+			col, row := toCell(s.X[j], s.Y[j])
+			if !hasPrev {
+				prevCol, prevRow = col, row
+			}
+			drawLine(grid, prevCol, prevRow, col, row, marker)
+			prevCol, prevRow, hasPrev = col, row, true
+		}
+	}
+
+	var b strings.Builder
+	for i, row := range grid {
+		axis := chars.axis
+		label, ok := yLabels[i]
+		if ok {
+			axis = chars.tick
+		}
+		b.WriteString(strings.Repeat(" ", labelWidth-len(label)) + label + " " + string(axis))
+		b.WriteString(strings.TrimRight(string(row), " ") + "\n")
+	}
+	b.WriteString(strings.Repeat(" ", labelWidth+1) + string(chars.corner) + strings.Repeat(string(chars.horizontal), width) + "\n")
+	b.WriteString(xLabels(labelWidth+2, width, xMin, xMax) + "\n")
+
+	for i, s := range series {
+		b.WriteString(strings.Repeat(" ", labelWidth+2) + string(chars.markers[i%len(chars.markers)]) + " " + s.Name + "\n")
+	}
+	return b.String()
+}
+
+// xLabels returns the labels of the X axis, with the smallest value at the
+// start of the axis, the largest at the end, and the middle value if there
+// is room for it
+func xLabels(offset int, width int, xMin float64, xMax float64) string {
+	line := []rune(strings.Repeat(" ", offset+width))
+	put := func(pos int, label string) {
+		copy(line[pos:], []rune(label))
+	}
+	left := formatNumber(xMin)
+	put(offset, left)
+	if xMax == xMin {
+		return strings.TrimRight(string(line), " ")
+	}
+	right := formatNumber(xMax)
+	rightPos := offset + width - len(right)
+	if rightPos <= offset+len(left) {
+		return strings.TrimRight(string(line), " ")
+	}
+	put(rightPos, right)
+	middle := formatNumber(xMin + (xMax-xMin)/2)
+	middlePos := offset + (width-len(middle))/2
+	if middlePos > offset+len(left)+1 && middlePos+len(middle) < rightPos-1 {
+		put(middlePos, middle)
+	}
+	return string(line)
+}
+
+// drawLine draws a line between two cells of the grid with Bresenham's
+// algorithm
+func drawLine(grid [][]rune, col0 int, row0 int, col1 int, row1 int, marker rune) {
+	dCol, dRow := abs(col1-col0), -abs(row1-row0)
+	stepCol, stepRow := 1, 1
+	if col0 > col1 {
+		stepCol = -1
+	}
+	if row0 > row1 {
+		stepRow = -1
+	}
+	e := dCol + dRow
+	for {
+		grid[row0][col0] = marker
+		if col0 == col1 && row0 == row1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dRow {
+			e += dRow
+			col0 += stepCol
+		}
+		if e2 <= dCol {
+			e += dCol
+			row0 += stepRow
+		}
+	}
+}
+
+// formatNumber formats a label on an axis. Whole numbers, like steps, are
+// displayed in full, and other numbers with three significant digits.
+func formatNumber(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e9 {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'g', 3, 64)
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
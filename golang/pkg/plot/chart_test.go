@@ -0,0 +1,56 @@
+package plot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChart(t *testing.T) {
+	series := []*Series{
+		{Name: "1eeeeee", X: []float64{0, 10, 20, 30, 40}, Y: []float64{1, 0.75, 0.5, 0.5, 0}},
+		// NaN breaks the line
+		{Name: "2eeeeee", X: []float64{0, 20, 30, 40}, Y: []float64{0, 1, math.NaN(), 1}},
+	}
+	expected := `
+  1 ┤•••       ××         ×
+    │   ••••×××
+0.5 ┤     ×× ••••••••••
+    │  ×××             ••
+  0 ┤××                  ••
+    └──────────────────────
+     0         20        40
+     • 1eeeeee
+     × 2eeeeee
+`
+	require.Equal(t, expected[1:], Chart(series, Options{Width: 27, Height: 5}))
+
+	expected = `
+  1 +***       xx         x
+    |   ****xxx
+0.5 +     xx **********
+    |  xxx             **
+  0 +xx                  **
+    +----------------------
+     0         20        40
+     * 1eeeeee
+     x 2eeeeee
+`
+	require.Equal(t, expected[1:], Chart(series, Options{Width: 27, Height: 5, ASCII: true}))
+
+	require.Equal(t, "", Chart([]*Series{{Name: "1eeeeee", X: []float64{0}, Y: []float64{math.NaN()}}}, Options{Width: 27, Height: 5}))
+}
+
+func TestChartFlatLine(t *testing.T) {
+	series := []*Series{{Name: "1eeeeee", X: []float64{5}, Y: []float64{2}}}
+	expected := `
+2.2 ┤
+    │•
+1.8 ┤
+    └──────────
+     5
+     • 1eeeeee
+`
+	require.Equal(t, expected[1:], Chart(series, Options{Width: 10, Height: 3}))
+}
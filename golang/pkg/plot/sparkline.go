@@ -0,0 +1,125 @@
+// Package plot draws metrics as text, so how they change over the course
+// of an experiment can be seen in a terminal
+package plot
+
+import (
+	"math"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+const (
+	defaultSparklineWidth = 20
+	minSparklineWidth     = 8
+	maxSparklineWidth     = 40
+)
+
+// Sparkline draws values as a line of block characters, scaled between the
+// smallest and largest value. It is at most width characters wide: if there
+// are more values than that, neighbouring values are averaged. NaN values
+// are spaces.
+func Sparkline(values []float64, width int) string {
+	return string(sparkline(values, width))
+}
+
+// SparklinePrefixes returns the sparkline of values, cut off after the
+// character that each value is drawn in. Displayed next to each value in a
+// table, the line grows down the rows.
+func SparklinePrefixes(values []float64, width int) []string {
+	line := sparkline(values, width)
+	prefixes := make([]string, len(values))
+	for i := range values {
+		prefixes[i] = string(line[:bucket(i, len(values), len(line))+1])
+	}
+	return prefixes
+}
+
+// SparklineWidth returns the width of sparklines in tables, which is a
+// fraction of the width of the terminal so the rest of the table still fits
+func SparklineWidth() int {
+	width, err := console.GetWidth()
+	if err != nil || width == 0 {
+		return defaultSparklineWidth
+	}
+	w := int(width) / 8
+	if w < minSparklineWidth {
+		return minSparklineWidth
+	}
+	if w > maxSparklineWidth {
+		return maxSparklineWidth
+	}
+	return w
+}
+
+func sparkline(values []float64, width int) []rune {
+	resampled := resample(values, width)
+	min, max := finiteRange(resampled)
+	line := make([]rune, len(resampled))
+	for i, v := range resampled {
+		switch {
+		case math.IsNaN(v) || math.IsInf(v, 0):
+			line[i] = ' '
+		case min == max:
+			line[i] = sparkBlocks[len(sparkBlocks)/2-1]
+		default:
+			level := int(math.Round((v - min) / (max - min) * float64(len(sparkBlocks)-1)))
+			line[i] = sparkBlocks[level]
+		}
+	}
+	return line
+}
+
+// resample averages values into width buckets, if there are more values
+// than that. NaN values are ignored, unless a whole bucket is NaN.
+func resample(values []float64, width int) []float64 {
+	if width <= 0 || len(values) <= width {
+		return values
+	}
+	sums := make([]float64, width)
+	counts := make([]int, width)
+	for i, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		b := bucket(i, len(values), width)
+		sums[b] += v
+		counts[b]++
+	}
+	result := make([]float64, width)
+	for i := range result {
+		if counts[i] == 0 {
+			result[i] = math.NaN()
+		} else {
+			result[i] = sums[i] / float64(counts[i])
+		}
+	}
+	return result
+}
+
+// bucket returns which of width buckets the ith of n values is in
+func bucket(i int, n int, width int) int {
+	if n <= width {
+		return i
+	}
+	return i * width / n
+}
+
+// finiteRange returns the smallest and largest of values, ignoring NaN and
+// infinity. Both are NaN if there aren't any finite values.
+func finiteRange(values []float64) (min float64, max float64) {
+	min, max = math.NaN(), math.NaN()
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		if math.IsNaN(min) || v < min {
+			min = v
+		}
+		if math.IsNaN(max) || v > max {
+			max = v
+		}
+	}
+	return min, max
+}
@@ -0,0 +1,22 @@
+package plot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparkline(t *testing.T) {
+	require.Equal(t, "▁▃▅▃▁ █", Sparkline([]float64{1, 2, 3, 2, 1, math.NaN(), 5}, 20))
+	// values are averaged when there are more than fit
+	require.Equal(t, "▁▃▅▆█", Sparkline([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 5))
+	require.Equal(t, "▄▄▄", Sparkline([]float64{0.5, 0.5, 0.5}, 20))
+	require.Equal(t, "  ", Sparkline([]float64{math.NaN(), math.Inf(1)}, 20))
+	require.Equal(t, "", Sparkline([]float64{}, 20))
+}
+
+func TestSparklinePrefixes(t *testing.T) {
+	require.Equal(t, []string{"▁", "▁█", "▁█▁"}, SparklinePrefixes([]float64{1, 2, 1}, 20))
+	require.Equal(t, []string{"▁", "▁", "▁█", "▁█"}, SparklinePrefixes([]float64{1, 1, 2, 2}, 2))
+}
@@ -61,7 +61,7 @@ func (p *Project) CheckoutCheckpoint(checkpoint *Checkpoint, experiment *Experim
 }
 
 // checkout all the files from an experiment or checkpoint
-func (p *Project) CheckoutFileOrDirectory(checkpoint *Checkpoint, experiment *Experiment, outputDir string, checkoutPath string) error {
+func (p *Project) CheckoutFileOrDirectory(checkpoint *Checkpoint, experiment *Experiment, outputDir string, checkoutPath string, quiet bool) error {
 	// Extract the tarfile
 	experimentFilesExist := true
 	checkpointFilesExist := true
@@ -74,7 +74,7 @@ func (p *Project) CheckoutFileOrDirectory(checkpoint *Checkpoint, experiment *Ex
 		} else {
 			return err
 		}
-	} else {
+	} else if !quiet {
 		console.Info("Copied the path %s from experiment %s to %q", checkoutPath, experiment.ShortID(), filepath.Join(outputDir, experiment.Path))
 	}
 
@@ -89,7 +89,7 @@ func (p *Project) CheckoutFileOrDirectory(checkpoint *Checkpoint, experiment *Ex
 				return err
 
 			}
-		} else {
+		} else if !quiet {
 			console.Info("Copied the path %s from checkpoint %s to %q", checkoutPath, checkpoint.ShortID(), filepath.Join(outputDir, checkpoint.Path))
 		}
 
@@ -103,10 +103,12 @@ func (p *Project) CheckoutFileOrDirectory(checkpoint *Checkpoint, experiment *Ex
 		return fmt.Errorf("Neither the experiment %s nor the checkpoint %s has the path %s associated with it. You need to pass the 'path' argument to 'init()' or 'checkpoint()' to check out files.", experiment.ShortID(), checkpoint.ShortID(), checkoutPath)
 	}
 
-	console.Info(`If you want to run this experiment again, this is how it was run:
+	if !quiet {
+		console.Info(`If you want to run this experiment again, this is how it was run:
 
   ` + experiment.Command + `
 `)
+	}
 
 	return nil
 }
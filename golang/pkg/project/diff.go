@@ -0,0 +1,164 @@
+package project
+
+import (
+	"math"
+	"time"
+
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/slices"
+)
+
+// DiffSection is a group of values that differ between two checkpoints,
+// e.g. "Params"
+type DiffSection struct {
+	Name        string
+	Differences []*Difference
+}
+
+// Difference is a value that differs between two checkpoints. Left or
+// Right is nil if the value is only set for one of the checkpoints.
+type Difference struct {
+	Key   string
+	Left  *param.Value
+	Right *param.Value
+}
+
+// DiffTarget is a checkpoint and its experiment, to be compared with
+// other checkpoints by DiffMany
+type DiffTarget struct {
+	Experiment *Experiment
+	Checkpoint *Checkpoint
+}
+
+// MultiDiffSection is a group of values that differ between any number of
+// checkpoints, e.g. "Params"
+type MultiDiffSection struct {
+	Name        string             `json:"name"`
+	Differences []*MultiDifference `json:"differences"`
+}
+
+// MultiDifference is a value that differs between checkpoints. Values has
+// a value for each checkpoint, in the order they were compared, which is
+// nil if the value isn't set for that checkpoint.
+type MultiDifference struct {
+	Key    string         `json:"key"`
+	Values []*param.Value `json:"values"`
+}
+
+// DiffCheckpoints returns the differences between two checkpoints and
+// their experiments, in the sections shown by `keepsake diff`. Times are
+// formatted in loc. Objects in params and metrics are compared value by
+// value, with paths like "optimizer.lr" as keys.
+func DiffCheckpoints(exp1 *Experiment, chk1 *Checkpoint, exp2 *Experiment, chk2 *Checkpoint, loc *time.Location) []*DiffSection {
+	sections := []*DiffSection{}
+	for _, section := range DiffMany([]*DiffTarget{{exp1, chk1}, {exp2, chk2}}, loc) {
+		sections = append(sections, &DiffSection{Name: section.Name, Differences: leftRight(section.Differences)})
+	}
+	return sections
+}
+
+// DiffMany returns the values that differ between any number of
+// checkpoints and their experiments, in the same sections as
+// DiffCheckpoints
+func DiffMany(targets []*DiffTarget, loc *time.Location) []*MultiDiffSection {
+	experiments := make([]param.ValueMap, len(targets))
+	params := make([]param.ValueMap, len(targets))
+	packages := make([]param.ValueMap, len(targets))
+	checkpoints := make([]param.ValueMap, len(targets))
+	metrics := make([]param.ValueMap, len(targets))
+	for i, target := range targets {
+		experiments[i] = experimentDiffValues(target.Experiment, loc)
+		params[i] = target.Experiment.Params.Flatten()
+		packages[i] = stringsToValueMap(target.Experiment.PythonPackages)
+		checkpoints[i] = checkpointDiffValues(target.Checkpoint, loc)
+		metrics[i] = target.Checkpoint.Metrics.Flatten()
+	}
+	return []*MultiDiffSection{
+		{"Experiment", diffManyValueMaps(experiments)},
+		{"Params", diffManyValueMaps(params)},
+		{"Python Packages", diffManyValueMaps(packages)},
+		{"Checkpoint", diffManyValueMaps(checkpoints)},
+		{"Metrics", diffManyValueMaps(metrics)},
+	}
+}
+
+func experimentDiffValues(exp *Experiment, loc *time.Location) param.ValueMap {
+	return param.ValueMap{
+		"Created":        param.String(exp.Created.In(loc).Format(time.RFC1123)),
+		"Host":           param.String(exp.Host),
+		"User":           param.String(exp.User),
+		"Command":        param.String(exp.Command),
+		"Python version": param.String(exp.PythonVersion),
+	}
+}
+
+func checkpointDiffValues(chk *Checkpoint, loc *time.Location) param.ValueMap {
+	return param.ValueMap{
+		"Step":    param.Int(chk.Step),
+		"Created": param.String(chk.Created.In(loc).Format(time.RFC1123)),
+		"Path":    param.String(chk.Path),
+	}
+}
+
+func stringsToValueMap(m map[string]string) param.ValueMap {
+	result := param.ValueMap{}
+	for k, v := range m {
+		result[k] = param.String(v)
+	}
+	return result
+}
+
+// diffValueMaps returns the keys whose values are different in left and
+// right, sorted by key
+func diffValueMaps(left, right param.ValueMap) []*Difference {
+	return leftRight(diffManyValueMaps([]param.ValueMap{left, right}))
+}
+
+func leftRight(differences []*MultiDifference) []*Difference {
+	result := make([]*Difference, len(differences))
+	for i, d := range differences {
+		result[i] = &Difference{Key: d.Key, Left: d.Values[0], Right: d.Values[1]}
+	}
+	return result
+}
+
+// diffManyValueMaps returns the keys whose values aren't the same in all
+// of maps, sorted by key. Values of different types are always different,
+// so 1 and 1.0 or "1" are different values.
+func diffManyValueMaps(maps []param.ValueMap) []*MultiDifference {
+	keys := map[string]bool{}
+	for _, m := range maps {
+		for k := range m {
+			keys[k] = true
+		}
+	}
+	differences := []*MultiDifference{}
+	for _, k := range slices.StringKeys(keys) {
+		values := make([]*param.Value, len(maps))
+		for i, m := range maps {
+			if v, ok := m[k]; ok {
+				values[i] = &v
+			}
+		}
+		for _, v := range values[1:] {
+			if !valuesEqual(values[0], v) {
+				differences = append(differences, &MultiDifference{Key: k, Values: values})
+				break
+			}
+		}
+	}
+	return differences
+}
+
+// valuesEqual returns whether two values are the same. nil values aren't
+// set, and NaN is the same as NaN.
+func valuesEqual(a, b *param.Value) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Type() == param.TypeFloat && b.Type() == param.TypeFloat && math.IsNaN(a.FloatVal()) && math.IsNaN(b.FloatVal()) {
+		return true
+	}
+	equal, err := a.Equal(*b)
+	return err == nil && equal
+}
@@ -0,0 +1,94 @@
+package project
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/param"
+)
+
+func TestDiffValueMaps(t *testing.T) {
+	baz := param.String("baz")
+	bop := param.String("bop")
+	same := param.ValueMap{"same": param.String("in both")}
+
+	require.Equal(t, []*Difference{}, diffValueMaps(same, param.ValueMap{"same": param.String("in both")}))
+
+	// just in left
+	require.Equal(t, []*Difference{{Key: "left", Left: &baz}}, diffValueMaps(
+		param.ValueMap{"same": param.String("in both"), "left": baz},
+		same,
+	))
+
+	// just in right
+	require.Equal(t, []*Difference{{Key: "right", Right: &baz}}, diffValueMaps(
+		same,
+		param.ValueMap{"same": param.String("in both"), "right": baz},
+	))
+
+	// different, sorted by key
+	require.Equal(t, []*Difference{
+		{Key: "a", Left: &bop, Right: &baz},
+		{Key: "different", Left: &baz, Right: &bop},
+	}, diffValueMaps(
+		param.ValueMap{"same": param.String("in both"), "different": baz, "a": bop},
+		param.ValueMap{"same": param.String("in both"), "different": bop, "a": baz},
+	))
+}
+
+func TestDiffCheckpointsObjects(t *testing.T) {
+	exp1 := &Experiment{Params: param.ValueMap{
+		"optimizer": param.ParseFromString(`{"name": "adam", "lr": 0.01}`),
+		"layers":    param.ParseFromString(`[64, 10]`),
+	}}
+	exp2 := &Experiment{Params: param.ValueMap{
+		"optimizer": param.ParseFromString(`{"name": "adam", "lr": 0.001}`),
+		"layers":    param.ParseFromString(`[128, 10, 10]`),
+	}}
+	chk := &Checkpoint{Metrics: param.ValueMap{}}
+
+	sections := DiffCheckpoints(exp1, chk, exp2, chk, time.UTC)
+	require.Equal(t, "Params", sections[1].Name)
+
+	lr1, lr2 := param.Float(0.01), param.Float(0.001)
+	units1, units2 := param.Int(64), param.Int(128)
+	extra := param.Int(10)
+	require.Equal(t, []*Difference{
+		{Key: "layers[0]", Left: &units1, Right: &units2},
+		{Key: "layers[2]", Right: &extra},
+		{Key: "optimizer.lr", Left: &lr1, Right: &lr2},
+	}, sections[1].Differences)
+}
+
+func TestDiffMany(t *testing.T) {
+	exp := func(params string) *Experiment {
+		p, err := param.FromJSON(params)
+		require.NoError(t, err)
+		return &Experiment{Params: p}
+	}
+	chk := &Checkpoint{Metrics: param.ValueMap{"loss": param.Float(math.NaN())}}
+	targets := []*DiffTarget{
+		{exp(`{"lr": 0.01, "epochs": 10, "optimizer": {"name": "adam", "betas": [0.9, 0.99]}, "same": "x"}`), chk},
+		{exp(`{"lr": 0.01, "epochs": "10", "optimizer": {"name": "adam", "betas": [0.9, 0.999]}, "same": "x"}`), chk},
+		{exp(`{"lr": 0.001, "epochs": 10, "optimizer": {"name": "sgd"}, "same": "x"}`), chk},
+	}
+	sections := DiffMany(targets, time.UTC)
+	require.Equal(t, "Params", sections[1].Name)
+
+	value := func(v param.Value) *param.Value { return &v }
+	require.Equal(t, []*MultiDifference{
+		// different types are different, even if they look the same
+		{Key: "epochs", Values: []*param.Value{value(param.Int(10)), value(param.String("10")), value(param.Int(10))}},
+		{Key: "lr", Values: []*param.Value{value(param.Float(0.01)), value(param.Float(0.01)), value(param.Float(0.001))}},
+		{Key: "optimizer.betas[0]", Values: []*param.Value{value(param.Float(0.9)), value(param.Float(0.9)), nil}},
+		{Key: "optimizer.betas[1]", Values: []*param.Value{value(param.Float(0.99)), value(param.Float(0.999)), nil}},
+		{Key: "optimizer.name", Values: []*param.Value{value(param.String("adam")), value(param.String("adam")), value(param.String("sgd"))}},
+	}, sections[1].Differences)
+
+	// NaN is the same as NaN
+	require.Equal(t, "Metrics", sections[4].Name)
+	require.Empty(t, sections[4].Differences)
+}
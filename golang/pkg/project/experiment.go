@@ -1,6 +1,7 @@
 package project
 
 import (
+	"context"
 	"encoding/json"
 	"path"
 	"sort"
@@ -141,18 +142,14 @@ func (e *Experiment) BestCheckpoint() *Checkpoint {
 	return best
 }
 
-func listExperiments(repo repository.Repository) ([]*Experiment, error) {
+func listExperiments(ctx context.Context, repo repository.Repository) ([]*Experiment, error) {
 	paths, err := repo.List("metadata/experiments/")
 	if err != nil {
 		return nil, err
 	}
 	experiments := []*Experiment{}
 	for _, p := range paths {
-		exp := new(Experiment)
-		if err := loadFromPath(repo, p, exp); err == nil {
-			if exp.KeepsakeVersion == "" && exp.ReplicateVersion != "" {
-				exp.KeepsakeVersion = exp.ReplicateVersion
-			}
+		if exp, err := loadExperimentFromPath(ctx, repo, p); err == nil {
 			experiments = append(experiments, exp)
 		} else {
 			// Should we complain more loudly? https://github.com/replicate/keepsake/issues/347
@@ -162,6 +159,17 @@ func listExperiments(repo repository.Repository) ([]*Experiment, error) {
 	return experiments, nil
 }
 
+func loadExperimentFromPath(ctx context.Context, repo repository.Repository, path string) (*Experiment, error) {
+	exp := new(Experiment)
+	if err := loadFromPath(ctx, repo, path, exp); err != nil {
+		return nil, err
+	}
+	if exp.KeepsakeVersion == "" && exp.ReplicateVersion != "" {
+		exp.KeepsakeVersion = exp.ReplicateVersion
+	}
+	return exp, nil
+}
+
 func copyCheckpoints(checkpoints []*Checkpoint) []*Checkpoint {
 	copied := make([]*Checkpoint, len(checkpoints))
 	copy(copied, checkpoints)
@@ -0,0 +1,306 @@
+package project
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/replicate/keepsake/golang/pkg/errors"
+	"github.com/replicate/keepsake/golang/pkg/files"
+)
+
+// maxTextDiffSize is the largest file whose contents are returned by
+// DiffFiles, so it can be displayed as a text diff
+const maxTextDiffSize = 64 * 1024
+
+// CheckpointFile is a file saved with an experiment or checkpoint
+type CheckpointFile struct {
+	Path string
+	// TarPath is the tarball in the repository that the file is in
+	TarPath string
+	Size    int64
+}
+
+// ListCheckpointFiles returns the files of a checkpoint, sorted by path.
+// Like when a checkpoint is checked out, these are the files of its
+// experiment with the checkpoint's files on top. chk can be nil to list
+// just the files of the experiment.
+func (p *Project) ListCheckpointFiles(exp *Experiment, chk *Checkpoint) ([]*CheckpointFile, error) {
+	byPath := map[string]*CheckpointFile{}
+	for _, tarPath := range checkpointTarPaths(exp, chk) {
+		tarFiles, err := p.repository.ListTarFile(tarPath)
+		if err != nil {
+			// files might not have been written yet
+			if errors.IsDoesNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, f := range tarFiles {
+			if f.Path == "" || strings.HasSuffix(f.Path, "/") {
+				continue
+			}
+			byPath[f.Path] = &CheckpointFile{Path: f.Path, TarPath: tarPath, Size: f.Size}
+		}
+	}
+
+	result := make([]*CheckpointFile, 0, len(byPath))
+	for _, f := range byPath {
+		result = append(result, f)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result, nil
+}
+
+// checkpointTarPaths returns the tarballs that the files of a checkpoint
+// are in, with the experiment's first
+func checkpointTarPaths(exp *Experiment, chk *Checkpoint) []string {
+	tarPaths := []string{}
+	if exp.Path != "" {
+		tarPaths = append(tarPaths, exp.StorageTarPath())
+	}
+	if chk != nil && chk.Path != "" {
+		tarPaths = append(tarPaths, chk.StorageTarPath())
+	}
+	return tarPaths
+}
+
+// ReadCheckpointFile writes the file at path in the files of a checkpoint
+// to w. Like ListCheckpointFiles, the checkpoint's files are on top of its
+// experiment's files, and chk can be nil to read a file of the experiment.
+func (p *Project) ReadCheckpointFile(exp *Experiment, chk *Checkpoint, path string, w io.Writer) error {
+	path = strings.TrimPrefix(pathpkg.Clean("/"+path), "/")
+	if path == "" {
+		return fmt.Errorf("Missing path of the file to read")
+	}
+
+	tarPaths := checkpointTarPaths(exp, chk)
+	// the checkpoint's files take priority over the experiment's
+	for i := len(tarPaths) - 1; i >= 0; i-- {
+		found, err := p.readTarItem(tarPaths[i], path, w)
+		if err != nil {
+			return err
+		}
+		if found {
+			return nil
+		}
+	}
+	if chk == nil {
+		return errors.DoesNotExist(fmt.Sprintf("The experiment %s does not have the file %s", exp.ShortID(), path))
+	}
+	return errors.DoesNotExist(fmt.Sprintf("Neither the checkpoint %s nor its experiment %s have the file %s", chk.ShortID(), exp.ShortID(), path))
+}
+
+// readTarItem writes the file at path in the tarball tarPath to w. It
+// returns false if the tarball or the file doesn't exist.
+func (p *Project) readTarItem(tarPath string, path string, w io.Writer) (found bool, err error) {
+	dir, err := files.TempDir("read-file")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(dir)
+	if err := p.repository.GetPathItemTar(tarPath, path, dir); err != nil {
+		if errors.IsDoesNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	localPath := filepath.Join(dir, path)
+	info, err := os.Stat(localPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.ReadError(err.Error())
+	}
+	if info.IsDir() {
+		return false, fmt.Errorf("%s is a directory", path)
+	}
+	f, err := os.Open(localPath)
+	if err != nil {
+		return false, errors.ReadError(err.Error())
+	}
+	defer f.Close()
+	if _, err := io.Copy(w, f); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type FileChange string
+
+const (
+	FileAdded    FileChange = "added"
+	FileRemoved  FileChange = "removed"
+	FileModified FileChange = "modified"
+)
+
+// FileDifference is a file that is different in two checkpoints
+type FileDifference struct {
+	Path   string     `json:"path"`
+	Change FileChange `json:"change"`
+	// Left and Right are the contents of a modified file, if it is a
+	// small text file in both checkpoints. Otherwise, they are nil.
+	Left  []byte `json:"-"`
+	Right []byte `json:"-"`
+}
+
+// IsText returns whether the contents of a modified file were read, so
+// it can be displayed as a text diff
+func (d *FileDifference) IsText() bool {
+	return d.Left != nil && d.Right != nil
+}
+
+// DiffFiles returns the files that were added, removed or modified between
+// two checkpoints, sorted by path. Files that are in the same tarball in
+// both checkpoints, e.g. the files of the experiment when comparing two of
+// its checkpoints, are the same. Otherwise, files in both checkpoints are
+// compared by their SHA-256 hashes, which means downloading the tarballs
+// they are in.
+func (p *Project) DiffFiles(exp1 *Experiment, chk1 *Checkpoint, exp2 *Experiment, chk2 *Checkpoint) ([]*FileDifference, error) {
+	files1, err := p.ListCheckpointFiles(exp1, chk1)
+	if err != nil {
+		return nil, err
+	}
+	files2, err := p.ListCheckpointFiles(exp2, chk2)
+	if err != nil {
+		return nil, err
+	}
+	right := map[string]*CheckpointFile{}
+	for _, f := range files2 {
+		right[f.Path] = f
+	}
+
+	differences := []*FileDifference{}
+	// files that might be modified, with the file in the left checkpoint
+	maybeModified := map[string]*CheckpointFile{}
+	tarPaths := map[string]bool{}
+	for _, f := range files1 {
+		r, ok := right[f.Path]
+		if !ok {
+			differences = append(differences, &FileDifference{Path: f.Path, Change: FileRemoved})
+			continue
+		}
+		delete(right, f.Path)
+		if f.TarPath != r.TarPath {
+			maybeModified[f.Path] = f
+			tarPaths[f.TarPath] = true
+			tarPaths[r.TarPath] = true
+		}
+	}
+	for path := range right {
+		differences = append(differences, &FileDifference{Path: path, Change: FileAdded})
+	}
+
+	if len(maybeModified) > 0 {
+		dirs, cleanup, err := p.extractTarballs(tarPaths)
+		defer cleanup()
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files2 {
+			l, ok := maybeModified[f.Path]
+			if !ok {
+				continue
+			}
+			d, err := compareFiles(f.Path, filepath.Join(dirs[l.TarPath], l.Path), filepath.Join(dirs[f.TarPath], f.Path))
+			if err != nil {
+				return nil, err
+			}
+			if d != nil {
+				differences = append(differences, d)
+			}
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Path < differences[j].Path
+	})
+	return differences, nil
+}
+
+// extractTarballs extracts each tarball to its own temporary directory.
+// cleanup removes the directories, and must be called even if there is
+// an error.
+func (p *Project) extractTarballs(tarPaths map[string]bool) (dirs map[string]string, cleanup func(), err error) {
+	dirs = map[string]string{}
+	cleanup = func() {
+		for _, dir := range dirs {
+			os.RemoveAll(dir)
+		}
+	}
+	for tarPath := range tarPaths {
+		dir, err := files.TempDir("diff-files")
+		if err != nil {
+			return nil, cleanup, err
+		}
+		dirs[tarPath] = dir
+		if err := p.repository.GetPathTar(tarPath, dir); err != nil {
+			return nil, cleanup, err
+		}
+	}
+	return dirs, cleanup, nil
+}
+
+// compareFiles returns the difference between two files, or nil if they
+// are the same
+func compareFiles(path string, leftPath string, rightPath string) (*FileDifference, error) {
+	leftHash, leftSize, err := hashFile(leftPath)
+	if err != nil {
+		return nil, err
+	}
+	rightHash, rightSize, err := hashFile(rightPath)
+	if err != nil {
+		return nil, err
+	}
+	if leftHash == rightHash {
+		return nil, nil
+	}
+	d := &FileDifference{Path: path, Change: FileModified}
+	if leftSize > maxTextDiffSize || rightSize > maxTextDiffSize {
+		return d, nil
+	}
+	left, err := os.ReadFile(leftPath)
+	if err != nil {
+		return nil, errors.ReadError(err.Error())
+	}
+	right, err := os.ReadFile(rightPath)
+	if err != nil {
+		return nil, errors.ReadError(err.Error())
+	}
+	if isText(left) && isText(right) {
+		d.Left, d.Right = left, right
+	}
+	return d, nil
+}
+
+func hashFile(path string) (hash string, size int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, errors.ReadError(err.Error())
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err = io.Copy(h, f)
+	if err != nil {
+		return "", 0, errors.ReadError(err.Error())
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// isText returns whether data looks like text, like git does: it is valid
+// UTF-8 without any null bytes
+func isText(data []byte) bool {
+	return utf8.Valid(data) && bytes.IndexByte(data, 0) < 0
+}
@@ -0,0 +1,137 @@
+package project
+
+import (
+	"bytes"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/errors"
+	"github.com/replicate/keepsake/golang/pkg/repository"
+	"github.com/replicate/keepsake/golang/pkg/testutil"
+)
+
+func createFilesTestData(t *testing.T) (*Project, []*Experiment) {
+	repo, err := repository.NewDiskRepository(path.Join(t.TempDir(), ".keepsake"))
+	require.NoError(t, err)
+
+	exp1 := &Experiment{ID: "1eeeeeeeee", Path: ".", Checkpoints: []*Checkpoint{
+		{ID: "1ccccccccc", Path: "."},
+		{ID: "2ccccccccc", Path: "."},
+		{ID: "3ccccccccc"},
+	}}
+	exp2 := &Experiment{ID: "2eeeeeeeee", Path: ".", Checkpoints: []*Checkpoint{
+		{ID: "4ccccccccc", Path: "."},
+	}}
+	testutil.PutTarFiles(t, repo, exp1.StorageTarPath(), "", map[string]string{
+		"train.py":    "import keepsake\nprint(1)\n",
+		"config.yaml": "lr: 0.1\n",
+		"data.bin":    "\x00\x01\x02",
+	})
+	testutil.PutTarFiles(t, repo, exp1.Checkpoints[0].StorageTarPath(), "", map[string]string{
+		"model.txt": "a\nb\nc\n",
+	})
+	testutil.PutTarFiles(t, repo, exp1.Checkpoints[1].StorageTarPath(), "", map[string]string{
+		"model.txt":   "a\nB\nc\n",
+		"config.yaml": "lr: 0.01\n",
+		"out/log.txt": "done\n",
+	})
+	testutil.PutTarFiles(t, repo, exp2.StorageTarPath(), "", map[string]string{
+		"train.py":    "import keepsake\nprint(2)\n",
+		"config.yaml": "lr: 0.1\n",
+		"data.bin":    "\x00\x01\x03",
+	})
+	testutil.PutTarFiles(t, repo, exp2.Checkpoints[0].StorageTarPath(), "", map[string]string{
+		"model.txt": "a\nb\nc\n",
+	})
+	return NewProject(repo, ""), []*Experiment{exp1, exp2}
+}
+
+func TestListCheckpointFiles(t *testing.T) {
+	proj, experiments := createFilesTestData(t)
+	exp := experiments[0]
+
+	checkpointFiles, err := proj.ListCheckpointFiles(exp, exp.Checkpoints[1])
+	require.NoError(t, err)
+	require.Equal(t, []*CheckpointFile{
+		// checkpoint files are on top of the experiment's files
+		{Path: "config.yaml", TarPath: "checkpoints/2ccccccccc.tar.gz", Size: 9},
+		{Path: "data.bin", TarPath: "experiments/1eeeeeeeee.tar.gz", Size: 3},
+		{Path: "model.txt", TarPath: "checkpoints/2ccccccccc.tar.gz", Size: 6},
+		{Path: "out/log.txt", TarPath: "checkpoints/2ccccccccc.tar.gz", Size: 5},
+		{Path: "train.py", TarPath: "experiments/1eeeeeeeee.tar.gz", Size: 25},
+	}, checkpointFiles)
+
+	// a checkpoint without files has the experiment's files
+	checkpointFiles, err = proj.ListCheckpointFiles(exp, exp.Checkpoints[2])
+	require.NoError(t, err)
+	require.Len(t, checkpointFiles, 3)
+
+	checkpointFiles, err = proj.ListCheckpointFiles(exp, nil)
+	require.NoError(t, err)
+	require.Len(t, checkpointFiles, 3)
+}
+
+func TestDiffFiles(t *testing.T) {
+	proj, experiments := createFilesTestData(t)
+	exp1, exp2 := experiments[0], experiments[1]
+
+	differences, err := proj.DiffFiles(exp1, exp1.Checkpoints[0], exp1, exp1.Checkpoints[1])
+	require.NoError(t, err)
+	require.Equal(t, []*FileDifference{
+		{Path: "config.yaml", Change: FileModified, Left: []byte("lr: 0.1\n"), Right: []byte("lr: 0.01\n")},
+		{Path: "model.txt", Change: FileModified, Left: []byte("a\nb\nc\n"), Right: []byte("a\nB\nc\n")},
+		{Path: "out/log.txt", Change: FileAdded},
+	}, differences)
+
+	differences, err = proj.DiffFiles(exp1, exp1.Checkpoints[1], exp2, exp2.Checkpoints[0])
+	require.NoError(t, err)
+	require.Equal(t, []*FileDifference{
+		{Path: "config.yaml", Change: FileModified, Left: []byte("lr: 0.01\n"), Right: []byte("lr: 0.1\n")},
+		// binary files aren't read
+		{Path: "data.bin", Change: FileModified},
+		{Path: "model.txt", Change: FileModified, Left: []byte("a\nB\nc\n"), Right: []byte("a\nb\nc\n")},
+		{Path: "out/log.txt", Change: FileRemoved},
+		{Path: "train.py", Change: FileModified, Left: []byte("import keepsake\nprint(1)\n"), Right: []byte("import keepsake\nprint(2)\n")},
+	}, differences)
+
+	// files that are the same in different tarballs aren't different
+	differences, err = proj.DiffFiles(exp1, exp1.Checkpoints[0], exp2, exp2.Checkpoints[0])
+	require.NoError(t, err)
+	require.Equal(t, []string{"data.bin", "train.py"}, []string{differences[0].Path, differences[1].Path})
+	require.Len(t, differences, 2)
+}
+
+func TestReadCheckpointFile(t *testing.T) {
+	proj, experiments := createFilesTestData(t)
+	exp := experiments[0]
+
+	for _, tt := range []struct {
+		chk      *Checkpoint
+		path     string
+		expected string
+	}{
+		// from the checkpoint, on top of the experiment
+		{exp.Checkpoints[1], "config.yaml", "lr: 0.01\n"},
+		{exp.Checkpoints[1], "./out/log.txt", "done\n"},
+		// from the experiment
+		{exp.Checkpoints[1], "train.py", "import keepsake\nprint(1)\n"},
+		{exp.Checkpoints[2], "config.yaml", "lr: 0.1\n"},
+		{nil, "/config.yaml", "lr: 0.1\n"},
+	} {
+		out := new(bytes.Buffer)
+		require.NoError(t, proj.ReadCheckpointFile(exp, tt.chk, tt.path, out), tt.path)
+		require.Equal(t, tt.expected, out.String(), tt.path)
+	}
+
+	err := proj.ReadCheckpointFile(exp, exp.Checkpoints[1], "config", new(bytes.Buffer))
+	require.True(t, errors.IsDoesNotExist(err))
+	require.EqualError(t, err, "Neither the checkpoint 2cccccc nor its experiment 1eeeeee have the file config")
+
+	err = proj.ReadCheckpointFile(exp, nil, "missing.txt", new(bytes.Buffer))
+	require.EqualError(t, err, "The experiment 1eeeeee does not have the file missing.txt")
+
+	err = proj.ReadCheckpointFile(exp, exp.Checkpoints[1], "out", new(bytes.Buffer))
+	require.EqualError(t, err, "out is a directory")
+}
@@ -1,8 +1,8 @@
 package project
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"path"
 	"time"
 
@@ -38,14 +38,14 @@ func DeleteHeartbeat(repo repository.Repository, experimentID string) error {
 	return repo.Delete(path.Join("metadata", "heartbeats", experimentID+".json"))
 }
 
-func listHeartbeats(repo repository.Repository) ([]*Heartbeat, error) {
+func listHeartbeats(ctx context.Context, repo repository.Repository) ([]*Heartbeat, error) {
 	paths, err := repo.List("metadata/heartbeats/")
 	if err != nil {
 		return nil, err
 	}
 	heartbeats := []*Heartbeat{}
 	for _, p := range paths {
-		if hb, err := loadHeartbeatFromPath(repo, p); err == nil {
+		if hb, err := loadHeartbeatFromPath(ctx, repo, p); err == nil {
 			heartbeats = append(heartbeats, hb)
 		} else {
 			// Should we complain more loudly? https://github.com/replicate/keepsake/issues/347
@@ -61,14 +61,10 @@ func (h *Heartbeat) IsRunning() bool {
 	return h.LastHeartbeat.After(lastTolerableHeartbeat)
 }
 
-func loadHeartbeatFromPath(repo repository.Repository, path string) (*Heartbeat, error) {
-	contents, err := repo.Get(path)
-	if err != nil {
-		return nil, err
-	}
+func loadHeartbeatFromPath(ctx context.Context, repo repository.Repository, path string) (*Heartbeat, error) {
 	hb := new(Heartbeat)
-	if err := json.Unmarshal(contents, hb); err != nil {
-		return nil, fmt.Errorf("Parse error: %s", err)
+	if err := loadFromPath(ctx, repo, path, hb); err != nil {
+		return nil, err
 	}
 	return hb, nil
 }
@@ -0,0 +1,161 @@
+package project
+
+import (
+	"sort"
+	"time"
+
+	"github.com/replicate/keepsake/golang/pkg/config"
+	"github.com/replicate/keepsake/golang/pkg/param"
+)
+
+// ListExperiment is the summary of an experiment that is displayed in
+// lists, and that filters and sorting are applied to
+type ListExperiment struct {
+	ID               string         `json:"id"`
+	Created          time.Time      `json:"created"`
+	Params           param.ValueMap `json:"params"`
+	Command          string         `json:"command"`
+	NumCheckpoints   int            `json:"num_checkpoints"`
+	LatestCheckpoint *Checkpoint    `json:"latest_checkpoint"`
+	BestCheckpoint   *Checkpoint    `json:"best_checkpoint"`
+	User             string         `json:"user"`
+	Host             string         `json:"host"`
+	Running          bool           `json:"running"`
+	// values of the columns that are displayed, by name
+	Columns param.ValueMap `json:"columns,omitempty"`
+
+	// exclude config from json output
+	Config *config.Config `json:"-"`
+	// fields that can be used like params and metrics
+	Computed *param.ComputedFields `json:"-"`
+
+	// all checkpoints in the order they were created, for aggregations
+	checkpoints []*Checkpoint
+	// zero if the experiment doesn't have a heartbeat
+	lastHeartbeat time.Time
+}
+
+func (exp *ListExperiment) ShortID() string {
+	return exp.ID[:7]
+}
+
+// We should add some validation and better error messages, see https://github.com/replicate/keepsake/issues/340
+func (exp *ListExperiment) GetValue(name string) param.Value {
+	if val, ok := exp.Computed.GetValue(name, exp); ok {
+		return val
+	}
+	if agg, arg, ok := param.ParseAggregation(name); ok {
+		return exp.aggregate(agg, arg)
+	}
+	if name == "started" || name == "created" {
+		return param.Time(exp.Created)
+	}
+	if name == "duration" {
+		return exp.duration()
+	}
+	if name == "step" {
+		if exp.LatestCheckpoint != nil {
+			return param.Int(int64(exp.LatestCheckpoint.Step))
+		}
+		return param.Int(0)
+	}
+	if name == "user" {
+		return param.String(exp.User)
+	}
+	if name == "host" {
+		return param.String(exp.Host)
+	}
+	if name == "command" {
+		return param.String(exp.Command)
+	}
+	if name == "status" {
+		if exp.Running {
+			return param.String("running")
+		}
+		return param.String("stopped")
+	}
+	// names can be paths inside objects, e.g. "optimizer.lr"
+	if exp.BestCheckpoint != nil {
+		if val, ok := exp.BestCheckpoint.Metrics.GetPath(name); ok {
+			return val
+		}
+	}
+	if val, ok := exp.Params.GetPath(name); ok {
+		return val
+	}
+	return param.None()
+}
+
+// Checkpoints returns all of the experiment's checkpoints, in the order they
+// were created
+func (exp *ListExperiment) Checkpoints() []*Checkpoint {
+	return exp.checkpoints
+}
+
+// duration returns the time from when the experiment was created to its
+// last checkpoint or heartbeat, or None if it has neither
+func (exp *ListExperiment) duration() param.Value {
+	end := exp.lastHeartbeat
+	if exp.LatestCheckpoint != nil && exp.LatestCheckpoint.Created.After(end) {
+		end = exp.LatestCheckpoint.Created
+	}
+	if end.IsZero() {
+		return param.None()
+	}
+	return param.Duration(end.Sub(exp.Created))
+}
+
+// aggregate combines the values of a metric, or "step", across all of the
+// experiment's checkpoints. An empty name counts the checkpoints.
+func (exp *ListExperiment) aggregate(agg param.Aggregation, name string) param.Value {
+	values := make([]param.Value, len(exp.checkpoints))
+	for i, chk := range exp.checkpoints {
+		switch {
+		case name == "":
+			values[i] = param.Bool(true)
+		case name == "step":
+			values[i] = param.Int(chk.Step)
+		default:
+			values[i], _ = chk.Metrics.GetPath(name)
+		}
+	}
+	return param.Aggregate(agg, values)
+}
+
+// ListExperimentFromExperiment returns the summary of exp, without its last
+// heartbeat
+func ListExperimentFromExperiment(exp *Experiment, running bool) *ListExperiment {
+	checkpoints := make([]*Checkpoint, len(exp.Checkpoints))
+	copy(checkpoints, exp.Checkpoints)
+	sort.SliceStable(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Created.Before(checkpoints[j].Created)
+	})
+	return &ListExperiment{
+		ID:               exp.ID,
+		Params:           exp.Params,
+		Command:          exp.Command,
+		Created:          exp.Created,
+		Host:             exp.Host,
+		User:             exp.User,
+		Config:           exp.Config,
+		LatestCheckpoint: exp.LatestCheckpoint(),
+		BestCheckpoint:   exp.BestCheckpoint(),
+		NumCheckpoints:   len(exp.Checkpoints),
+		Running:          running,
+		checkpoints:      checkpoints,
+	}
+}
+
+// ListExperimentFromProject returns the summary of an experiment in proj,
+// including whether it is running and when its last heartbeat was
+func ListExperimentFromProject(proj *Project, exp *Experiment) (*ListExperiment, error) {
+	heartbeat, err := proj.ExperimentHeartbeat(exp.ID)
+	if err != nil {
+		return nil, err
+	}
+	listExperiment := ListExperimentFromExperiment(exp, heartbeat != nil && heartbeat.IsRunning())
+	if heartbeat != nil {
+		listExperiment.lastHeartbeat = heartbeat.LastHeartbeat
+	}
+	return listExperiment, nil
+}
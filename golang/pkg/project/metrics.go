@@ -0,0 +1,323 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/errors"
+	"github.com/replicate/keepsake/golang/pkg/hash"
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/repository"
+)
+
+// MetricPoint is a set of metric values logged at a step, without
+// creating a checkpoint
+type MetricPoint struct {
+	Step    int64
+	Metrics map[string]param.Value
+}
+
+// MetricSeries is the time series for a single metric. Steps and values
+// are stored as parallel lists to keep the files compact.
+type MetricSeries struct {
+	Steps  []int64       `json:"steps"`
+	Values []param.Value `json:"values"`
+}
+
+// ExperimentMetrics holds all the metric series logged for an experiment
+type ExperimentMetrics struct {
+	ExperimentID string                   `json:"experiment_id"`
+	Series       map[string]*MetricSeries `json:"series"`
+}
+
+// MetricSummary summarises a metric series
+type MetricSummary struct {
+	Name      string
+	Count     int
+	FirstStep int64
+	LastStep  int64
+	Min       float64
+	Max       float64
+	Last      float64
+}
+
+// Metrics are stored as chunks in metrics/<experiment ID>/, one for
+// each time points are logged, so logging doesn't need to read and rewrite
+// the whole series. The chunks are named by a sequence number and a random
+// ID of the project that wrote them, like 00000001-3f9a0c2e.json, so
+// processes logging to the same experiment don't overwrite each other's
+// chunks. They are merged in that order when the metrics are read. They
+// aren't in metadata/, so they aren't synced by commands that only list
+// experiments and checkpoints.
+
+func metricsDir(experimentID string) string {
+	return "metrics/" + experimentID
+}
+
+func metricsChunkPath(experimentID string, seq int, writerID string) string {
+	return fmt.Sprintf("%s/%08d-%s.json", metricsDir(experimentID), seq, writerID)
+}
+
+// MetricsPath returns the directory that the metric chunks of the
+// experiment are in
+func (e *Experiment) MetricsPath() string {
+	return metricsDir(e.ID)
+}
+
+// LogMetrics appends metric points to the time series of an experiment, by
+// writing them as a new chunk. Metric values must be numbers.
+func (p *Project) LogMetrics(experimentID string, points []MetricPoint) error {
+	if err := p.ValidateExperimentID(experimentID); err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	if err := ValidateMetricPoints(points); err != nil {
+		return err
+	}
+
+	// the sequence number of the next chunk is listed the first time, then
+	// counted, so appends to the same project must not interleave. Other
+	// processes might write a chunk with the same number, but not the same
+	// writer ID.
+	p.metricsMu.Lock()
+	defer p.metricsMu.Unlock()
+
+	seq, ok := p.metricsSeq[experimentID]
+	if !ok {
+		chunks, err := listMetricsChunks(p.repository, experimentID)
+		if err != nil {
+			return err
+		}
+		if len(chunks) > 0 {
+			seq = chunks[len(chunks)-1].seq + 1
+		}
+	}
+	chunk := &ExperimentMetrics{ExperimentID: experimentID, Series: map[string]*MetricSeries{}}
+	chunk.append(points)
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+	if p.metricsWriterID == "" {
+		p.metricsWriterID = hash.Random()[:8]
+	}
+	if err := p.repository.Put(metricsChunkPath(experimentID, seq, p.metricsWriterID), data); err != nil {
+		return err
+	}
+	if p.metricsSeq == nil {
+		p.metricsSeq = map[string]int{}
+	}
+	p.metricsSeq[experimentID] = seq + 1
+	return nil
+}
+
+// ValidateExperimentID returns an error if experimentID is empty, or isn't
+// the ID of an experiment in the project
+func (p *Project) ValidateExperimentID(experimentID string) error {
+	if experimentID == "" {
+		return fmt.Errorf("Experiment ID is missing")
+	}
+	experimentsByID, _, err := p.loaded()
+	if err != nil {
+		return err
+	}
+	if _, ok := experimentsByID[experimentID]; ok {
+		return nil
+	}
+	// the experiment might have been created since the metadata was loaded
+	p.invalidateCache()
+	experimentsByID, _, err = p.loaded()
+	if err != nil {
+		return err
+	}
+	if _, ok := experimentsByID[experimentID]; !ok {
+		return errors.DoesNotExist("Experiment not found: " + experimentID)
+	}
+	return nil
+}
+
+// ValidateMetricPoints returns an error if any of the metric values
+// aren't numbers
+func ValidateMetricPoints(points []MetricPoint) error {
+	for _, point := range points {
+		for name, val := range point.Metrics {
+			if val.Type() != param.TypeInt && val.Type() != param.TypeFloat {
+				return fmt.Errorf("Metric %s at step %d must be a number, not %s", name, point.Step, val.Type())
+			}
+		}
+	}
+	return nil
+}
+
+// ExperimentMetrics returns the metric series logged for an experiment.
+// If no metrics have been logged, it returns empty metrics.
+func (p *Project) ExperimentMetrics(experimentID string) (*ExperimentMetrics, error) {
+	return loadExperimentMetrics(p.repository, experimentID)
+}
+
+func loadExperimentMetrics(repo repository.Repository, experimentID string) (*ExperimentMetrics, error) {
+	metrics := &ExperimentMetrics{ExperimentID: experimentID, Series: map[string]*MetricSeries{}}
+	chunks, err := listMetricsChunks(repo, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, chunk := range chunks {
+		contents, err := repo.Get(chunk.path)
+		if err != nil {
+			return nil, err
+		}
+		chunkMetrics := &ExperimentMetrics{}
+		if err := json.Unmarshal(contents, chunkMetrics); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %w", chunk.path, err)
+		}
+		metrics.merge(chunkMetrics)
+	}
+	return metrics, nil
+}
+
+type metricsChunk struct {
+	path     string
+	seq      int
+	writerID string
+}
+
+// listMetricsChunks returns the metric chunks of an experiment, in the
+// order they were written
+func listMetricsChunks(repo repository.Repository, experimentID string) ([]metricsChunk, error) {
+	paths, err := repo.List(metricsDir(experimentID))
+	if err != nil {
+		return nil, err
+	}
+	chunks := []metricsChunk{}
+	for _, p := range paths {
+		name := strings.TrimSuffix(path.Base(p), ".json")
+		parts := strings.SplitN(name, "-", 2)
+		seq, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) != 2 || !strings.HasSuffix(p, ".json") {
+			console.Debug("Skipping unknown file in metrics directory: %s", p)
+			continue
+		}
+		chunks = append(chunks, metricsChunk{path: p, seq: seq, writerID: parts[1]})
+	}
+	// chunks with the same sequence number were written at about the same
+	// time by different processes, so they are ordered by ID to be stable
+	sort.Slice(chunks, func(i, j int) bool {
+		if chunks[i].seq != chunks[j].seq {
+			return chunks[i].seq < chunks[j].seq
+		}
+		return chunks[i].writerID < chunks[j].writerID
+	})
+	return chunks, nil
+}
+
+// merge appends the series of other to m
+func (m *ExperimentMetrics) merge(other *ExperimentMetrics) {
+	for name, otherSeries := range other.Series {
+		if otherSeries == nil || len(otherSeries.Steps) != len(otherSeries.Values) {
+			continue
+		}
+		series, ok := m.Series[name]
+		if !ok {
+			series = &MetricSeries{Steps: []int64{}, Values: []param.Value{}}
+			m.Series[name] = series
+		}
+		series.Steps = append(series.Steps, otherSeries.Steps...)
+		series.Values = append(series.Values, otherSeries.Values...)
+	}
+}
+
+func (m *ExperimentMetrics) append(points []MetricPoint) {
+	for _, point := range points {
+		for name, val := range point.Metrics {
+			series, ok := m.Series[name]
+			if !ok {
+				series = &MetricSeries{Steps: []int64{}, Values: []param.Value{}}
+				m.Series[name] = series
+			}
+			series.Steps = append(series.Steps, point.Step)
+			series.Values = append(series.Values, val)
+		}
+	}
+}
+
+// Summaries returns a summary of each metric series, sorted by name
+func (m *ExperimentMetrics) Summaries() []*MetricSummary {
+	names := []string{}
+	for name := range m.Series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	summaries := []*MetricSummary{}
+	for _, name := range names {
+		if summary := m.Series[name].summarize(name); summary != nil {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
+}
+
+func (s *MetricSeries) summarize(name string) *MetricSummary {
+	if len(s.Steps) == 0 || len(s.Steps) != len(s.Values) {
+		return nil
+	}
+	summary := &MetricSummary{
+		Name:      name,
+		Count:     len(s.Steps),
+		FirstStep: s.Steps[0],
+		LastStep:  s.Steps[0],
+		Min:       math.Inf(1),
+		Max:       math.Inf(-1),
+	}
+	for i, step := range s.Steps {
+		val := numericValue(s.Values[i])
+		if step < summary.FirstStep {
+			summary.FirstStep = step
+		}
+		if step >= summary.LastStep {
+			summary.LastStep = step
+			summary.Last = val
+		}
+		// NaN is neither smaller nor larger than anything, so it is skipped
+		if val < summary.Min {
+			summary.Min = val
+		}
+		if val > summary.Max {
+			summary.Max = val
+		}
+	}
+	return summary
+}
+
+func numericValue(v param.Value) float64 {
+	switch v.Type() {
+	case param.TypeInt:
+		return float64(v.IntVal())
+	case param.TypeFloat:
+		return v.FloatVal()
+	}
+	return math.NaN()
+}
+
+// CheckpointMetricValues returns the value of a metric at each checkpoint,
+// so it can be plotted. It is NaN for checkpoints that don't have the metric,
+// or where it isn't a number. name can be a path inside an object, like
+// "val.loss".
+func CheckpointMetricValues(checkpoints []*Checkpoint, name string) []float64 {
+	values := make([]float64, len(checkpoints))
+	for i, chk := range checkpoints {
+		values[i] = math.NaN()
+		if val, ok := chk.Metrics.GetPath(name); ok {
+			values[i] = numericValue(val)
+		}
+	}
+	return values
+}
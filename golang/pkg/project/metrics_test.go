@@ -0,0 +1,131 @@
+package project
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/repository"
+)
+
+func newMetricsTestProject(t *testing.T) (*Project, repository.Repository) {
+	repo, err := repository.NewDiskRepository(t.TempDir())
+	require.NoError(t, err)
+	for _, id := range []string{"1eee", "2eee"} {
+		require.NoError(t, (&Experiment{ID: id, Created: time.Now().UTC()}).Save(repo))
+	}
+	return NewProject(repo, t.TempDir()), repo
+}
+
+func TestLogMetrics(t *testing.T) {
+	proj, repo := newMetricsTestProject(t)
+
+	metrics, err := proj.ExperimentMetrics("1eee")
+	require.NoError(t, err)
+	require.Empty(t, metrics.Summaries())
+
+	require.NoError(t, proj.LogMetrics("1eee", []MetricPoint{
+		{Step: 1, Metrics: map[string]param.Value{"loss": param.Float(0.5), "acc": param.Float(0.1)}},
+		{Step: 2, Metrics: map[string]param.Value{"loss": param.Float(0.3)}},
+	}))
+	require.NoError(t, proj.LogMetrics("1eee", []MetricPoint{
+		{Step: 3, Metrics: map[string]param.Value{"loss": param.Float(0.4), "acc": param.Int(1)}},
+	}))
+
+	// each batch is written as its own chunk
+	chunks, err := repo.List("metrics/1eee")
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	require.Regexp(t, `^metrics/1eee/00000000-[0-9a-f]{8}\.json$`, chunks[0])
+	require.Regexp(t, `^metrics/1eee/00000001-[0-9a-f]{8}\.json$`, chunks[1])
+	// metadata/ is synced by ls, so the chunks aren't in it
+	chunks, err = repo.List("metadata/metrics/1eee")
+	require.NoError(t, err)
+	require.Empty(t, chunks)
+
+	metrics, err = proj.ExperimentMetrics("1eee")
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 3}, metrics.Series["loss"].Steps)
+	require.Equal(t, []param.Value{param.Float(0.5), param.Float(0.3), param.Float(0.4)}, metrics.Series["loss"].Values)
+
+	require.Equal(t, []*MetricSummary{
+		{Name: "acc", Count: 2, FirstStep: 1, LastStep: 3, Min: 0.1, Max: 1, Last: 1},
+		{Name: "loss", Count: 3, FirstStep: 1, LastStep: 3, Min: 0.3, Max: 0.5, Last: 0.4},
+	}, metrics.Summaries())
+
+	// other experiments are unaffected
+	metrics, err = proj.ExperimentMetrics("2eee")
+	require.NoError(t, err)
+	require.Empty(t, metrics.Series)
+
+	// a new project continues after the existing chunks
+	proj = NewProject(repo, t.TempDir())
+	require.NoError(t, proj.LogMetrics("1eee", []MetricPoint{
+		{Step: 4, Metrics: map[string]param.Value{"loss": param.Float(0.2)}},
+	}))
+	metrics, err = proj.ExperimentMetrics("1eee")
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 3, 4}, metrics.Series["loss"].Steps)
+}
+
+func TestLogMetricsFromSeveralProjects(t *testing.T) {
+	proj1, repo := newMetricsTestProject(t)
+	proj2 := NewProject(repo, t.TempDir())
+
+	// proj2 lists the chunks after proj1's first one, so both projects
+	// write a chunk numbered 1, which mustn't overwrite each other
+	require.NoError(t, proj1.LogMetrics("1eee", []MetricPoint{{Step: 1, Metrics: map[string]param.Value{"loss": param.Float(0.5)}}}))
+	require.NoError(t, proj2.LogMetrics("1eee", []MetricPoint{{Step: 2, Metrics: map[string]param.Value{"acc": param.Float(0.1)}}}))
+	require.NoError(t, proj1.LogMetrics("1eee", []MetricPoint{{Step: 3, Metrics: map[string]param.Value{"loss": param.Float(0.4)}}}))
+	require.NoError(t, proj2.LogMetrics("1eee", []MetricPoint{{Step: 4, Metrics: map[string]param.Value{"acc": param.Float(0.2)}}}))
+
+	chunks, err := repo.List("metrics/1eee")
+	require.NoError(t, err)
+	require.Len(t, chunks, 4)
+
+	metrics, err := proj1.ExperimentMetrics("1eee")
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 3}, metrics.Series["loss"].Steps)
+	require.Equal(t, []int64{2, 4}, metrics.Series["acc"].Steps)
+}
+
+func TestLogMetricsValidatesExperiment(t *testing.T) {
+	proj, repo := newMetricsTestProject(t)
+	points := []MetricPoint{{Step: 1, Metrics: map[string]param.Value{"loss": param.Float(0.5)}}}
+
+	require.EqualError(t, proj.LogMetrics("", points), "Experiment ID is missing")
+	require.EqualError(t, proj.LogMetrics("3eee", points), "Experiment not found: 3eee")
+
+	// experiments created after the project was loaded are found
+	require.NoError(t, (&Experiment{ID: "3eee", Created: time.Now().UTC()}).Save(repo))
+	require.NoError(t, proj.LogMetrics("3eee", points))
+}
+
+func TestLogMetricsRejectsNonNumbers(t *testing.T) {
+	proj, _ := newMetricsTestProject(t)
+
+	err := proj.LogMetrics("1eee", []MetricPoint{
+		{Step: 1, Metrics: map[string]param.Value{"loss": param.String("low")}},
+	})
+	require.EqualError(t, err, "Metric loss at step 1 must be a number, not string")
+}
+
+func TestCheckpointMetricValues(t *testing.T) {
+	checkpoints := []*Checkpoint{
+		{Metrics: param.ValueMap{"loss": param.Float(0.5), "val": param.Object(map[string]interface{}{"loss": 0.6})}},
+		{Metrics: param.ValueMap{"loss": param.Int(1)}},
+		{Metrics: param.ValueMap{"loss": param.String("high")}},
+		{},
+	}
+	values := CheckpointMetricValues(checkpoints, "loss")
+	require.Equal(t, []float64{0.5, 1}, values[:2])
+	require.True(t, math.IsNaN(values[2]))
+	require.True(t, math.IsNaN(values[3]))
+
+	values = CheckpointMetricValues(checkpoints, "val.loss")
+	require.Equal(t, 0.6, values[0])
+	require.True(t, math.IsNaN(values[1]))
+}
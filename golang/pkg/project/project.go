@@ -1,49 +1,122 @@
 package project
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
 	"os"
 	"os/user"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/replicate/keepsake/golang/pkg/config"
 	"github.com/replicate/keepsake/golang/pkg/console"
 	"github.com/replicate/keepsake/golang/pkg/errors"
 	"github.com/replicate/keepsake/golang/pkg/global"
 	"github.com/replicate/keepsake/golang/pkg/param"
 	"github.com/replicate/keepsake/golang/pkg/repository"
+	"github.com/replicate/keepsake/golang/pkg/tracing"
 )
 
 const IDLength = 64
 
 // Project is essentially a data access object for retrieving
 // metadata objects
+//
+// It is safe for concurrent use. The loaded metadata is replaced, never
+// mutated, when it is reloaded, so the maps returned by loaded() can be
+// read without holding the lock.
 type Project struct {
-	repository        repository.Repository
-	directory         string
+	*projectState
+
+	// the repository is bound to ctx, so repository operations are
+	// traced as children of the span in ctx
+	repository repository.Repository
+	ctx        context.Context
+}
+
+// projectState is shared by a project and the copies returned by
+// WithContext()
+type projectState struct {
+	directory string
+
+	specMu         sync.Mutex
+	hasCheckedSpec bool
+
+	metricsMu sync.Mutex
+	// metricsSeq is the sequence number of the next metrics chunk of each
+	// experiment, see metrics.go
+	metricsSeq map[string]int
+	// metricsWriterID is a random ID in the names of the metrics chunks
+	// that this project writes, so they don't collide with other processes'
+	metricsWriterID string
+
+	// mu guards the fields below. It isn't held while metadata is loaded
+	// from the repository.
+	mu                sync.Mutex
 	experimentsByID   map[string]*Experiment
 	heartbeatsByExpID map[string]*Heartbeat
 	hasLoaded         bool
+	// loading is closed when the load in progress finishes, or nil if
+	// nothing is being loaded
+	loading chan struct{}
+	// generation is incremented when the cache is invalidated, so a load
+	// that started before then isn't cached
+	generation int
 }
 
 func NewProject(repo repository.Repository, directory string) *Project {
 	return &Project{
+		projectState: &projectState{
+			directory: directory,
+			hasLoaded: false,
+		},
 		repository: repo,
-		directory:  directory,
-		hasLoaded:  false,
+		ctx:        context.Background(),
 	}
 }
 
+// Directory returns the directory of the project's source code
+func (p *Project) Directory() string {
+	return p.directory
+}
+
+// RepositoryURL returns the URL of the repository the project is stored in
+func (p *Project) RepositoryURL() string {
+	return p.repository.RootURL()
+}
+
+// WithContext returns a copy of the project that traces its operations as
+// children of the span in ctx. The copy shares loaded metadata with p.
+func (p *Project) WithContext(ctx context.Context) *Project {
+	return &Project{
+		projectState: p.projectState,
+		repository:   repository.WithContext(ctx, p.repository),
+		ctx:          ctx,
+	}
+}
+
+// startSpan starts a span for an operation on the project, and returns a
+// copy of the project whose operations are traced as children of it
+func (p *Project) startSpan(name string, attrs ...attribute.KeyValue) (*Project, trace.Span) {
+	ctx, span := tracing.Start(p.ctx, name, attrs...)
+	return p.WithContext(ctx), span
+}
+
 // Experiments returns all experiments in this project
 func (p *Project) Experiments() ([]*Experiment, error) {
-	if err := p.ensureLoaded(); err != nil {
+	experimentsByID, _, err := p.loaded()
+	if err != nil {
 		return nil, err
 	}
 	experiments := []*Experiment{}
-	for _, exp := range p.experimentsByID {
+	for _, exp := range experimentsByID {
 		experiments = append(experiments, exp)
 	}
 	return experiments, nil
@@ -52,28 +125,40 @@ func (p *Project) Experiments() ([]*Experiment, error) {
 // ExperimentIsRunning returns true if an experiment is still running
 // (i.e. the heartbeat has beat in the last n seconds).
 func (p *Project) ExperimentIsRunning(experimentID string) (bool, error) {
-	if err := p.ensureLoaded(); err != nil {
+	heartbeat, err := p.ExperimentHeartbeat(experimentID)
+	if err != nil || heartbeat == nil {
 		return false, err
 	}
-	heartbeat, ok := p.heartbeatsByExpID[experimentID]
+	return heartbeat.IsRunning(), nil
+}
+
+// ExperimentHeartbeat returns the heartbeat of an experiment, or nil if it
+// doesn't have one
+func (p *Project) ExperimentHeartbeat(experimentID string) (*Heartbeat, error) {
+	_, heartbeatsByExpID, err := p.loaded()
+	if err != nil {
+		return nil, err
+	}
+	heartbeat, ok := heartbeatsByExpID[experimentID]
 	if !ok {
 		// TODO(bfirsh): unknown state? https://github.com/replicate/keepsake/issues/36
 		console.Debug("No heartbeat found for experiment %s", experimentID)
-		return false, nil
+		return nil, nil
 	}
-	return heartbeat.IsRunning(), nil
+	return heartbeat, nil
 }
 
 // ExperimentFromPrefix returns an experiment that matches a given ID prefix.
 func (p *Project) ExperimentFromPrefix(prefix string) (*Experiment, error) {
-	if err := p.ensureLoaded(); err != nil {
+	experimentsByID, _, err := p.loaded()
+	if err != nil {
 		return nil, err
 	}
 
 	matches := []*Experiment{}
 
-	for id := range p.experimentsByID {
-		exp := p.experimentsByID[id]
+	for id := range experimentsByID {
+		exp := experimentsByID[id]
 		if strings.HasPrefix(id, prefix) {
 			matches = append(matches, exp)
 		}
@@ -90,10 +175,11 @@ func (p *Project) ExperimentFromPrefix(prefix string) (*Experiment, error) {
 
 // ExperimentByID returns an experiment that matches a given ID.
 func (p *Project) ExperimentByID(id string) (*Experiment, error) {
-	if err := p.ensureLoaded(); err != nil {
+	experimentsByID, _, err := p.loaded()
+	if err != nil {
 		return nil, err
 	}
-	if exp, ok := p.experimentsByID[id]; ok {
+	if exp, ok := experimentsByID[id]; ok {
 		return exp, nil
 	}
 	return nil, fmt.Errorf("Experiment not found: %s", id)
@@ -101,7 +187,8 @@ func (p *Project) ExperimentByID(id string) (*Experiment, error) {
 
 // CheckpointFromPrefix returns an experiment that matches a given ID prefix.
 func (p *Project) CheckpointFromPrefix(prefix string) (*Checkpoint, *Experiment, error) {
-	if err := p.ensureLoaded(); err != nil {
+	experimentsByID, _, err := p.loaded()
+	if err != nil {
 		return nil, nil, err
 	}
 
@@ -112,8 +199,8 @@ func (p *Project) CheckpointFromPrefix(prefix string) (*Checkpoint, *Experiment,
 
 	matches := []match{}
 
-	for id := range p.experimentsByID {
-		exp := p.experimentsByID[id]
+	for id := range experimentsByID {
+		exp := experimentsByID[id]
 		for _, checkpoint := range exp.Checkpoints {
 			if strings.HasPrefix(checkpoint.ID, prefix) {
 				matches = append(matches, match{
@@ -144,13 +231,14 @@ type CheckpointOrExperiment struct {
 // prefix. This is a single function so we can detect ambiguities
 // across both checkpoints and experiments.
 func (p *Project) CheckpointOrExperimentFromPrefix(prefix string) (*CheckpointOrExperiment, error) {
-	if err := p.ensureLoaded(); err != nil {
+	experimentsByID, _, err := p.loaded()
+	if err != nil {
 		return nil, err
 	}
 
 	matches := []*CheckpointOrExperiment{}
-	for id := range p.experimentsByID {
-		exp := p.experimentsByID[id]
+	for id := range experimentsByID {
+		exp := experimentsByID[id]
 		if strings.HasPrefix(id, prefix) {
 			matches = append(matches, &CheckpointOrExperiment{Experiment: exp})
 		}
@@ -179,6 +267,44 @@ func (p *Project) DeleteCheckpoint(chk *Checkpoint) error {
 	return nil
 }
 
+// RemoveCheckpoint removes a checkpoint from its experiment's metadata and
+// deletes its files. If the experiment is still running, its process might
+// add the checkpoint back the next time it saves the experiment.
+func (p *Project) RemoveCheckpoint(chk *Checkpoint, exp *Experiment) error {
+	updated := *exp
+	updated.Checkpoints = []*Checkpoint{}
+	for _, c := range exp.Checkpoints {
+		if c.ID != chk.ID {
+			updated.Checkpoints = append(updated.Checkpoints, c)
+		}
+	}
+	if err := updated.Save(p.repository); err != nil {
+		return err
+	}
+	return p.DeleteCheckpoint(chk)
+}
+
+// CheckpointFiles returns the paths of the files saved with a checkpoint,
+// relative to the project directory
+func (p *Project) CheckpointFiles(chk *Checkpoint) ([]string, error) {
+	if chk.Path == "" {
+		return []string{}, nil
+	}
+	tarFiles, err := p.repository.ListTarFile(chk.StorageTarPath())
+	if err != nil {
+		if errors.IsDoesNotExist(err) {
+			return nil, errors.DoesNotExist(fmt.Sprintf("Checkpoint %s is supposed to have files associated with it, but could not find the files at %q.\nMaybe it hasn't been written yet, or the repository is corrupted?", chk.ShortID(), chk.StorageTarPath()))
+		}
+		return nil, err
+	}
+	paths := make([]string, len(tarFiles))
+	for i, f := range tarFiles {
+		paths[i] = f.Path
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
 func (p *Project) DeleteExperiment(exp *Experiment) error {
 	console.Debug("Deleting experiment: %s", exp.ShortID())
 	if err := p.repository.Delete(exp.HeartbeatPath()); err != nil {
@@ -190,6 +316,9 @@ func (p *Project) DeleteExperiment(exp *Experiment) error {
 	if err := p.repository.Delete(exp.MetadataPath()); err != nil {
 		console.Warn("Failed to delete experiment metadata file %s: %s", exp.MetadataPath(), err)
 	}
+	if err := p.repository.Delete(exp.MetricsPath()); err != nil && !errors.IsDoesNotExist(err) {
+		console.Warn("Failed to delete experiment metrics directory %s: %s", exp.MetricsPath(), err)
+	}
 	p.invalidateCache()
 	return nil
 }
@@ -202,18 +331,15 @@ type CreateExperimentArgs struct {
 	PythonVersion  string
 }
 
-func (p *Project) CreateExperiment(args CreateExperimentArgs, async bool, workChan chan func() error, quiet bool) (*Experiment, error) {
-	spec, err := repository.LoadSpec(p.repository)
-	if err != nil {
+func (p *Project) CreateExperiment(args CreateExperimentArgs, async bool, workChan chan func() error, quiet bool) (_ *Experiment, err error) {
+	p, span := p.startSpan("Project.CreateExperiment")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	if err := p.ensureSpec(); err != nil {
 		return nil, err
 	}
-	if spec == nil {
-		if err := repository.WriteSpec(p.repository); err != nil {
-			return nil, err
-		}
-	} else if spec.Version > repository.Version {
-		return nil, errors.IncompatibleRepositoryVersion(p.repository.RootURL())
-	}
 
 	host := "" // currently disabled and unused
 	currentUser, err := user.Current()
@@ -251,7 +377,7 @@ func (p *Project) CreateExperiment(args CreateExperimentArgs, async bool, workCh
 		return exp, nil
 	}
 
-	tempDir, err := repository.CopyToTempDir(p.directory, exp.Path)
+	tempDir, err := repository.CopyToTempDir(p.ctx, p.directory, exp.Path)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to copy files to temporary directory: %v", err)
 	}
@@ -280,6 +406,30 @@ func (p *Project) CreateExperiment(args CreateExperimentArgs, async bool, workCh
 	return exp, nil
 }
 
+// ensureSpec writes the repository spec if it doesn't exist, and checks that
+// the repository isn't from a newer version of Keepsake if it does. It is
+// serialized so concurrent experiments don't read a partially written spec.
+func (p *Project) ensureSpec() error {
+	p.specMu.Lock()
+	defer p.specMu.Unlock()
+	if p.hasCheckedSpec {
+		return nil
+	}
+	spec, err := repository.LoadSpec(p.repository)
+	if err != nil {
+		return err
+	}
+	if spec == nil {
+		if err := repository.WriteSpec(p.repository); err != nil {
+			return err
+		}
+	} else if spec.Version > repository.Version {
+		return errors.IncompatibleRepositoryVersion(p.repository.RootURL())
+	}
+	p.hasCheckedSpec = true
+	return nil
+}
+
 type CreateCheckpointArgs struct {
 	Path          string
 	Step          int64
@@ -287,7 +437,12 @@ type CreateCheckpointArgs struct {
 	PrimaryMetric *PrimaryMetric
 }
 
-func (p *Project) CreateCheckpoint(args CreateCheckpointArgs, async bool, workChan chan func() error, quiet bool) (*Checkpoint, error) {
+func (p *Project) CreateCheckpoint(args CreateCheckpointArgs, async bool, workChan chan func() error, quiet bool) (_ *Checkpoint, err error) {
+	p, span := p.startSpan("Project.CreateCheckpoint")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	chk := &Checkpoint{
 		ID:            generateRandomID(),
 		Created:       time.Now().UTC(),
@@ -310,7 +465,7 @@ func (p *Project) CreateCheckpoint(args CreateCheckpointArgs, async bool, workCh
 		console.Info("Creating checkpoint %s, copying '%s' to '%s' in the background...", chk.ShortID(), chk.Path, p.repository.RootURL())
 	}
 
-	tempDir, err := repository.CopyToTempDir(p.directory, chk.Path)
+	tempDir, err := repository.CopyToTempDir(p.ctx, p.directory, chk.Path)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to copy files to temporary directory: %v", err)
 	}
@@ -357,47 +512,98 @@ func (p *Project) StopExperiment(experimentID string) error {
 }
 
 func (p *Project) invalidateCache() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.hasLoaded = false
+	p.generation++
 }
 
-// ensureLoaded eagerly loads all the metadata for this project.
+// loaded eagerly loads all the metadata for this project, and returns
+// experiments and heartbeats keyed by experiment ID. The returned maps must
+// not be modified.
+//
+// Concurrent callers wait for a single load rather than each loading the
+// metadata. If the cache is invalidated during a load, the next caller
+// loads it again.
+//
 // This is highly inefficient, see https://github.com/replicate/keepsake/issues/305
-func (p *Project) ensureLoaded() error {
+func (p *Project) loaded() (experimentsByID map[string]*Experiment, heartbeatsByExpID map[string]*Heartbeat, err error) {
+	p.mu.Lock()
+	for !p.hasLoaded && p.loading != nil {
+		loading := p.loading
+		p.mu.Unlock()
+		<-loading
+		p.mu.Lock()
+	}
 	// TODO(andreas): 5(?) second caching instead
 	if p.hasLoaded {
-		return nil
+		defer p.mu.Unlock()
+		return p.experimentsByID, p.heartbeatsByExpID, nil
 	}
-	experiments, err := listExperiments(p.repository)
+	loading := make(chan struct{})
+	p.loading = loading
+	generation := p.generation
+	p.mu.Unlock()
+
+	experimentsByID, heartbeatsByExpID, err = p.load()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.loading = nil
+	close(loading)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	if generation == p.generation {
+		p.experimentsByID = experimentsByID
+		p.heartbeatsByExpID = heartbeatsByExpID
+		p.hasLoaded = true
 	}
-	heartbeats, err := listHeartbeats(p.repository)
+	return experimentsByID, heartbeatsByExpID, nil
+}
+
+// load reads all the experiments and heartbeats in the repository
+func (p *Project) load() (experimentsByID map[string]*Experiment, heartbeatsByExpID map[string]*Heartbeat, err error) {
+	traced, span := p.startSpan("Project.load")
+	defer func() {
+		tracing.End(span, err)
+	}()
+	experiments, err := listExperiments(traced.ctx, traced.repository)
+	if err != nil {
+		return nil, nil, err
+	}
+	span.SetAttributes(attribute.Int("keepsake.experiments", len(experiments)))
+	heartbeats, err := listHeartbeats(traced.ctx, traced.repository)
 	if err != nil {
 		heartbeats = []*Heartbeat{}
 		console.Warn("Failed to load heartbeats: %s", err)
 	}
-	p.setObjects(experiments, heartbeats)
-	p.hasLoaded = true
-	return nil
-}
 
-func (p *Project) setObjects(experiments []*Experiment, heartbeats []*Heartbeat) {
-	p.experimentsByID = map[string]*Experiment{}
+	experimentsByID = map[string]*Experiment{}
 	for _, exp := range experiments {
-		p.experimentsByID[exp.ID] = exp
+		experimentsByID[exp.ID] = exp
 	}
-	p.heartbeatsByExpID = map[string]*Heartbeat{}
+	heartbeatsByExpID = map[string]*Heartbeat{}
 	for _, hb := range heartbeats {
-		p.heartbeatsByExpID[hb.ExperimentID] = hb
+		heartbeatsByExpID[hb.ExperimentID] = hb
 	}
+	return experimentsByID, heartbeatsByExpID, nil
 }
 
-func loadFromPath(repo repository.Repository, path string, obj interface{}) error {
+func loadFromPath(ctx context.Context, repo repository.Repository, path string, obj interface{}) error {
 	contents, err := repo.Get(path)
 	if err != nil {
 		return err
 	}
-	if err := json.Unmarshal(contents, obj); err != nil {
+	return parseJSON(ctx, path, contents, obj)
+}
+
+// parseJSON parses the metadata file at path
+func parseJSON(ctx context.Context, path string, contents []byte, obj interface{}) error {
+	_, span := tracing.Start(ctx, "parseJSON", attribute.String("keepsake.repository.path", path))
+	err := json.Unmarshal(contents, obj)
+	tracing.End(span, err)
+	if err != nil {
 		return fmt.Errorf("Parse error: %s", err)
 	}
 	return nil
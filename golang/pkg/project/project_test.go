@@ -0,0 +1,128 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/replicate/keepsake/golang/pkg/repository"
+	"github.com/replicate/keepsake/golang/pkg/tracing"
+)
+
+func TestProjectTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "train.py"), []byte("print(1)"), 0644))
+	disk, err := repository.NewDiskRepository(t.TempDir())
+	require.NoError(t, err)
+	repo := repository.NewInstrumentedRepository(disk, repository.SchemeDisk)
+
+	ctx, root := tracing.Start(context.Background(), "keepsake test")
+	proj := NewProject(repo, projectDir).WithContext(ctx)
+	_, err = proj.CreateExperiment(CreateExperimentArgs{Path: "train.py"}, false, nil, true)
+	require.NoError(t, err)
+	experiments, err := proj.Experiments()
+	require.NoError(t, err)
+	require.Len(t, experiments, 1)
+	root.End()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	parentName := func(name string) string {
+		span, ok := spans[name]
+		require.True(t, ok, "missing span %s", name)
+		for _, parent := range recorder.Ended() {
+			if parent.SpanContext().SpanID() == span.Parent().SpanID() {
+				return parent.Name()
+			}
+		}
+		return ""
+	}
+	require.Equal(t, "keepsake test", parentName("Project.CreateExperiment"))
+	require.Equal(t, "Project.CreateExperiment", parentName("CopyToTempDir"))
+	require.Equal(t, "Project.CreateExperiment", parentName("Repository.PutPathTar"))
+	require.Equal(t, "keepsake test", parentName("Project.load"))
+	require.Equal(t, "Project.load", parentName("Repository.List"))
+	require.Equal(t, "Project.load", parentName("parseJSON"))
+}
+
+// blockingRepository blocks listing experiments until unblock is closed,
+// and counts how many times they are listed
+type blockingRepository struct {
+	repository.Repository
+	unblock chan struct{}
+	lists   int32
+}
+
+func (r *blockingRepository) List(path string) ([]string, error) {
+	if strings.HasPrefix(path, "metadata/experiments") {
+		atomic.AddInt32(&r.lists, 1)
+		<-r.unblock
+	}
+	return r.Repository.List(path)
+}
+
+func TestProjectLoadsOnce(t *testing.T) {
+	disk, err := repository.NewDiskRepository(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, (&Experiment{ID: "1eee", Created: time.Now().UTC()}).Save(disk))
+	repo := &blockingRepository{Repository: disk, unblock: make(chan struct{})}
+	proj := NewProject(repo, t.TempDir())
+
+	// concurrent callers wait for a single load
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			experiments, err := proj.Experiments()
+			require.NoError(t, err)
+			require.Len(t, experiments, 1)
+		}()
+	}
+	for atomic.LoadInt32(&repo.lists) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(repo.unblock)
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(&repo.lists))
+
+	// the lock isn't held while loading, so invalidating the cache doesn't
+	// wait for the load, and the load isn't cached
+	proj.invalidateCache()
+	repo.unblock = make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := proj.Experiments()
+		require.NoError(t, err)
+	}()
+	for atomic.LoadInt32(&repo.lists) == 1 {
+		time.Sleep(time.Millisecond)
+	}
+	proj.invalidateCache()
+	close(repo.unblock)
+	<-done
+	_, err = proj.Experiments()
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&repo.lists))
+	_, err = proj.Experiments()
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&repo.lists))
+}
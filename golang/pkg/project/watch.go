@@ -0,0 +1,208 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/repository"
+	"github.com/replicate/keepsake/golang/pkg/tracing"
+)
+
+type ExperimentEventType string
+
+const (
+	EventExperimentCreated ExperimentEventType = "created"
+	EventExperimentUpdated ExperimentEventType = "updated"
+	EventCheckpointAdded   ExperimentEventType = "checkpoint_added"
+	EventExperimentStopped ExperimentEventType = "stopped"
+)
+
+// ExperimentEvent is a change to an experiment detected by a Watcher
+type ExperimentEvent struct {
+	Type       ExperimentEventType
+	Experiment *Experiment
+	// Checkpoint is the added checkpoint for EventCheckpointAdded events
+	Checkpoint *Checkpoint
+	Running    bool
+}
+
+// Watcher detects changes to the experiments in a project.
+//
+// Rather than reloading all metadata, it compares the checksums of the
+// metadata files with the previous poll and only loads the files that
+// changed. If the repository caches metadata locally, the cache is synced
+// before each poll.
+type Watcher struct {
+	repository repository.Repository
+	ctx        context.Context
+
+	experimentHashes map[string][]byte // metadata path -> MD5
+	heartbeatHashes  map[string][]byte
+	experiments      map[string]*Experiment // experiment ID -> experiment
+	heartbeats       map[string]*Heartbeat
+	running          map[string]bool
+}
+
+// Watch returns a Watcher for the experiments in this project. The first
+// call to Poll() returns a created event for every existing experiment.
+func (p *Project) Watch() *Watcher {
+	return &Watcher{
+		repository:       p.repository,
+		ctx:              p.ctx,
+		experimentHashes: map[string][]byte{},
+		heartbeatHashes:  map[string][]byte{},
+		experiments:      map[string]*Experiment{},
+		heartbeats:       map[string]*Heartbeat{},
+		running:          map[string]bool{},
+	}
+}
+
+// Poll returns the events since the last call to Poll, ordered by
+// experiment creation time. Deleted experiments are forgotten without
+// emitting an event.
+func (w *Watcher) Poll() (events []*ExperimentEvent, err error) {
+	ctx, span := tracing.Start(w.ctx, "Watcher.poll")
+	defer func() {
+		tracing.End(span, err)
+	}()
+	repo := repository.WithContext(ctx, w.repository)
+
+	if cached, ok := repo.(*repository.CachedRepository); ok {
+		if err := cached.SyncCache(); err != nil {
+			return nil, err
+		}
+	}
+
+	changedExperiments, deletedExperiments, err := w.changedPaths(repo, "metadata/experiments/", w.experimentHashes)
+	if err != nil {
+		return nil, err
+	}
+	changedHeartbeats, deletedHeartbeats, err := w.changedPaths(repo, "metadata/heartbeats/", w.heartbeatHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range deletedHeartbeats {
+		delete(w.heartbeats, idFromMetadataPath(p))
+	}
+	for _, p := range changedHeartbeats {
+		hb, err := loadHeartbeatFromPath(ctx, repo, p)
+		if err != nil {
+			// might be partially written, try again on the next poll
+			console.Debug("Failed to load metadata from %q: %s", p, err)
+			delete(w.heartbeatHashes, p)
+			continue
+		}
+		w.heartbeats[hb.ExperimentID] = hb
+	}
+
+	events = []*ExperimentEvent{}
+	for _, p := range deletedExperiments {
+		id := idFromMetadataPath(p)
+		delete(w.experiments, id)
+		delete(w.running, id)
+	}
+	for _, p := range changedExperiments {
+		exp, err := loadExperimentFromPath(ctx, repo, p)
+		if err != nil {
+			console.Debug("Failed to load metadata from %q: %s", p, err)
+			delete(w.experimentHashes, p)
+			continue
+		}
+		events = append(events, w.experimentEvents(w.experiments[exp.ID], exp)...)
+		w.experiments[exp.ID] = exp
+	}
+
+	// heartbeats expire without their files changing, so running
+	// status is checked for every experiment
+	for id, exp := range w.experiments {
+		hb, ok := w.heartbeats[id]
+		running := ok && hb.IsRunning()
+		if w.running[id] && !running {
+			events = append(events, &ExperimentEvent{Type: EventExperimentStopped, Experiment: exp})
+		}
+		w.running[id] = running
+	}
+	for _, event := range events {
+		event.Running = w.running[event.Experiment.ID]
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Experiment.Created.Before(events[j].Experiment.Created)
+	})
+	return events, nil
+}
+
+// changedPaths lists the files in a directory and returns the ones that
+// were added or changed, and the ones that were deleted, since the last
+// time hashes were updated
+func (w *Watcher) changedPaths(repo repository.Repository, dir string, hashes map[string][]byte) (changed []string, deleted []string, err error) {
+	results := make(chan repository.ListResult)
+	go repo.ListRecursive(results, dir)
+
+	seen := map[string]bool{}
+	for result := range results {
+		if result.Error != nil {
+			// drain the channel so ListRecursive can finish
+			err = result.Error
+			continue
+		}
+		seen[result.Path] = true
+		if previous, ok := hashes[result.Path]; !ok || !bytes.Equal(previous, result.MD5) {
+			changed = append(changed, result.Path)
+			hashes[result.Path] = result.MD5
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for p := range hashes {
+		if !seen[p] {
+			deleted = append(deleted, p)
+			delete(hashes, p)
+		}
+	}
+	sort.Strings(changed)
+	return changed, deleted, nil
+}
+
+// experimentEvents returns the events for an experiment whose metadata
+// changed from previous (nil if it is new) to current
+func (w *Watcher) experimentEvents(previous *Experiment, current *Experiment) []*ExperimentEvent {
+	if previous == nil {
+		return []*ExperimentEvent{{Type: EventExperimentCreated, Experiment: current}}
+	}
+
+	events := []*ExperimentEvent{}
+	previousCheckpointIDs := map[string]bool{}
+	for _, chk := range previous.Checkpoints {
+		previousCheckpointIDs[chk.ID] = true
+	}
+	for _, chk := range current.Checkpoints {
+		if !previousCheckpointIDs[chk.ID] {
+			events = append(events, &ExperimentEvent{Type: EventCheckpointAdded, Experiment: current, Checkpoint: chk})
+		}
+	}
+	if len(events) == 0 || !equalIgnoringCheckpoints(previous, current) {
+		events = append([]*ExperimentEvent{{Type: EventExperimentUpdated, Experiment: current}}, events...)
+	}
+	return events
+}
+
+func equalIgnoringCheckpoints(a *Experiment, b *Experiment) bool {
+	aCopy, bCopy := *a, *b
+	aCopy.Checkpoints, bCopy.Checkpoints = nil, nil
+	aJSON, aErr := json.Marshal(aCopy)
+	bJSON, bErr := json.Marshal(bCopy)
+	return aErr == nil && bErr == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// idFromMetadataPath returns the experiment ID from a path like
+// metadata/experiments/<id>.json
+func idFromMetadataPath(p string) string {
+	return strings.TrimSuffix(p[strings.LastIndex(p, "/")+1:], ".json")
+}
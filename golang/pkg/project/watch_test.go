@@ -0,0 +1,98 @@
+package project
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/repository"
+)
+
+func eventTypes(events []*ExperimentEvent) []ExperimentEventType {
+	types := []ExperimentEventType{}
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestWatcher(t *testing.T) {
+	repo, err := repository.NewDiskRepository(t.TempDir())
+	require.NoError(t, err)
+	proj := NewProject(repo, t.TempDir())
+
+	exp1 := &Experiment{ID: "1eeeeeeeee", Created: time.Now().UTC().Add(-time.Minute)}
+	_, err = proj.SaveExperiment(exp1, true)
+	require.NoError(t, err)
+	require.NoError(t, proj.RefreshHeartbeat(exp1.ID))
+
+	watcher := proj.Watch()
+	events, err := watcher.Poll()
+	require.NoError(t, err)
+	require.Equal(t, []ExperimentEventType{EventExperimentCreated}, eventTypes(events))
+	require.True(t, events[0].Running)
+
+	// nothing changed
+	events, err = watcher.Poll()
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	exp2 := &Experiment{ID: "2eeeeeeeee", Created: time.Now().UTC()}
+	_, err = proj.SaveExperiment(exp2, true)
+	require.NoError(t, err)
+	exp1.Checkpoints = append(exp1.Checkpoints, &Checkpoint{ID: "1ccccccccc", Step: 1})
+	_, err = proj.SaveExperiment(exp1, true)
+	require.NoError(t, err)
+	events, err = watcher.Poll()
+	require.NoError(t, err)
+	require.Equal(t, []ExperimentEventType{EventCheckpointAdded, EventExperimentCreated}, eventTypes(events))
+	require.Equal(t, "1ccccccccc", events[0].Checkpoint.ID)
+	require.Equal(t, "2eeeeeeeee", events[1].Experiment.ID)
+	require.False(t, events[1].Running)
+
+	exp1.Params = param.ValueMap{"lr": param.Float(0.1)}
+	_, err = proj.SaveExperiment(exp1, true)
+	require.NoError(t, err)
+	events, err = watcher.Poll()
+	require.NoError(t, err)
+	require.Equal(t, []ExperimentEventType{EventExperimentUpdated}, eventTypes(events))
+
+	require.NoError(t, proj.StopExperiment(exp1.ID))
+	events, err = watcher.Poll()
+	require.NoError(t, err)
+	require.Equal(t, []ExperimentEventType{EventExperimentStopped}, eventTypes(events))
+	require.Equal(t, exp1.ID, events[0].Experiment.ID)
+	require.False(t, events[0].Running)
+
+	require.NoError(t, proj.DeleteExperiment(exp2))
+	events, err = watcher.Poll()
+	require.NoError(t, err)
+	require.Empty(t, events)
+	require.NotContains(t, watcher.experiments, exp2.ID)
+}
+
+func TestWatcherExpiredHeartbeat(t *testing.T) {
+	repo, err := repository.NewDiskRepository(t.TempDir())
+	require.NoError(t, err)
+	proj := NewProject(repo, t.TempDir())
+
+	exp := &Experiment{ID: "1eeeeeeeee", Created: time.Now().UTC()}
+	_, err = proj.SaveExperiment(exp, true)
+	require.NoError(t, err)
+	require.NoError(t, proj.RefreshHeartbeat(exp.ID))
+
+	watcher := proj.Watch()
+	_, err = watcher.Poll()
+	require.NoError(t, err)
+
+	// the heartbeat file doesn't change when an experiment stops
+	// without cleaning up, it just gets old
+	defer func(interval time.Duration) { heartbeatRefreshInterval = interval }(heartbeatRefreshInterval)
+	heartbeatRefreshInterval = time.Millisecond
+	time.Sleep(10 * time.Millisecond)
+	events, err := watcher.Poll()
+	require.NoError(t, err)
+	require.Equal(t, []ExperimentEventType{EventExperimentStopped}, eventTypes(events))
+}
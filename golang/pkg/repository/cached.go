@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"context"
 	"strings"
 
 	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/tracing"
 )
 
 // CachedRepository wraps another repository, caching a prefix in a local directory.
@@ -19,6 +21,7 @@ type CachedRepository struct {
 	cacheDir        string
 	cacheRepository *DiskRepository
 	isSynced        bool
+	ctx             context.Context
 }
 
 func NewCachedRepository(repo Repository, cachePrefix string, projectDir string, cacheDir string) (*CachedRepository, error) {
@@ -33,9 +36,19 @@ func NewCachedRepository(repo Repository, cachePrefix string, projectDir string,
 		cacheDir:        cacheDir,
 		cacheRepository: cacheRepository,
 		isSynced:        false,
+		ctx:             context.Background(),
 	}, nil
 }
 
+// WithContext returns a copy of the repository that traces operations on
+// the wrapped repository, and syncs, as children of the span in ctx
+func (s *CachedRepository) WithContext(ctx context.Context) Repository {
+	clone := *s
+	clone.repository = WithContext(ctx, s.repository)
+	clone.ctx = ctx
+	return &clone
+}
+
 // NewCachedMetadataRepository returns a CachedRepository that caches the metadata/ path in
 // .keepsake/metadata-cache in a source dir
 func NewCachedMetadataRepository(projectDir string, repo Repository) (*CachedRepository, error) {
@@ -108,9 +121,9 @@ func (s *CachedRepository) List(p string) ([]string, error) {
 	return s.repository.List(p)
 }
 
-func (s *CachedRepository) ListTarFile(p string) ([]string, error) {
+func (s *CachedRepository) ListTarFile(p string) ([]*TarFile, error) {
 	if strings.HasPrefix(p, s.cachePrefix) {
-		return s.cacheRepository.List(p)
+		return s.cacheRepository.ListTarFile(p)
 	}
 	return s.repository.ListTarFile(p)
 }
@@ -145,6 +158,9 @@ func (s *CachedRepository) RootURL() string {
 }
 
 func (s *CachedRepository) SyncCache() error {
+	ctx, span := tracing.Start(s.ctx, "SyncCache")
 	console.Debug("Syncing %s/%s to %s/%s", s.repository.RootURL(), s.cachePrefix, s.cacheRepository.RootURL(), s.cachePrefix)
-	return Sync(s.repository, s.cachePrefix, s.cacheRepository, s.cachePrefix)
+	err := Sync(WithContext(ctx, s.repository), s.cachePrefix, s.cacheRepository, s.cachePrefix)
+	tracing.End(span, err)
+	return err
 }
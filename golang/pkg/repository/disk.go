@@ -172,27 +172,8 @@ func (s *DiskRepository) List(path string) ([]string, error) {
 	return result, nil
 }
 
-func (s *DiskRepository) ListTarFile(tarPath string) ([]string, error) {
-	fullTarPath := pathpkg.Join(s.rootDir, tarPath)
-	exists, err := files.FileExists(fullTarPath)
-	if err != nil {
-		return nil, err
-	}
-	if !exists {
-		return nil, errors.DoesNotExist("Path does not exist: " + fullTarPath)
-	}
-
-	files, err := getListOfFilesInTar(fullTarPath)
-	if err != nil {
-		return nil, err
-	}
-
-	tarname := filepath.Base(strings.TrimSuffix(tarPath, ".tar.gz"))
-	for idx := range files {
-		files[idx] = strings.TrimPrefix(files[idx], tarname+"/")
-	}
-
-	return files, nil
+func (s *DiskRepository) ListTarFile(tarPath string) ([]*TarFile, error) {
+	return listFilesInTar(tarPath, pathpkg.Join(s.rootDir, tarPath))
 }
 
 func (s *DiskRepository) ListRecursive(results chan<- ListResult, folder string) {
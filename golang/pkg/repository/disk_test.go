@@ -187,11 +187,13 @@ func TestDiskRepositoryListTarFile(t *testing.T) {
 	err = repository.PutPathTar(fileDir, "temp.tar.gz", "")
 	require.NoError(t, err)
 
-	paths, err := repository.ListTarFile("temp.tar.gz")
-	sort.Strings(paths)
-
+	tarFiles, err := repository.ListTarFile("temp.tar.gz")
 	require.NoError(t, err)
-	require.Equal(t, []string{"a.txt", "b.txt", "c/d.txt"}, paths)
+	sort.Slice(tarFiles, func(i, j int) bool { return tarFiles[i].Path < tarFiles[j].Path })
+	require.Equal(t, []*TarFile{{Path: "a.txt", Size: 6}, {Path: "b.txt", Size: 6}, {Path: "c/d.txt", Size: 6}}, tarFiles)
+
+	_, err = repository.ListTarFile("missing.tar.gz")
+	require.True(t, errors.IsDoesNotExist(err))
 }
 
 func TestPutPath(t *testing.T) {
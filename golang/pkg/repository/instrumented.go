@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/replicate/keepsake/golang/pkg/errors"
+	"github.com/replicate/keepsake/golang/pkg/tracing"
+)
+
+var (
+	repositoryOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "keepsake",
+		Subsystem: "repository",
+		Name:      "operation_duration_seconds",
+		Help:      "Time taken by repository operations.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 4, 8), // 5ms to ~80s
+	}, []string{"scheme", "operation"})
+
+	repositoryOperationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "keepsake",
+		Subsystem: "repository",
+		Name:      "operation_errors_total",
+		Help:      "Repository operations that failed, not counting paths that don't exist.",
+	}, []string{"scheme", "operation"})
+
+	repositoryBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "keepsake",
+		Subsystem: "repository",
+		Name:      "bytes_total",
+		Help:      "Bytes read by Get and written by Put.",
+	}, []string{"scheme", "operation"})
+)
+
+// InstrumentedRepository wraps another repository, recording Prometheus
+// metrics for every operation, labelled with the repository's scheme and
+// the name of the method. Every operation is also traced, as a child of the
+// span in the context passed to WithContext().
+type InstrumentedRepository struct {
+	repository Repository
+	scheme     Scheme
+	ctx        context.Context
+}
+
+func NewInstrumentedRepository(repo Repository, scheme Scheme) *InstrumentedRepository {
+	return &InstrumentedRepository{repository: repo, scheme: scheme, ctx: context.Background()}
+}
+
+// WithContext returns a copy of the repository that traces operations as
+// children of the span in ctx
+func (s *InstrumentedRepository) WithContext(ctx context.Context) Repository {
+	clone := *s
+	clone.ctx = ctx
+	return &clone
+}
+
+// start starts the span for an operation on path
+func (s *InstrumentedRepository) start(operation string, path string) trace.Span {
+	_, span := tracing.Start(s.ctx, "Repository."+operation,
+		attribute.String("keepsake.repository.scheme", string(s.scheme)),
+		attribute.String("keepsake.repository.path", path))
+	return span
+}
+
+// observe records the duration and outcome of an operation that started
+// at start, and ends its span
+func (s *InstrumentedRepository) observe(operation string, span trace.Span, start time.Time, err error) {
+	repositoryOperationDuration.WithLabelValues(string(s.scheme), operation).Observe(time.Since(start).Seconds())
+	// missing paths are expected, e.g. when checking if something exists
+	if errors.IsDoesNotExist(err) {
+		err = nil
+	}
+	if err != nil {
+		repositoryOperationErrors.WithLabelValues(string(s.scheme), operation).Inc()
+	}
+	tracing.End(span, err)
+}
+
+func (s *InstrumentedRepository) RootURL() string {
+	return s.repository.RootURL()
+}
+
+func (s *InstrumentedRepository) Get(path string) ([]byte, error) {
+	span := s.start("Get", path)
+	start := time.Now()
+	data, err := s.repository.Get(path)
+	s.observe("Get", span, start, err)
+	repositoryBytes.WithLabelValues(string(s.scheme), "Get").Add(float64(len(data)))
+	return data, err
+}
+
+func (s *InstrumentedRepository) GetPath(repoPath, localPath string) error {
+	span := s.start("GetPath", repoPath)
+	start := time.Now()
+	err := s.repository.GetPath(repoPath, localPath)
+	s.observe("GetPath", span, start, err)
+	return err
+}
+
+func (s *InstrumentedRepository) GetPathTar(tarPath, localPath string) error {
+	span := s.start("GetPathTar", tarPath)
+	start := time.Now()
+	err := s.repository.GetPathTar(tarPath, localPath)
+	s.observe("GetPathTar", span, start, err)
+	return err
+}
+
+func (s *InstrumentedRepository) GetPathItemTar(tarPath, itemPath, localPath string) error {
+	span := s.start("GetPathItemTar", tarPath)
+	start := time.Now()
+	err := s.repository.GetPathItemTar(tarPath, itemPath, localPath)
+	s.observe("GetPathItemTar", span, start, err)
+	return err
+}
+
+func (s *InstrumentedRepository) Put(path string, data []byte) error {
+	span := s.start("Put", path)
+	start := time.Now()
+	err := s.repository.Put(path, data)
+	s.observe("Put", span, start, err)
+	if err == nil {
+		repositoryBytes.WithLabelValues(string(s.scheme), "Put").Add(float64(len(data)))
+	}
+	return err
+}
+
+func (s *InstrumentedRepository) PutPath(localPath, repoPath string) error {
+	span := s.start("PutPath", repoPath)
+	start := time.Now()
+	err := s.repository.PutPath(localPath, repoPath)
+	s.observe("PutPath", span, start, err)
+	return err
+}
+
+func (s *InstrumentedRepository) PutPathTar(localPath, tarPath, includePath string) error {
+	span := s.start("PutPathTar", tarPath)
+	start := time.Now()
+	err := s.repository.PutPathTar(localPath, tarPath, includePath)
+	s.observe("PutPathTar", span, start, err)
+	return err
+}
+
+func (s *InstrumentedRepository) Delete(path string) error {
+	span := s.start("Delete", path)
+	start := time.Now()
+	err := s.repository.Delete(path)
+	s.observe("Delete", span, start, err)
+	return err
+}
+
+func (s *InstrumentedRepository) List(path string) ([]string, error) {
+	span := s.start("List", path)
+	start := time.Now()
+	paths, err := s.repository.List(path)
+	s.observe("List", span, start, err)
+	return paths, err
+}
+
+func (s *InstrumentedRepository) ListTarFile(path string) ([]*TarFile, error) {
+	span := s.start("ListTarFile", path)
+	start := time.Now()
+	tarFiles, err := s.repository.ListTarFile(path)
+	s.observe("ListTarFile", span, start, err)
+	return tarFiles, err
+}
+
+func (s *InstrumentedRepository) ListRecursive(results chan<- ListResult, folder string) {
+	s.observeList("ListRecursive", folder, results, func(inner chan<- ListResult) {
+		s.repository.ListRecursive(inner, folder)
+	})
+}
+
+func (s *InstrumentedRepository) MatchFilenamesRecursive(results chan<- ListResult, folder string, filename string) {
+	s.observeList("MatchFilenamesRecursive", folder, results, func(inner chan<- ListResult) {
+		s.repository.MatchFilenamesRecursive(inner, folder, filename)
+	})
+}
+
+// observeList forwards the results of a recursive listing to results, and
+// records its duration when the listing is closed
+func (s *InstrumentedRepository) observeList(operation string, folder string, results chan<- ListResult, list func(chan<- ListResult)) {
+	span := s.start(operation, folder)
+	start := time.Now()
+	inner := make(chan ListResult)
+	go list(inner)
+	var err error
+	for result := range inner {
+		if result.Error != nil && err == nil {
+			err = result.Error
+		}
+		results <- result
+	}
+	close(results)
+	s.observe(operation, span, start, err)
+}
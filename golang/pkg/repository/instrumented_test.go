@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentedRepository(t *testing.T) {
+	dir := t.TempDir()
+	disk, err := NewDiskRepository(dir)
+	require.NoError(t, err)
+	// a scheme of its own so other tests don't affect the counts
+	repo := NewInstrumentedRepository(disk, Scheme("test"))
+	durations := testutil.CollectAndCount(repositoryOperationDuration)
+
+	require.NoError(t, repo.Put("foo/bar.txt", []byte("hello")))
+	data, err := repo.Get("foo/bar.txt")
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), data)
+	require.Equal(t, 5.0, testutil.ToFloat64(repositoryBytes.WithLabelValues("test", "Put")))
+	require.Equal(t, 5.0, testutil.ToFloat64(repositoryBytes.WithLabelValues("test", "Get")))
+
+	// missing paths aren't errors
+	_, err = repo.Get("does-not-exist")
+	require.Error(t, err)
+	require.Equal(t, 0.0, testutil.ToFloat64(repositoryOperationErrors.WithLabelValues("test", "Get")))
+
+	// a directory can't be read as a file
+	require.NoError(t, os.MkdirAll(path.Join(dir, "some-dir"), 0755))
+	_, err = repo.Get("some-dir")
+	require.Error(t, err)
+	require.Equal(t, 1.0, testutil.ToFloat64(repositoryOperationErrors.WithLabelValues("test", "Get")))
+
+	// recursive listings are forwarded and closed
+	results := make(chan ListResult)
+	go repo.ListRecursive(results, "foo")
+	paths := []string{}
+	for result := range results {
+		require.NoError(t, result.Error)
+		paths = append(paths, result.Path)
+	}
+	require.Equal(t, []string{"foo/bar.txt"}, paths)
+
+	// Put, Get and ListRecursive have recorded durations
+	require.Equal(t, durations+3, testutil.CollectAndCount(repositoryOperationDuration))
+}
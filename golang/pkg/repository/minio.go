@@ -489,7 +489,7 @@ func (s *MinioRepository) List(dir string) ([]string, error) {
 	// return results, nil
 }
 
-func (s *MinioRepository) ListTarFile(tarPath string) ([]string, error) {
+func (s *MinioRepository) ListTarFile(tarPath string) ([]*TarFile, error) {
 	// archiver doesn't let us use readers, so download to temporary file
 	// TODO: make a better tar implementation
 	tmpdir, err := files.TempDir("tar")
@@ -501,25 +501,7 @@ func (s *MinioRepository) ListTarFile(tarPath string) ([]string, error) {
 	if err := s.GetPath(tarPath, tmptarball); err != nil {
 		return nil, err
 	}
-	exists, err := files.FileExists(tmptarball)
-	if err != nil {
-		return nil, err
-	}
-	if !exists {
-		return nil, errors.DoesNotExist("Path does not exist: " + tmptarball)
-	}
-
-	files, err := getListOfFilesInTar(tmptarball)
-	if err != nil {
-		return nil, err
-	}
-
-	tarname := filepath.Base(strings.TrimSuffix(tarPath, ".tar.gz"))
-	for idx := range files {
-		files[idx] = strings.TrimPrefix(files[idx], tarname+"/")
-	}
-
-	return files, nil
+	return listFilesInTar(tarPath, tmptarball)
 }
 
 // func CreateS3Bucket(region, bucket string) (err error) {
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"archive/tar"
+	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"net/url"
@@ -13,10 +15,12 @@ import (
 
 	"github.com/mholt/archiver/v3"
 	gitignore "github.com/sabhiram/go-gitignore"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/replicate/keepsake/golang/pkg/console"
 	"github.com/replicate/keepsake/golang/pkg/errors"
 	"github.com/replicate/keepsake/golang/pkg/files"
+	"github.com/replicate/keepsake/golang/pkg/tracing"
 )
 
 var maxWorkers = 128
@@ -90,9 +94,9 @@ type Repository interface {
 
 	// List files in a tar-file
 	//
-	// Returns a list of paths, present inside the give tarfile, that can be passed straight to GetPathItemTar()
+	// Returns the files inside the given tarfile, with their sizes. Their paths can be passed straight to GetPathItemTar()
 	// Directories are not listed.
-	ListTarFile(path string) ([]string, error)
+	ListTarFile(path string) ([]*TarFile, error)
 
 	// List files in a path recursively
 	ListRecursive(results chan<- ListResult, folder string)
@@ -100,6 +104,17 @@ type Repository interface {
 	MatchFilenamesRecursive(results chan<- ListResult, folder string, filename string)
 }
 
+// WithContext returns repo, tracing its operations as children of the
+// span in ctx if it supports tracing
+func WithContext(ctx context.Context, repo Repository) Repository {
+	if r, ok := repo.(interface {
+		WithContext(context.Context) Repository
+	}); ok {
+		return r.WithContext(ctx)
+	}
+	return repo
+}
+
 // SplitURL splits a repository URL into <scheme>://<path>
 func SplitURL(repositoryURL string) (scheme Scheme, bucket string, root string, err error) {
 	u, err := url.Parse(repositoryURL)
@@ -122,27 +137,34 @@ func SplitURL(repositoryURL string) (scheme Scheme, bucket string, root string,
 	return "", "", "", unknownRepositoryScheme(u.Scheme)
 }
 
+// ForURL returns the repository for a URL. Its operations are recorded as
+// Prometheus metrics, see InstrumentedRepository.
 func ForURL(repositoryURL string, projectDir string) (Repository, error) {
 	scheme, bucket, root, err := SplitURL(repositoryURL)
 	if err != nil {
 		return nil, err
 	}
+	var repo Repository
 	switch scheme {
 	case SchemeDisk:
 		if !filepath.IsAbs(root) {
 			root = path.Join(projectDir, root)
 		}
-		return NewDiskRepository(root)
+		repo, err = NewDiskRepository(root)
 	case SchemeS3:
-		return NewS3Repository(bucket, root)
+		repo, err = NewS3Repository(bucket, root)
 	case SchemeGCS:
-		return NewGCSRepository(bucket, root)
+		repo, err = NewGCSRepository(bucket, root)
 	case SchemeMinio:
 		// minio use full url with username nad password in url
-		return NewMinioRepository(repositoryURL, root)
+		repo, err = NewMinioRepository(repositoryURL, root)
+	default:
+		return nil, unknownRepositoryScheme(string(scheme))
 	}
-
-	return nil, unknownRepositoryScheme(string(scheme))
+	if err != nil {
+		return nil, err
+	}
+	return NewInstrumentedRepository(repo, scheme), nil
 }
 
 // FIXME: should we keep on doing this?
@@ -286,6 +308,53 @@ func getListOfFilesInTar(tarPath string) ([]string, error) {
 	return result, err
 }
 
+// TarFile is a file in a tarball
+type TarFile struct {
+	Path string
+	Size int64
+}
+
+// listFilesInTar returns the files in the local tarball localTarPath,
+// which is stored at tarPath in a repository. The first component of each
+// path is stripped, and directories are not listed. The tarball is read as
+// a stream, so only headers are kept in memory.
+func listFilesInTar(tarPath, localTarPath string) ([]*TarFile, error) {
+	f, err := os.Open(localTarPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.DoesNotExist("Path does not exist: " + tarPath)
+		}
+		return nil, errors.ReadError(err.Error())
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.ReadError(fmt.Sprintf("Failed to read %s: %s", tarPath, err))
+	}
+	defer gz.Close()
+
+	result := []*TarFile{}
+	tarname := filepath.Base(strings.TrimSuffix(tarPath, ".tar.gz"))
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.ReadError(fmt.Sprintf("Failed to read %s: %s", tarPath, err))
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		result = append(result, &TarFile{
+			Path: strings.TrimPrefix(header.Name, tarname+"/"),
+			Size: header.Size,
+		})
+	}
+	return result, nil
+}
+
 func extractTarItem(tarPath, itemPath, localPath string) error {
 	tarBaseName := filepath.Base(strings.TrimSuffix(tarPath, ".tar.gz"))
 	fullItemPath := path.Join(tarBaseName, itemPath)
@@ -295,10 +364,11 @@ func extractTarItem(tarPath, itemPath, localPath string) error {
 		return err
 	}
 
-	// Check if itemPath is inside the tar
+	// Check if itemPath is inside the tar, either as a file or as a
+	// directory, so "config" doesn't match "config.yaml"
 	itemPathExists := false
 	for _, fileInTar := range filesInTar {
-		if strings.HasPrefix(fileInTar, fullItemPath) {
+		if fileInTar == fullItemPath || strings.HasPrefix(fileInTar, strings.TrimSuffix(fullItemPath, "/")+"/") {
 			itemPathExists = true
 			break
 		}
@@ -387,7 +457,15 @@ func isVirtualenvDir(path string) (bool, error) {
 	return files.FileExists(filepath.Join(path, "pyvenv.cfg"))
 }
 
-func CopyToTempDir(localPath string, includePath string) (tempDir string, err error) {
+// CopyToTempDir copies includePath in localPath to a new temporary
+// directory, so it can be uploaded in the background while the files in
+// localPath change. It is traced as a child of the span in ctx.
+func CopyToTempDir(ctx context.Context, localPath string, includePath string) (tempDir string, err error) {
+	ctx, span := tracing.Start(ctx, "CopyToTempDir", attribute.String("keepsake.path", includePath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	// normalize path
 	includePath = filepath.Join(includePath)
 
@@ -402,10 +480,13 @@ func CopyToTempDir(localPath string, includePath string) (tempDir string, err er
 	// we first scan the whole repository to get the list of eligable files,
 	// then copy the ones that match the includePath.
 	// TODO(andreas): only scan files in the includePath
+	_, scanSpan := tracing.Start(ctx, "CopyToTempDir.scan")
 	filesToCopy, err := getListOfFilesToPut(localPath, tempDir)
+	tracing.End(scanSpan, err)
 	if err != nil {
 		return "", err
 	}
+	span.SetAttributes(attribute.Int("keepsake.files_scanned", len(filesToCopy)))
 	count := 0
 	for _, file := range filesToCopy {
 
@@ -434,6 +515,7 @@ func CopyToTempDir(localPath string, includePath string) (tempDir string, err er
 		count += 1
 	}
 
+	span.SetAttributes(attribute.Int("keepsake.files_copied", count))
 	if count == 0 {
 		return "", fmt.Errorf("No files matched '%s' in %s", includePath, localPath)
 	}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
@@ -149,6 +150,10 @@ func TestExtractTarItem(t *testing.T) {
 	// Extract a file that does not exist
 	err = extractTarItem(path.Join(dir, "temp.tar.gz"), "does-not-exist.txt", tmpDir)
 	require.True(t, errors.IsDoesNotExist(err))
+
+	// A path that is only the start of a file's path does not exist
+	err = extractTarItem(path.Join(dir, "temp.tar.gz"), "a.t", tmpDir)
+	require.True(t, errors.IsDoesNotExist(err))
 }
 
 func TestCopyToTempDir(t *testing.T) {
@@ -164,7 +169,7 @@ func TestCopyToTempDir(t *testing.T) {
 	require.NoError(t, err)
 
 	// without includePath
-	tempDir, err := CopyToTempDir(dir, ".")
+	tempDir, err := CopyToTempDir(context.Background(), dir, ".")
 	require.NoError(t, err)
 	defer os.RemoveAll(tempDir)
 
@@ -177,7 +182,7 @@ func TestCopyToTempDir(t *testing.T) {
 	require.Equal(t, "bar", string(contents))
 
 	// with directory includePath
-	tempDir, err = CopyToTempDir(dir, "my")
+	tempDir, err = CopyToTempDir(context.Background(), dir, "my")
 	require.NoError(t, err)
 	defer os.RemoveAll(tempDir)
 
@@ -190,7 +195,7 @@ func TestCopyToTempDir(t *testing.T) {
 	require.Equal(t, "bar", string(contents))
 
 	// with file includePath
-	tempDir, err = CopyToTempDir(dir, "my/folder/bar")
+	tempDir, err = CopyToTempDir(context.Background(), dir, "my/folder/bar")
 	require.NoError(t, err)
 	defer os.RemoveAll(tempDir)
 
@@ -203,6 +208,6 @@ func TestCopyToTempDir(t *testing.T) {
 	require.Equal(t, "bar", string(contents))
 
 	// with missing file
-	_, err = CopyToTempDir(dir, "not-existing")
+	_, err = CopyToTempDir(context.Background(), dir, "not-existing")
 	require.Error(t, err)
 }
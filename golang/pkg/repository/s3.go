@@ -327,7 +327,7 @@ func (s *S3Repository) List(dir string) ([]string, error) {
 	return results, nil
 }
 
-func (s *S3Repository) ListTarFile(tarPath string) ([]string, error) {
+func (s *S3Repository) ListTarFile(tarPath string) ([]*TarFile, error) {
 	// archiver doesn't let us use readers, so download to temporary file
 	// TODO: make a better tar implementation
 	tmpdir, err := files.TempDir("tar")
@@ -339,25 +339,7 @@ func (s *S3Repository) ListTarFile(tarPath string) ([]string, error) {
 	if err := s.GetPath(tarPath, tmptarball); err != nil {
 		return nil, err
 	}
-	exists, err := files.FileExists(tmptarball)
-	if err != nil {
-		return nil, err
-	}
-	if !exists {
-		return nil, errors.DoesNotExist("Path does not exist: " + tmptarball)
-	}
-
-	files, err := getListOfFilesInTar(tmptarball)
-	if err != nil {
-		return nil, err
-	}
-
-	tarname := filepath.Base(strings.TrimSuffix(tarPath, ".tar.gz"))
-	for idx := range files {
-		files[idx] = strings.TrimPrefix(files[idx], tarname+"/")
-	}
-
-	return files, nil
+	return listFilesInTar(tarPath, tmptarball)
 }
 
 func CreateS3Bucket(region, bucket string) (err error) {
@@ -0,0 +1,308 @@
+// Package convert converts between the daemon's protobuf messages and the
+// types in the project and param packages, for the daemon and its clients
+package convert
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/replicate/keepsake/golang/pkg/config"
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+)
+
+// convert from protobuf
+
+func CheckpointsFromPb(checkpointsPb []*servicepb.Checkpoint) []*project.Checkpoint {
+	if checkpointsPb == nil {
+		return nil
+	}
+	ret := make([]*project.Checkpoint, len(checkpointsPb))
+	for i, chkPb := range checkpointsPb {
+		ret[i] = CheckpointFromPb(chkPb)
+	}
+	return ret
+}
+
+func CheckpointFromPb(chkPb *servicepb.Checkpoint) *project.Checkpoint {
+	return &project.Checkpoint{
+		ID:            chkPb.Id,
+		Created:       chkPb.Created.AsTime(),
+		Metrics:       ValueMapFromPb(chkPb.Metrics),
+		Step:          chkPb.Step,
+		Path:          chkPb.Path,
+		PrimaryMetric: PrimaryMetricFromPb(chkPb.PrimaryMetric),
+	}
+}
+
+func ExperimentFromPb(expPb *servicepb.Experiment) *project.Experiment {
+	return &project.Experiment{
+		ID:              expPb.Id,
+		Created:         expPb.Created.AsTime(),
+		Params:          ValueMapFromPb(expPb.Params),
+		Host:            expPb.Host,
+		User:            expPb.User,
+		Config:          ConfigFromPb(expPb.Config),
+		Command:         expPb.Command,
+		Path:            expPb.Path,
+		PythonPackages:  expPb.PythonPackages,
+		PythonVersion:   expPb.PythonVersion,
+		Checkpoints:     CheckpointsFromPb(expPb.Checkpoints),
+		KeepsakeVersion: expPb.KeepsakeVersion,
+	}
+}
+
+func ConfigFromPb(confPb *servicepb.Config) *config.Config {
+	var conf *config.Config
+	if confPb != nil {
+		conf = &config.Config{Repository: confPb.Repository, Storage: confPb.Storage}
+	}
+	return conf
+}
+
+func PrimaryMetricFromPb(pmPb *servicepb.PrimaryMetric) *project.PrimaryMetric {
+	if pmPb == nil {
+		return nil
+	}
+	var goal project.MetricGoal
+	switch pmPb.Goal {
+	case servicepb.PrimaryMetric_MAXIMIZE:
+		goal = project.GoalMaximize
+	case servicepb.PrimaryMetric_MINIMIZE:
+		goal = project.GoalMinimize
+	}
+	return &project.PrimaryMetric{
+		Name: pmPb.Name,
+		Goal: goal,
+	}
+}
+
+func MetricPointsFromPb(pointsPb []*servicepb.MetricPoint) []project.MetricPoint {
+	points := []project.MetricPoint{}
+	for _, pointPb := range pointsPb {
+		points = append(points, project.MetricPoint{
+			Step:    pointPb.Step,
+			Metrics: ValueMapFromPb(pointPb.Metrics),
+		})
+	}
+	return points
+}
+
+func ValueMapFromPb(pb map[string]*servicepb.ParamType) map[string]param.Value {
+	if len(pb) == 0 {
+		return nil
+	}
+
+	params := map[string]param.Value{}
+	for k, v := range pb {
+		params[k] = ValueFromPb(v)
+	}
+	return params
+}
+
+func ValueFromPb(pb *servicepb.ParamType) param.Value {
+	switch pb.Value.(type) {
+	case *servicepb.ParamType_BoolValue:
+		return param.Bool(pb.GetBoolValue())
+	case *servicepb.ParamType_IntValue:
+		return param.Int(pb.GetIntValue())
+	case *servicepb.ParamType_FloatValue:
+		return param.Float(pb.GetFloatValue())
+	case *servicepb.ParamType_StringValue:
+		return param.String(pb.GetStringValue())
+	case *servicepb.ParamType_ObjectValueJson:
+		return param.ParseFromString(pb.GetObjectValueJson())
+	}
+	panic(fmt.Sprintf("Unknown param type: %v", pb)) // should never happen
+}
+
+func DiffSectionsFromPb(sectionsPb []*servicepb.DiffSection) []*project.DiffSection {
+	sections := []*project.DiffSection{}
+	for _, sectionPb := range sectionsPb {
+		section := &project.DiffSection{Name: sectionPb.Name, Differences: []*project.Difference{}}
+		for _, diffPb := range sectionPb.Differences {
+			diff := &project.Difference{Key: diffPb.Key}
+			if diffPb.Left != nil {
+				left := ValueFromPb(diffPb.Left)
+				diff.Left = &left
+			}
+			if diffPb.Right != nil {
+				right := ValueFromPb(diffPb.Right)
+				diff.Right = &right
+			}
+			section.Differences = append(section.Differences, diff)
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+// convert to protobuf
+
+func ExperimentsToPb(experiments []*project.Experiment) []*servicepb.Experiment {
+	ret := make([]*servicepb.Experiment, len(experiments))
+	for i, exp := range experiments {
+		ret[i] = ExperimentToPb(exp)
+	}
+	return ret
+}
+
+func ExperimentToPb(exp *project.Experiment) *servicepb.Experiment {
+	return &servicepb.Experiment{
+		Id:              exp.ID,
+		Created:         timestamppb.New(exp.Created),
+		Params:          ValueMapToPb(exp.Params),
+		Host:            exp.Host,
+		User:            exp.User,
+		Config:          ConfigToPb(exp.Config),
+		Command:         exp.Command,
+		Path:            exp.Path,
+		PythonPackages:  exp.PythonPackages,
+		PythonVersion:   exp.PythonVersion,
+		KeepsakeVersion: exp.KeepsakeVersion,
+		Checkpoints:     CheckpointsToPb(exp.Checkpoints),
+	}
+}
+
+func ConfigToPb(conf *config.Config) *servicepb.Config {
+	if conf == nil {
+		return nil
+	}
+	return &servicepb.Config{
+		Repository: conf.Repository,
+		Storage:    conf.Storage, // deprecated
+	}
+}
+
+func CheckpointsToPb(checkpoints []*project.Checkpoint) []*servicepb.Checkpoint {
+	if checkpoints == nil {
+		return nil
+	}
+	ret := make([]*servicepb.Checkpoint, len(checkpoints))
+	for i, chk := range checkpoints {
+		ret[i] = CheckpointToPb(chk)
+	}
+	return ret
+}
+
+func CheckpointToPb(chk *project.Checkpoint) *servicepb.Checkpoint {
+	if chk == nil {
+		return nil
+	}
+	return &servicepb.Checkpoint{
+		Id:            chk.ID,
+		Created:       timestamppb.New(chk.Created),
+		Step:          chk.Step,
+		Metrics:       ValueMapToPb(chk.Metrics),
+		Path:          chk.Path,
+		PrimaryMetric: PrimaryMetricToPb(chk.PrimaryMetric),
+	}
+}
+
+func PrimaryMetricToPb(pm *project.PrimaryMetric) *servicepb.PrimaryMetric {
+	var pbPrimaryMetric *servicepb.PrimaryMetric
+	if pm != nil {
+		var goal servicepb.PrimaryMetric_Goal
+		switch pm.Goal {
+		case project.GoalMaximize:
+			goal = servicepb.PrimaryMetric_MAXIMIZE
+		case project.GoalMinimize:
+			goal = servicepb.PrimaryMetric_MINIMIZE
+		}
+
+		pbPrimaryMetric = &servicepb.PrimaryMetric{
+			Name: pm.Name,
+			Goal: goal,
+		}
+	}
+	return pbPrimaryMetric
+}
+
+func MetricPointsToPb(points []project.MetricPoint) []*servicepb.MetricPoint {
+	pointsPb := []*servicepb.MetricPoint{}
+	for _, point := range points {
+		pointsPb = append(pointsPb, &servicepb.MetricPoint{
+			Step:    point.Step,
+			Metrics: ValueMapToPb(point.Metrics),
+		})
+	}
+	return pointsPb
+}
+
+func ValueMapToPb(m map[string]param.Value) map[string]*servicepb.ParamType {
+	if len(m) == 0 {
+		return nil
+	}
+
+	pbMap := map[string]*servicepb.ParamType{}
+	for k, v := range m {
+		pbMap[k] = ValueToPb(v)
+	}
+	return pbMap
+}
+
+func ValueToPb(v param.Value) *servicepb.ParamType {
+	switch v.Type() {
+	case param.TypeBool:
+		return &servicepb.ParamType{Value: &servicepb.ParamType_BoolValue{BoolValue: v.BoolVal()}}
+	case param.TypeInt:
+		return &servicepb.ParamType{Value: &servicepb.ParamType_IntValue{IntValue: v.IntVal()}}
+	case param.TypeFloat:
+		return &servicepb.ParamType{Value: &servicepb.ParamType_FloatValue{FloatValue: v.FloatVal()}}
+	case param.TypeString:
+		return &servicepb.ParamType{Value: &servicepb.ParamType_StringValue{StringValue: v.StringVal()}}
+	case param.TypeObject:
+		return &servicepb.ParamType{Value: &servicepb.ParamType_ObjectValueJson{ObjectValueJson: v.String()}}
+	case param.TypeTime:
+		return &servicepb.ParamType{Value: &servicepb.ParamType_StringValue{StringValue: v.String()}}
+	case param.TypeDuration:
+		return &servicepb.ParamType{Value: &servicepb.ParamType_FloatValue{FloatValue: v.DurationVal().Seconds()}}
+	case param.TypeNone:
+		return &servicepb.ParamType{Value: &servicepb.ParamType_ObjectValueJson{ObjectValueJson: "null"}}
+	}
+	panic("Uninitiazlied param.Value") // should never happen
+}
+
+func ExperimentEventToPb(event *project.ExperimentEvent) *servicepb.ExperimentEvent {
+	var eventType servicepb.ExperimentEvent_Type
+	switch event.Type {
+	case project.EventExperimentCreated:
+		eventType = servicepb.ExperimentEvent_CREATED
+	case project.EventExperimentUpdated:
+		eventType = servicepb.ExperimentEvent_UPDATED
+	case project.EventCheckpointAdded:
+		eventType = servicepb.ExperimentEvent_CHECKPOINT_ADDED
+	case project.EventExperimentStopped:
+		eventType = servicepb.ExperimentEvent_STOPPED
+	}
+	eventPb := &servicepb.ExperimentEvent{
+		Type:       eventType,
+		Experiment: ExperimentToPb(event.Experiment),
+		Running:    event.Running,
+	}
+	if event.Checkpoint != nil {
+		eventPb.Checkpoint = CheckpointToPb(event.Checkpoint)
+	}
+	return eventPb
+}
+
+func DiffSectionsToPb(sections []*project.DiffSection) []*servicepb.DiffSection {
+	sectionsPb := []*servicepb.DiffSection{}
+	for _, section := range sections {
+		sectionPb := &servicepb.DiffSection{Name: section.Name}
+		for _, diff := range section.Differences {
+			diffPb := &servicepb.Difference{Key: diff.Key}
+			if diff.Left != nil {
+				diffPb.Left = ValueToPb(*diff.Left)
+			}
+			if diff.Right != nil {
+				diffPb.Right = ValueToPb(*diff.Right)
+			}
+			sectionPb.Differences = append(sectionPb.Differences, diffPb)
+		}
+		sectionsPb = append(sectionsPb, sectionPb)
+	}
+	return sectionsPb
+}
@@ -1,6 +1,7 @@
-package shared
+package convert
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -47,7 +48,7 @@ func fullCheckpoint() *project.Checkpoint {
 			"mystring": param.String("value"),
 			"mytrue":   param.Bool(true),
 			"myfalse":  param.Bool(false),
-			"mylist":   param.Object([]interface{}{1.0, 2.0, 3.0}),
+			"mylist":   param.Object([]interface{}{json.Number("1"), json.Number("2"), json.Number("3")}),
 			"mymap":    param.Object(map[string]interface{}{"bar": "baz"}),
 		},
 		PrimaryMetric: &project.PrimaryMetric{Name: "myfloat", Goal: "maximize"},
@@ -122,7 +123,7 @@ func fullExperiment() *project.Experiment {
 			"mystring": param.String("value"),
 			"mytrue":   param.Bool(true),
 			"myfalse":  param.Bool(false),
-			"mylist":   param.Object([]interface{}{1.0, 2.0, 3.0}),
+			"mylist":   param.Object([]interface{}{json.Number("1"), json.Number("2"), json.Number("3")}),
 			"mymap":    param.Object(map[string]interface{}{"bar": "baz"}),
 		},
 		PythonPackages:  map[string]string{"pkg1": "1.1", "pkg2": "2.2"},
@@ -151,47 +152,47 @@ func emptyExperiment() *project.Experiment {
 func TestConvertCheckpointFromPb(t *testing.T) {
 	chkPb := fullCheckpointPb()
 	expected := fullCheckpoint()
-	require.Equal(t, expected, checkpointFromPb(chkPb))
+	require.Equal(t, expected, CheckpointFromPb(chkPb))
 }
 
 func TestConvertEmptyCheckpointFromPb(t *testing.T) {
 	chkPb := emptyCheckpointPb()
 	expected := emptyCheckpoint()
-	require.Equal(t, expected, checkpointFromPb(chkPb))
+	require.Equal(t, expected, CheckpointFromPb(chkPb))
 }
 
 func TestConvertExperimentFromPb(t *testing.T) {
 	expPb := fullExperimentPb()
 	expected := fullExperiment()
-	require.Equal(t, expected, experimentFromPb(expPb))
+	require.Equal(t, expected, ExperimentFromPb(expPb))
 }
 
 func TestConvertEmptyExperimentFromPb(t *testing.T) {
 	expPb := emptyExperimentPb()
 	expected := emptyExperiment()
-	require.Equal(t, expected, experimentFromPb(expPb))
+	require.Equal(t, expected, ExperimentFromPb(expPb))
 }
 
 func TestConvertCheckpointToPb(t *testing.T) {
 	chk := fullCheckpoint()
 	expected := fullCheckpointPb()
-	require.Equal(t, expected, checkpointToPb(chk))
+	require.Equal(t, expected, CheckpointToPb(chk))
 }
 
 func TestConvertEmptyCheckpointToPb(t *testing.T) {
 	chk := emptyCheckpoint()
 	expected := emptyCheckpointPb()
-	require.Equal(t, expected, checkpointToPb(chk))
+	require.Equal(t, expected, CheckpointToPb(chk))
 }
 
 func TestConvertExperimentToPb(t *testing.T) {
 	exp := fullExperiment()
 	expected := fullExperimentPb()
-	require.Equal(t, expected, experimentToPb(exp))
+	require.Equal(t, expected, ExperimentToPb(exp))
 }
 
 func TestConvertEmptyExperimentToPb(t *testing.T) {
 	exp := emptyExperiment()
 	expected := emptyExperimentPb()
-	require.Equal(t, expected, experimentToPb(exp))
+	require.Equal(t, expected, ExperimentToPb(exp))
 }
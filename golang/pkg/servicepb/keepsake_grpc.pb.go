@@ -27,6 +27,14 @@ type DaemonClient interface {
 	DeleteExperiment(ctx context.Context, in *DeleteExperimentRequest, opts ...grpc.CallOption) (*DeleteExperimentReply, error)
 	CheckoutCheckpoint(ctx context.Context, in *CheckoutCheckpointRequest, opts ...grpc.CallOption) (*CheckoutCheckpointReply, error)
 	GetExperimentStatus(ctx context.Context, in *GetExperimentStatusRequest, opts ...grpc.CallOption) (*GetExperimentStatusReply, error)
+	LogMetrics(ctx context.Context, in *LogMetricsRequest, opts ...grpc.CallOption) (*LogMetricsReply, error)
+	WatchExperiments(ctx context.Context, in *WatchExperimentsRequest, opts ...grpc.CallOption) (Daemon_WatchExperimentsClient, error)
+	GetCheckpoint(ctx context.Context, in *GetCheckpointRequest, opts ...grpc.CallOption) (*GetCheckpointReply, error)
+	DeleteCheckpoint(ctx context.Context, in *DeleteCheckpointRequest, opts ...grpc.CallOption) (*DeleteCheckpointReply, error)
+	ListCheckpointFiles(ctx context.Context, in *ListCheckpointFilesRequest, opts ...grpc.CallOption) (*ListCheckpointFilesReply, error)
+	CheckoutPath(ctx context.Context, in *CheckoutPathRequest, opts ...grpc.CallOption) (*CheckoutPathReply, error)
+	DiffCheckpoints(ctx context.Context, in *DiffCheckpointsRequest, opts ...grpc.CallOption) (*DiffCheckpointsReply, error)
+	Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainReply, error)
 }
 
 type daemonClient struct {
@@ -118,6 +126,101 @@ func (c *daemonClient) GetExperimentStatus(ctx context.Context, in *GetExperimen
 	return out, nil
 }
 
+func (c *daemonClient) LogMetrics(ctx context.Context, in *LogMetricsRequest, opts ...grpc.CallOption) (*LogMetricsReply, error) {
+	out := new(LogMetricsReply)
+	err := c.cc.Invoke(ctx, "/service.Daemon/LogMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) WatchExperiments(ctx context.Context, in *WatchExperimentsRequest, opts ...grpc.CallOption) (Daemon_WatchExperimentsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Daemon_serviceDesc.Streams[0], "/service.Daemon/WatchExperiments", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daemonWatchExperimentsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Daemon_WatchExperimentsClient interface {
+	Recv() (*ExperimentEvent, error)
+	grpc.ClientStream
+}
+
+type daemonWatchExperimentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *daemonWatchExperimentsClient) Recv() (*ExperimentEvent, error) {
+	m := new(ExperimentEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *daemonClient) GetCheckpoint(ctx context.Context, in *GetCheckpointRequest, opts ...grpc.CallOption) (*GetCheckpointReply, error) {
+	out := new(GetCheckpointReply)
+	err := c.cc.Invoke(ctx, "/service.Daemon/GetCheckpoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) DeleteCheckpoint(ctx context.Context, in *DeleteCheckpointRequest, opts ...grpc.CallOption) (*DeleteCheckpointReply, error) {
+	out := new(DeleteCheckpointReply)
+	err := c.cc.Invoke(ctx, "/service.Daemon/DeleteCheckpoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) ListCheckpointFiles(ctx context.Context, in *ListCheckpointFilesRequest, opts ...grpc.CallOption) (*ListCheckpointFilesReply, error) {
+	out := new(ListCheckpointFilesReply)
+	err := c.cc.Invoke(ctx, "/service.Daemon/ListCheckpointFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) CheckoutPath(ctx context.Context, in *CheckoutPathRequest, opts ...grpc.CallOption) (*CheckoutPathReply, error) {
+	out := new(CheckoutPathReply)
+	err := c.cc.Invoke(ctx, "/service.Daemon/CheckoutPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) DiffCheckpoints(ctx context.Context, in *DiffCheckpointsRequest, opts ...grpc.CallOption) (*DiffCheckpointsReply, error) {
+	out := new(DiffCheckpointsReply)
+	err := c.cc.Invoke(ctx, "/service.Daemon/DiffCheckpoints", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonClient) Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainReply, error) {
+	out := new(DrainReply)
+	err := c.cc.Invoke(ctx, "/service.Daemon/Drain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DaemonServer is the server API for Daemon service.
 // All implementations must embed UnimplementedDaemonServer
 // for forward compatibility
@@ -131,6 +234,14 @@ type DaemonServer interface {
 	DeleteExperiment(context.Context, *DeleteExperimentRequest) (*DeleteExperimentReply, error)
 	CheckoutCheckpoint(context.Context, *CheckoutCheckpointRequest) (*CheckoutCheckpointReply, error)
 	GetExperimentStatus(context.Context, *GetExperimentStatusRequest) (*GetExperimentStatusReply, error)
+	LogMetrics(context.Context, *LogMetricsRequest) (*LogMetricsReply, error)
+	WatchExperiments(*WatchExperimentsRequest, Daemon_WatchExperimentsServer) error
+	GetCheckpoint(context.Context, *GetCheckpointRequest) (*GetCheckpointReply, error)
+	DeleteCheckpoint(context.Context, *DeleteCheckpointRequest) (*DeleteCheckpointReply, error)
+	ListCheckpointFiles(context.Context, *ListCheckpointFilesRequest) (*ListCheckpointFilesReply, error)
+	CheckoutPath(context.Context, *CheckoutPathRequest) (*CheckoutPathReply, error)
+	DiffCheckpoints(context.Context, *DiffCheckpointsRequest) (*DiffCheckpointsReply, error)
+	Drain(context.Context, *DrainRequest) (*DrainReply, error)
 	mustEmbedUnimplementedDaemonServer()
 }
 
@@ -165,6 +276,30 @@ func (UnimplementedDaemonServer) CheckoutCheckpoint(context.Context, *CheckoutCh
 func (UnimplementedDaemonServer) GetExperimentStatus(context.Context, *GetExperimentStatusRequest) (*GetExperimentStatusReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetExperimentStatus not implemented")
 }
+func (UnimplementedDaemonServer) LogMetrics(context.Context, *LogMetricsRequest) (*LogMetricsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LogMetrics not implemented")
+}
+func (UnimplementedDaemonServer) WatchExperiments(*WatchExperimentsRequest, Daemon_WatchExperimentsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchExperiments not implemented")
+}
+func (UnimplementedDaemonServer) GetCheckpoint(context.Context, *GetCheckpointRequest) (*GetCheckpointReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCheckpoint not implemented")
+}
+func (UnimplementedDaemonServer) DeleteCheckpoint(context.Context, *DeleteCheckpointRequest) (*DeleteCheckpointReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCheckpoint not implemented")
+}
+func (UnimplementedDaemonServer) ListCheckpointFiles(context.Context, *ListCheckpointFilesRequest) (*ListCheckpointFilesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCheckpointFiles not implemented")
+}
+func (UnimplementedDaemonServer) CheckoutPath(context.Context, *CheckoutPathRequest) (*CheckoutPathReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckoutPath not implemented")
+}
+func (UnimplementedDaemonServer) DiffCheckpoints(context.Context, *DiffCheckpointsRequest) (*DiffCheckpointsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffCheckpoints not implemented")
+}
+func (UnimplementedDaemonServer) Drain(context.Context, *DrainRequest) (*DrainReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Drain not implemented")
+}
 func (UnimplementedDaemonServer) mustEmbedUnimplementedDaemonServer() {}
 
 // UnsafeDaemonServer may be embedded to opt out of forward compatibility for this service.
@@ -340,6 +475,153 @@ func _Daemon_GetExperimentStatus_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Daemon_LogMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).LogMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.Daemon/LogMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).LogMetrics(ctx, req.(*LogMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_WatchExperiments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchExperimentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaemonServer).WatchExperiments(m, &daemonWatchExperimentsServer{stream})
+}
+
+type Daemon_WatchExperimentsServer interface {
+	Send(*ExperimentEvent) error
+	grpc.ServerStream
+}
+
+type daemonWatchExperimentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *daemonWatchExperimentsServer) Send(m *ExperimentEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Daemon_GetCheckpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCheckpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).GetCheckpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.Daemon/GetCheckpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).GetCheckpoint(ctx, req.(*GetCheckpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_DeleteCheckpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCheckpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).DeleteCheckpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.Daemon/DeleteCheckpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).DeleteCheckpoint(ctx, req.(*DeleteCheckpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_ListCheckpointFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCheckpointFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).ListCheckpointFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.Daemon/ListCheckpointFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).ListCheckpointFiles(ctx, req.(*ListCheckpointFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_CheckoutPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckoutPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).CheckoutPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.Daemon/CheckoutPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).CheckoutPath(ctx, req.(*CheckoutPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_DiffCheckpoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffCheckpointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).DiffCheckpoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.Daemon/DiffCheckpoints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).DiffCheckpoints(ctx, req.(*DiffCheckpointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Daemon_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.Daemon/Drain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).Drain(ctx, req.(*DrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Daemon_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "service.Daemon",
 	HandlerType: (*DaemonServer)(nil),
@@ -380,7 +662,41 @@ var _Daemon_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetExperimentStatus",
 			Handler:    _Daemon_GetExperimentStatus_Handler,
 		},
+		{
+			MethodName: "LogMetrics",
+			Handler:    _Daemon_LogMetrics_Handler,
+		},
+		{
+			MethodName: "GetCheckpoint",
+			Handler:    _Daemon_GetCheckpoint_Handler,
+		},
+		{
+			MethodName: "DeleteCheckpoint",
+			Handler:    _Daemon_DeleteCheckpoint_Handler,
+		},
+		{
+			MethodName: "ListCheckpointFiles",
+			Handler:    _Daemon_ListCheckpointFiles_Handler,
+		},
+		{
+			MethodName: "CheckoutPath",
+			Handler:    _Daemon_CheckoutPath_Handler,
+		},
+		{
+			MethodName: "DiffCheckpoints",
+			Handler:    _Daemon_DiffCheckpoints_Handler,
+		},
+		{
+			MethodName: "Drain",
+			Handler:    _Daemon_Drain_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchExperiments",
+			Handler:       _Daemon_WatchExperiments_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "keepsake.proto",
 }
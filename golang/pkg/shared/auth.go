@@ -0,0 +1,57 @@
+package shared
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const authorizationMetadataKey = "authorization"
+const bearerPrefix = "Bearer "
+
+// tokenAuthInterceptors returns interceptors that reject any request that
+// doesn't have "authorization: Bearer <token>" in its metadata
+func tokenAuthInterceptors(token string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := checkToken(ctx, token); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkToken(ss.Context(), token); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+	return unary, stream
+}
+
+func checkToken(ctx context.Context, token string) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "Missing authorization token")
+	}
+	return checkAuthorization(md.Get(authorizationMetadataKey), token)
+}
+
+// checkAuthorization returns an error unless one of the values of the
+// authorization header is "Bearer <token>"
+func checkAuthorization(values []string, token string) error {
+	for _, value := range values {
+		if !strings.HasPrefix(value, bearerPrefix) {
+			continue
+		}
+		given := strings.TrimPrefix(value, bearerPrefix)
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+			return nil
+		}
+		return status.Error(codes.Unauthenticated, "Invalid authorization token")
+	}
+	return status.Error(codes.Unauthenticated, "Missing authorization token")
+}
@@ -0,0 +1,36 @@
+package shared
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckToken(t *testing.T) {
+	for _, tt := range []struct {
+		md    metadata.MD
+		valid bool
+	}{
+		{metadata.Pairs("authorization", "Bearer s3cret"), true},
+		{metadata.Pairs("authorization", "Bearer wrong"), false},
+		{metadata.Pairs("authorization", "s3cret"), false},
+		{metadata.Pairs("other", "Bearer s3cret"), false},
+		{nil, false},
+	} {
+		ctx := context.Background()
+		if tt.md != nil {
+			ctx = metadata.NewIncomingContext(ctx, tt.md)
+		}
+		err := checkToken(ctx, "s3cret")
+		if tt.valid {
+			require.NoError(t, err)
+		} else {
+			require.Error(t, err)
+			require.Equal(t, codes.Unauthenticated, status.Code(err))
+		}
+	}
+}
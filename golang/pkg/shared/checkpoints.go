@@ -0,0 +1,121 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+	"github.com/replicate/keepsake/golang/pkg/servicepb/convert"
+)
+
+func (s *server) GetCheckpoint(ctx context.Context, req *servicepb.GetCheckpointRequest) (*servicepb.GetCheckpointReply, error) {
+	proj, _, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
+	if err != nil {
+		return nil, handleError(err)
+	}
+	chk, exp, err := proj.CheckpointFromPrefix(req.CheckpointIDPrefix)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	return &servicepb.GetCheckpointReply{
+		Checkpoint: convert.CheckpointToPb(chk),
+		Experiment: convert.ExperimentToPb(exp),
+	}, nil
+}
+
+func (s *server) DeleteCheckpoint(ctx context.Context, req *servicepb.DeleteCheckpointRequest) (*servicepb.DeleteCheckpointReply, error) {
+	proj, _, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
+	if err != nil {
+		return nil, handleError(err)
+	}
+	// a full ID is required, like DeleteExperiment, so a short prefix
+	// can't delete the wrong checkpoint
+	chk, exp, err := proj.CheckpointFromPrefix(req.CheckpointID)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	if chk.ID != req.CheckpointID {
+		return nil, handleError(fmt.Errorf("Checkpoint not found: %s", req.CheckpointID))
+	}
+	if err := proj.RemoveCheckpoint(chk, exp); err != nil {
+		return nil, handleError(err)
+	}
+	return &servicepb.DeleteCheckpointReply{}, nil
+}
+
+func (s *server) ListCheckpointFiles(ctx context.Context, req *servicepb.ListCheckpointFilesRequest) (*servicepb.ListCheckpointFilesReply, error) {
+	proj, _, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
+	if err != nil {
+		return nil, handleError(err)
+	}
+	chk, _, err := proj.CheckpointFromPrefix(req.CheckpointIDPrefix)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	paths, err := proj.CheckpointFiles(chk)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	return &servicepb.ListCheckpointFilesReply{Paths: paths}, nil
+}
+
+func (s *server) CheckoutPath(ctx context.Context, req *servicepb.CheckoutPathRequest) (*servicepb.CheckoutPathReply, error) {
+	proj, _, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
+	if err != nil {
+		return nil, handleError(err)
+	}
+	obj, err := proj.CheckpointOrExperimentFromPrefix(req.IdPrefix)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	if err := proj.CheckoutFileOrDirectory(obj.Checkpoint, obj.Experiment, req.OutputDirectory, req.Path, req.Quiet); err != nil {
+		return nil, handleError(err)
+	}
+	return &servicepb.CheckoutPathReply{}, nil
+}
+
+func (s *server) DiffCheckpoints(ctx context.Context, req *servicepb.DiffCheckpointsRequest) (*servicepb.DiffCheckpointsReply, error) {
+	proj, _, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
+	if err != nil {
+		return nil, handleError(err)
+	}
+	leftExp, leftChk, err := checkpointForDiff(proj, req.LeftIDPrefix)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	rightExp, rightChk, err := checkpointForDiff(proj, req.RightIDPrefix)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	// times are formatted in UTC, since the client's timezone is unknown
+	sections := project.DiffCheckpoints(leftExp, leftChk, rightExp, rightChk, time.UTC)
+	return &servicepb.DiffCheckpointsReply{
+		Left:              convert.CheckpointToPb(leftChk),
+		Right:             convert.CheckpointToPb(rightChk),
+		LeftExperimentID:  leftExp.ID,
+		RightExperimentID: rightExp.ID,
+		Sections:          convert.DiffSectionsToPb(sections),
+	}, nil
+}
+
+// checkpointForDiff returns the checkpoint that matches prefix, or the best
+// or latest checkpoint of the experiment that matches it
+func checkpointForDiff(proj *project.Project, prefix string) (*project.Experiment, *project.Checkpoint, error) {
+	obj, err := proj.CheckpointOrExperimentFromPrefix(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	if obj.Checkpoint != nil {
+		return obj.Experiment, obj.Checkpoint, nil
+	}
+	exp := obj.Experiment
+	if chk := exp.BestCheckpoint(); chk != nil {
+		return exp, chk, nil
+	}
+	if chk := exp.LatestCheckpoint(); chk != nil {
+		return exp, chk, nil
+	}
+	return nil, nil, fmt.Errorf("Could not pick best checkpoint for experiment %q: it does not have any checkpoints.", exp.ShortID())
+}
@@ -0,0 +1,97 @@
+package shared
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/errors"
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/repository"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+	"github.com/replicate/keepsake/golang/pkg/servicepb/convert"
+)
+
+func TestCheckpointMethods(t *testing.T) {
+	projectDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "data"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "train.py"), []byte("print(1)"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "data/weights.pth"), []byte("v1"), 0644))
+	repo, err := repository.NewDiskRepository(t.TempDir())
+	require.NoError(t, err)
+	proj := project.NewProject(repo, projectDir)
+	s := newTestServerForProject(t, proj)
+	ctx := context.Background()
+
+	// created synchronously so the files are in the repository straight away
+	exp, err := proj.CreateExperiment(project.CreateExperimentArgs{
+		Path:    ".",
+		Command: "train.py",
+		Params:  param.ValueMap{"lr": param.Float(0.01)},
+	}, false, nil, true)
+	require.NoError(t, err)
+	chk1, err := proj.CreateCheckpoint(project.CreateCheckpointArgs{
+		Path:    "data",
+		Step:    1,
+		Metrics: param.ValueMap{"loss": param.Float(0.5)},
+	}, false, nil, true)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "data/weights.pth"), []byte("v2"), 0644))
+	chk2, err := proj.CreateCheckpoint(project.CreateCheckpointArgs{
+		Path:    "data",
+		Step:    2,
+		Metrics: param.ValueMap{"loss": param.Float(0.2)},
+	}, false, nil, true)
+	require.NoError(t, err)
+	exp.Checkpoints = []*project.Checkpoint{chk1, chk2}
+	_, err = proj.SaveExperiment(exp, true)
+	require.NoError(t, err)
+
+	getReply, err := s.GetCheckpoint(ctx, &servicepb.GetCheckpointRequest{CheckpointIDPrefix: chk1.ID[:7]})
+	require.NoError(t, err)
+	require.Equal(t, chk1.ID, getReply.Checkpoint.Id)
+	require.Equal(t, exp.ID, getReply.Experiment.Id)
+
+	filesReply, err := s.ListCheckpointFiles(ctx, &servicepb.ListCheckpointFilesRequest{CheckpointIDPrefix: chk2.ID})
+	require.NoError(t, err)
+	require.Equal(t, []string{"data/weights.pth"}, filesReply.Paths)
+
+	// the checkpoint's files overlay the experiment's
+	outputDir := t.TempDir()
+	_, err = s.CheckoutPath(ctx, &servicepb.CheckoutPathRequest{
+		IdPrefix:        chk1.ID,
+		Path:            "data/weights.pth",
+		OutputDirectory: outputDir,
+		Quiet:           true,
+	})
+	require.NoError(t, err)
+	contents, err := os.ReadFile(filepath.Join(outputDir, "data/weights.pth"))
+	require.NoError(t, err)
+	require.Equal(t, "v1", string(contents))
+
+	diffReply, err := s.DiffCheckpoints(ctx, &servicepb.DiffCheckpointsRequest{LeftIDPrefix: chk1.ID, RightIDPrefix: chk2.ID})
+	require.NoError(t, err)
+	sections := convert.DiffSectionsFromPb(diffReply.Sections)
+	require.Equal(t, "Metrics", sections[4].Name)
+	require.Len(t, sections[4].Differences, 1)
+	require.Equal(t, "loss", sections[4].Differences[0].Key)
+	require.Equal(t, param.Float(0.5), *sections[4].Differences[0].Left)
+	require.Equal(t, param.Float(0.2), *sections[4].Differences[0].Right)
+	require.Empty(t, sections[1].Differences)
+
+	// prefixes aren't enough to delete a checkpoint
+	_, err = s.DeleteCheckpoint(ctx, &servicepb.DeleteCheckpointRequest{CheckpointID: chk1.ID[:7]})
+	require.Error(t, err)
+	_, err = s.DeleteCheckpoint(ctx, &servicepb.DeleteCheckpointRequest{CheckpointID: chk1.ID})
+	require.NoError(t, err)
+	exp, err = proj.ExperimentByID(exp.ID)
+	require.NoError(t, err)
+	require.Len(t, exp.Checkpoints, 1)
+	require.Equal(t, chk2.ID, exp.Checkpoints[0].ID)
+	_, err = repo.Get(chk1.StorageTarPath())
+	require.True(t, errors.IsDoesNotExist(err))
+}
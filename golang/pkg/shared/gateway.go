@@ -0,0 +1,315 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/errors"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+	"github.com/replicate/keepsake/golang/pkg/tracing"
+)
+
+// The HTTP gateway exposes every method of the Daemon service as
+//
+//     POST /v1/<method>
+//
+// where <method> is the snake_case name of the RPC, e.g.
+// /v1/create_experiment. The request body is the JSON form of the request
+// message (see gateway_json.go), and the response is the JSON form of the
+// reply. Streaming methods respond with one JSON object per line.
+//
+// GET /v1/openapi.json returns an OpenAPI description of the gateway.
+
+const gatewayPathPrefix = "/v1/"
+
+// requests bodies are small, except for SaveExperiment with many checkpoints
+const maxGatewayRequestSize = 64 << 20
+
+var daemonServiceDescriptor = servicepb.File_keepsake_proto.Services().ByName("Daemon")
+
+// gatewayError is the body of a failed gateway response
+type gatewayError struct {
+	Error gatewayErrorDetails `json:"error"`
+}
+
+type gatewayErrorDetails struct {
+	// Code is the error code from pkg/errors, e.g. DOES_NOT_EXIST, or
+	// the lowercase name of the gRPC status code if the error has none
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type gateway struct {
+	s     *server
+	token string
+}
+
+// HTTPHandler returns an http.Handler that serves the daemon's methods as
+// JSON over HTTP. If token is set, requests must have an
+// "Authorization: Bearer <token>" header.
+func (srv *Server) HTTPHandler(token string) http.Handler {
+	return &gateway{s: srv.s, token: token}
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if g.token != "" {
+		if err := checkAuthorization(r.Header.Values("Authorization"), g.token); err != nil {
+			writeGatewayError(w, err)
+			return
+		}
+	}
+
+	if r.URL.Path == gatewayPathPrefix+"openapi.json" {
+		if r.Method != http.MethodGet {
+			writeGatewayError(w, status.Errorf(codes.Unimplemented, "Method %s is not allowed, use GET", r.Method))
+			return
+		}
+		spec, err := OpenAPI()
+		if err != nil {
+			writeGatewayError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(spec)
+		return
+	}
+
+	method := gatewayMethod(r.URL.Path)
+	if method == nil {
+		writeGatewayError(w, status.Errorf(codes.NotFound, "Unknown path %s", r.URL.Path))
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeGatewayError(w, status.Errorf(codes.Unimplemented, "Method %s is not allowed, use POST", r.Method))
+		return
+	}
+
+	req, err := g.readRequest(w, r, method)
+	if err != nil {
+		writeGatewayError(w, status.Error(codes.InvalidArgument, err.Error()))
+		return
+	}
+	if method.IsStreamingServer() {
+		g.serveStream(w, r, method, req)
+		return
+	}
+
+	reply, err := g.call(r.Context(), r.Header, method, req)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	data, err := marshalGatewayJSON(reply)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// gatewayMethod returns the method for a path like /v1/create_experiment,
+// or nil if there is no such method
+func gatewayMethod(path string) protoreflect.MethodDescriptor {
+	if !strings.HasPrefix(path, gatewayPathPrefix) {
+		return nil
+	}
+	name := strings.TrimPrefix(path, gatewayPathPrefix)
+	methods := daemonServiceDescriptor.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		if gatewayMethodName(methods.Get(i)) == name {
+			return methods.Get(i)
+		}
+	}
+	return nil
+}
+
+// gatewayFullMethod returns the gRPC name of method, e.g.
+// "/service.Daemon/CreateExperiment"
+func gatewayFullMethod(method protoreflect.MethodDescriptor) string {
+	return "/" + string(daemonServiceDescriptor.FullName()) + "/" + string(method.Name())
+}
+
+func gatewayMethodName(method protoreflect.MethodDescriptor) string {
+	return snakeCase(string(method.Name()))
+}
+
+func (g *gateway) readRequest(w http.ResponseWriter, r *http.Request, method protoreflect.MethodDescriptor) (proto.Message, error) {
+	msgType, err := protoregistry.GlobalTypes.FindMessageByName(method.Input().FullName())
+	if err != nil {
+		return nil, err
+	}
+	req := msgType.New().Interface()
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxGatewayRequestSize))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read request body: %w", err)
+	}
+	if err := unmarshalGatewayJSON(body, req); err != nil {
+		return nil, fmt.Errorf("Invalid request body: %w", err)
+	}
+	return req, nil
+}
+
+// call calls the unary server method with the same name as method
+func (g *gateway) call(ctx context.Context, header http.Header, method protoreflect.MethodDescriptor, req proto.Message) (reply proto.Message, err error) {
+	ctx, span := tracing.StartHTTPServer(ctx, gatewayFullMethod(method), header)
+	start := time.Now()
+	defer func() {
+		observeRequest(string(method.Name()), start, err)
+		tracing.End(span, err)
+	}()
+	fn := reflect.ValueOf(g.s).MethodByName(string(method.Name()))
+	if !fn.IsValid() {
+		return nil, status.Errorf(codes.Unimplemented, "Method %s is not implemented", method.Name())
+	}
+	results := fn.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(req)})
+	if err, _ := results[1].Interface().(error); err != nil {
+		return nil, err
+	}
+	return results[0].Interface().(proto.Message), nil
+}
+
+func (g *gateway) serveStream(w http.ResponseWriter, r *http.Request, method protoreflect.MethodDescriptor, req proto.Message) {
+	ctx, span := tracing.StartHTTPServer(r.Context(), gatewayFullMethod(method), r.Header)
+	stream := &gatewayStream{ctx: ctx, w: w}
+	start := time.Now()
+	var err error
+	switch method.Name() {
+	case "WatchExperiments":
+		err = g.s.WatchExperiments(req.(*servicepb.WatchExperimentsRequest), &watchExperimentsGatewayStream{stream})
+	default:
+		err = status.Errorf(codes.Unimplemented, "Method %s is not implemented", method.Name())
+	}
+	observeRequest(string(method.Name()), start, err)
+	tracing.End(span, err)
+	if err == nil {
+		return
+	}
+	if !stream.started {
+		writeGatewayError(w, err)
+		return
+	}
+	// the status has already been sent, so the error is sent as the last line
+	if err := stream.send(gatewayErrorBody(err)); err != nil {
+		console.Debug("Failed to send error to HTTP client: %s", err)
+	}
+}
+
+// gatewayStream writes messages as JSON lines and flushes after every
+// message, so clients see events as soon as they happen
+type gatewayStream struct {
+	ctx     context.Context
+	w       http.ResponseWriter
+	started bool
+}
+
+func (s *gatewayStream) send(v interface{}) error {
+	var data []byte
+	var err error
+	if msg, ok := v.(proto.Message); ok {
+		data, err = marshalGatewayJSON(msg)
+	} else {
+		data, err = json.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+	if !s.started {
+		s.w.Header().Set("Content-Type", "application/x-ndjson")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// watchExperimentsGatewayStream implements the gRPC stream interface that
+// WatchExperiments sends events to. Only Context() and Send() are used by
+// the server; the other grpc.ServerStream methods are left unimplemented.
+type watchExperimentsGatewayStream struct {
+	*gatewayStream
+}
+
+var _ servicepb.Daemon_WatchExperimentsServer = &watchExperimentsGatewayStream{}
+
+func (s *watchExperimentsGatewayStream) Send(event *servicepb.ExperimentEvent) error {
+	return s.send(event)
+}
+
+func (s *watchExperimentsGatewayStream) Context() context.Context { return s.ctx }
+
+func (s *watchExperimentsGatewayStream) SetHeader(metadata.MD) error  { return nil }
+func (s *watchExperimentsGatewayStream) SendHeader(metadata.MD) error { return nil }
+func (s *watchExperimentsGatewayStream) SetTrailer(metadata.MD)       {}
+func (s *watchExperimentsGatewayStream) SendMsg(m interface{}) error {
+	return s.send(m)
+}
+func (s *watchExperimentsGatewayStream) RecvMsg(m interface{}) error {
+	return status.Error(codes.Unimplemented, "Streams from the HTTP gateway can't receive messages")
+}
+
+var _ grpc.ServerStream = &watchExperimentsGatewayStream{}
+
+func gatewayErrorBody(err error) *gatewayError {
+	st := status.Convert(err)
+	code := strings.ToLower(st.Code().String())
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			code = info.Reason
+		}
+	}
+	return &gatewayError{Error: gatewayErrorDetails{Code: code, Message: st.Message()}}
+}
+
+func writeGatewayError(w http.ResponseWriter, err error) {
+	body := gatewayErrorBody(err)
+	data, marshalErr := json.Marshal(body)
+	if marshalErr != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(gatewayHTTPStatus(err, body.Error.Code))
+	_, _ = w.Write(data)
+}
+
+func gatewayHTTPStatus(err error, code string) int {
+	switch code {
+	case errors.CodeDoesNotExist:
+		return http.StatusNotFound
+	case errors.CodeConfigNotFound, errors.CodeRepositoryConfigurationError:
+		return http.StatusBadRequest
+	}
+	switch status.Code(err) {
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.Unimplemented:
+		return http.StatusMethodNotAllowed
+	}
+	return http.StatusInternalServerError
+}
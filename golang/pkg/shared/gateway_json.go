@@ -0,0 +1,352 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+	"github.com/replicate/keepsake/golang/pkg/servicepb/convert"
+)
+
+// The HTTP gateway transcodes between JSON and the daemon's protobuf
+// messages, so that the JSON has the same shape as the metadata we write
+// in the repository:
+//
+// - field names are snake_case, e.g. python_packages
+// - params and metrics (ParamType) are plain JSON values
+// - timestamps are RFC 3339 strings, durations are strings like "2s",
+//   field masks are lists of paths, and enums are lowercase strings
+// - all fields are written, even if they are empty
+
+var (
+	paramTypeName = (&servicepb.ParamType{}).ProtoReflect().Descriptor().FullName()
+	timestampName = (&timestamppb.Timestamp{}).ProtoReflect().Descriptor().FullName()
+	durationName  = (&durationpb.Duration{}).ProtoReflect().Descriptor().FullName()
+	fieldMaskName = (&fieldmaskpb.FieldMask{}).ProtoReflect().Descriptor().FullName()
+)
+
+// jsonFieldName returns the snake_case JSON name of a protobuf field
+func jsonFieldName(fd protoreflect.FieldDescriptor) string {
+	return snakeCase(string(fd.Name()))
+}
+
+// snakeCase converts a camelCase or PascalCase name to snake_case, treating
+// runs of capitals as one word: experimentIDPrefix -> experiment_id_prefix
+func snakeCase(s string) string {
+	name := []rune(s)
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && unicode.IsLower(name[i-1])
+			endOfAcronym := i > 0 && unicode.IsUpper(name[i-1]) && i+1 < len(name) && unicode.IsLower(name[i+1])
+			if prevLower || endOfAcronym {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func jsonEnumName(v protoreflect.EnumValueDescriptor) string {
+	return strings.ToLower(string(v.Name()))
+}
+
+// unmarshalGatewayJSON sets the fields of msg from JSON data
+func unmarshalGatewayJSON(data []byte, msg proto.Message) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	return unmarshalMessage(json.RawMessage(data), msg.ProtoReflect())
+}
+
+func unmarshalMessage(data json.RawMessage, msg protoreflect.Message) error {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("Expected a JSON object for %s: %w", msg.Descriptor().Name(), err)
+	}
+	fields := msg.Descriptor().Fields()
+	for key, value := range raw {
+		var fd protoreflect.FieldDescriptor
+		for i := 0; i < fields.Len(); i++ {
+			if jsonFieldName(fields.Get(i)) == key {
+				fd = fields.Get(i)
+				break
+			}
+		}
+		if fd == nil {
+			return fmt.Errorf("Unknown field %q in %s", key, msg.Descriptor().Name())
+		}
+		if isJSONNull(value) {
+			continue
+		}
+		if err := unmarshalField(value, msg, fd); err != nil {
+			return fmt.Errorf("Invalid value for %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func unmarshalField(data json.RawMessage, msg protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	switch {
+	case fd.IsList():
+		items := []json.RawMessage{}
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		list := msg.Mutable(fd).List()
+		for _, item := range items {
+			var v protoreflect.Value
+			if fd.Message() != nil {
+				v = list.NewElement()
+				if err := unmarshalSingularMessage(item, v.Message()); err != nil {
+					return err
+				}
+			} else {
+				var err error
+				if v, err = unmarshalScalar(item, fd); err != nil {
+					return err
+				}
+			}
+			list.Append(v)
+		}
+		return nil
+	case fd.IsMap():
+		items := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		m := msg.Mutable(fd).Map()
+		valueFd := fd.MapValue()
+		for key, item := range items {
+			var v protoreflect.Value
+			if valueFd.Message() != nil {
+				v = m.NewValue()
+				if err := unmarshalSingularMessage(item, v.Message()); err != nil {
+					return err
+				}
+			} else {
+				var err error
+				if v, err = unmarshalScalar(item, valueFd); err != nil {
+					return err
+				}
+			}
+			m.Set(protoreflect.ValueOfString(key).MapKey(), v)
+		}
+		return nil
+	case fd.Message() != nil:
+		return unmarshalSingularMessage(data, msg.Mutable(fd).Message())
+	}
+	v, err := unmarshalScalar(data, fd)
+	if err != nil {
+		return err
+	}
+	msg.Set(fd, v)
+	return nil
+}
+
+func unmarshalSingularMessage(data json.RawMessage, msg protoreflect.Message) error {
+	switch msg.Descriptor().FullName() {
+	case paramTypeName:
+		var v param.Value
+		if isJSONNull(data) {
+			v = param.None()
+		} else if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		proto.Merge(msg.Interface(), convert.ValueToPb(v))
+		return nil
+	case timestampName:
+		var t time.Time
+		if err := json.Unmarshal(data, &t); err != nil {
+			return err
+		}
+		proto.Merge(msg.Interface(), timestamppb.New(t))
+		return nil
+	case durationName:
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		proto.Merge(msg.Interface(), durationpb.New(d))
+		return nil
+	case fieldMaskName:
+		paths := []string{}
+		if err := json.Unmarshal(data, &paths); err != nil {
+			return err
+		}
+		proto.Merge(msg.Interface(), &fieldmaskpb.FieldMask{Paths: paths})
+		return nil
+	}
+	return unmarshalMessage(data, msg)
+}
+
+func unmarshalScalar(data json.RawMessage, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		var b bool
+		err := json.Unmarshal(data, &b)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.StringKind:
+		var s string
+		err := json.Unmarshal(data, &s)
+		return protoreflect.ValueOfString(s), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		var i int32
+		err := json.Unmarshal(data, &i)
+		return protoreflect.ValueOfInt32(i), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		var i int64
+		err := json.Unmarshal(data, &i)
+		return protoreflect.ValueOfInt64(i), err
+	case protoreflect.DoubleKind:
+		var f float64
+		err := json.Unmarshal(data, &f)
+		return protoreflect.ValueOfFloat64(f), err
+	case protoreflect.EnumKind:
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return protoreflect.Value{}, err
+		}
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			if jsonEnumName(values.Get(i)) == strings.ToLower(s) {
+				return protoreflect.ValueOfEnum(values.Get(i).Number()), nil
+			}
+		}
+		return protoreflect.Value{}, fmt.Errorf("Unknown value %q", s)
+	}
+	// should never happen, the daemon's messages don't use other types
+	return protoreflect.Value{}, fmt.Errorf("Unsupported field type %s", fd.Kind())
+}
+
+func isJSONNull(data json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
+
+// marshalGatewayJSON returns the JSON representation of msg
+func marshalGatewayJSON(msg proto.Message) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := marshalMessage(buf, msg.ProtoReflect()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalMessage(buf *bytes.Buffer, msg protoreflect.Message) error {
+	switch msg.Descriptor().FullName() {
+	case paramTypeName:
+		return marshalJSONValue(buf, convert.ValueFromPb(msg.Interface().(*servicepb.ParamType)))
+	case timestampName:
+		return marshalJSONValue(buf, msg.Interface().(*timestamppb.Timestamp).AsTime())
+	case durationName:
+		return marshalJSONValue(buf, msg.Interface().(*durationpb.Duration).AsDuration().String())
+	case fieldMaskName:
+		return marshalJSONValue(buf, msg.Interface().(*fieldmaskpb.FieldMask).Paths)
+	}
+
+	buf.WriteByte('{')
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := marshalJSONValue(buf, jsonFieldName(fd)); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := marshalField(buf, msg, fd); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func marshalField(buf *bytes.Buffer, msg protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	v := msg.Get(fd)
+	switch {
+	case fd.IsList():
+		list := v.List()
+		buf.WriteByte('[')
+		for i := 0; i < list.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := marshalSingular(buf, list.Get(i), fd); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case fd.IsMap():
+		// sort keys so the output is stable
+		m := v.Map()
+		keys := []string{}
+		m.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, k.String())
+			return true
+		})
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := marshalJSONValue(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := marshalSingular(buf, m.Get(protoreflect.ValueOfString(key).MapKey()), fd.MapValue()); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case fd.Message() != nil && !msg.Has(fd):
+		buf.WriteString("null")
+		return nil
+	}
+	return marshalSingular(buf, v, fd)
+}
+
+func marshalSingular(buf *bytes.Buffer, v protoreflect.Value, fd protoreflect.FieldDescriptor) error {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return marshalMessage(buf, v.Message())
+	case protoreflect.EnumKind:
+		enumValue := fd.Enum().Values().ByNumber(v.Enum())
+		if enumValue == nil {
+			return marshalJSONValue(buf, int32(v.Enum()))
+		}
+		return marshalJSONValue(buf, jsonEnumName(enumValue))
+	}
+	return marshalJSONValue(buf, v.Interface())
+}
+
+func marshalJSONValue(buf *bytes.Buffer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
@@ -0,0 +1,180 @@
+package shared
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+)
+
+func newTestGateway(t *testing.T, token string) *httptest.Server {
+	ts := httptest.NewServer(&gateway{s: newTestServer(t), token: token})
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func postGateway(t *testing.T, ts *httptest.Server, method string, body string) (int, map[string]interface{}) {
+	resp, err := http.Post(ts.URL+"/v1/"+method, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	reply := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(data, &reply), string(data))
+	return resp.StatusCode, reply
+}
+
+func TestGateway(t *testing.T) {
+	ts := newTestGateway(t, "")
+
+	code, reply := postGateway(t, ts, "create_experiment", `{
+		"experiment": {
+			"command": "train.py",
+			"params": {"lr": 0.01, "layers": [1, 2], "name": "resnet", "dropout": null},
+			"python_packages": {"torch": "1.7.0"}
+		},
+		"disable_heartbeat": true,
+		"quiet": true
+	}`)
+	require.Equal(t, http.StatusOK, code, reply)
+	exp := reply["experiment"].(map[string]interface{})
+	expID := exp["id"].(string)
+	require.Equal(t, "train.py", exp["command"])
+	require.Equal(t, map[string]interface{}{"lr": 0.01, "layers": []interface{}{1.0, 2.0}, "name": "resnet", "dropout": nil}, exp["params"])
+	require.Equal(t, map[string]interface{}{"torch": "1.7.0"}, exp["python_packages"])
+	require.Equal(t, []interface{}{}, exp["checkpoints"])
+	require.Contains(t, exp["config"], "repository")
+
+	code, reply = postGateway(t, ts, "get_experiment", `{"experiment_id_prefix": "`+expID[:7]+`"}`)
+	require.Equal(t, http.StatusOK, code, reply)
+	require.Equal(t, expID, reply["experiment"].(map[string]interface{})["id"])
+
+	code, reply = postGateway(t, ts, "get_experiment_status", `{"experiment_id": "`+expID+`"}`)
+	require.Equal(t, http.StatusOK, code, reply)
+	require.Equal(t, "stopped", reply["status"])
+
+	code, reply = postGateway(t, ts, "list_experiments", `{"filters": ["lr = 0.01"], "field_mask": ["id", "params"]}`)
+	require.Equal(t, http.StatusOK, code, reply)
+	require.Equal(t, 1.0, reply["total_size"])
+	experiments := reply["experiments"].([]interface{})
+	require.Len(t, experiments, 1)
+	require.Equal(t, expID, experiments[0].(map[string]interface{})["id"])
+	require.Equal(t, "", experiments[0].(map[string]interface{})["command"])
+}
+
+func TestGatewayErrors(t *testing.T) {
+	ts := newTestGateway(t, "")
+
+	code, reply := postGateway(t, ts, "get_experiment", `{"experiment_id_prefix": "doesnotexist"}`)
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "DOES_NOT_EXIST", reply["error"].(map[string]interface{})["code"])
+
+	code, reply = postGateway(t, ts, "get_experiment", `{"experimentIDPrefix": "abc"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, reply["error"].(map[string]interface{})["message"], `Unknown field "experimentIDPrefix"`)
+
+	code, _ = postGateway(t, ts, "not_a_method", `{}`)
+	require.Equal(t, http.StatusNotFound, code)
+
+	resp, err := http.Get(ts.URL + "/v1/list_experiments")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestGatewayAuth(t *testing.T) {
+	ts := newTestGateway(t, "secret")
+
+	code, reply := postGateway(t, ts, "list_experiments", `{}`)
+	require.Equal(t, http.StatusUnauthorized, code)
+	require.Equal(t, "unauthenticated", reply["error"].(map[string]interface{})["code"])
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/v1/list_experiments", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGatewayWatchExperiments(t *testing.T) {
+	ts := newTestGateway(t, "")
+
+	code, reply := postGateway(t, ts, "create_experiment", `{"experiment": {"command": "train.py"}, "disable_heartbeat": true, "quiet": true}`)
+	require.Equal(t, http.StatusOK, code, reply)
+
+	resp, err := http.Post(ts.URL+"/v1/watch_experiments", "application/json", strings.NewReader(`{"include_existing": true, "poll_interval": "100ms"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(resp.Body)
+	require.True(t, scanner.Scan())
+	event := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+	require.Equal(t, "created", event["type"])
+	require.Equal(t, "train.py", event["experiment"].(map[string]interface{})["command"])
+}
+
+func TestGatewayJSONRoundTrip(t *testing.T) {
+	req := &servicepb.WatchExperimentsRequest{
+		Project:         &servicepb.Project{RepositoryURL: "s3://bucket", Directory: "/project"},
+		Filters:         []string{"step > 3"},
+		IncludeExisting: true,
+		PollInterval:    durationpb.New(1500000000),
+	}
+	data, err := marshalGatewayJSON(req)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"project": {"repository_url": "s3://bucket", "directory": "/project"},
+		"filters": ["step > 3"],
+		"include_existing": true,
+		"poll_interval": "1.5s"
+	}`, string(data))
+
+	parsed := &servicepb.WatchExperimentsRequest{}
+	require.NoError(t, unmarshalGatewayJSON(data, parsed))
+	require.True(t, proto.Equal(req, parsed))
+
+	listReq := &servicepb.ListExperimentsRequest{}
+	require.NoError(t, unmarshalGatewayJSON([]byte(`{"field_mask": ["id", "checkpoints.step"], "page_size": 10}`), listReq))
+	require.True(t, proto.Equal(&servicepb.ListExperimentsRequest{
+		FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "checkpoints.step"}},
+		PageSize:  10,
+	}, listReq))
+}
+
+func TestSnakeCase(t *testing.T) {
+	for input, expected := range map[string]string{
+		"experimentIDPrefix": "experiment_id_prefix",
+		"repositoryURL":      "repository_url",
+		"pythonPackages":     "python_packages",
+		"id":                 "id",
+		"CreateExperiment":   "create_experiment",
+		"LogMetrics":         "log_metrics",
+	} {
+		require.Equal(t, expected, snakeCase(input))
+	}
+}
+
+// The checked in OpenAPI description must be regenerated when keepsake.proto
+// changes, with `make build` in proto/
+func TestOpenAPIIsUpToDate(t *testing.T) {
+	expected, err := ioutil.ReadFile(filepath.Join("..", "..", "..", "proto", "keepsake.openapi.json"))
+	require.NoError(t, err)
+	actual, err := OpenAPI()
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"sync"
 	"time"
 
 	"github.com/replicate/keepsake/golang/pkg/console"
@@ -12,6 +13,8 @@ type HeartbeatProcess struct {
 	experimentID string
 	ticker       *time.Ticker
 	done         chan struct{}
+	exited       chan struct{}
+	killOnce     sync.Once
 }
 
 func StartHeartbeat(proj *project.Project, experimentID string) *HeartbeatProcess {
@@ -20,8 +23,13 @@ func StartHeartbeat(proj *project.Project, experimentID string) *HeartbeatProces
 		experimentID: experimentID,
 		ticker:       time.NewTicker(5 * time.Second),
 		done:         make(chan struct{}),
+		exited:       make(chan struct{}),
 	}
+	// mark the experiment as running straight away, rather than
+	// after the first tick
+	h.Refresh()
 	go func() {
+		defer close(h.exited)
 		for {
 			select {
 			case <-h.done:
@@ -40,7 +48,13 @@ func (h *HeartbeatProcess) Refresh() {
 	}
 }
 
+// Kill stops the heartbeat and waits for any in-flight refresh to finish,
+// so the heartbeat isn't written again after the experiment is stopped.
+// It is safe to call Kill more than once.
 func (h *HeartbeatProcess) Kill() {
-	h.ticker.Stop()
-	h.done <- struct{}{}
+	h.killOnce.Do(func() {
+		h.ticker.Stop()
+		close(h.done)
+	})
+	<-h.exited
 }
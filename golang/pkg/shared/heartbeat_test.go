@@ -0,0 +1,33 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/repository"
+)
+
+func TestStartHeartbeat(t *testing.T) {
+	repo, err := repository.NewDiskRepository(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, (&project.Experiment{ID: "1eee", Created: time.Now().UTC()}).Save(repo))
+	proj := project.NewProject(repo, t.TempDir())
+
+	// the experiment is running as soon as the heartbeat starts, rather
+	// than after the first tick
+	h := StartHeartbeat(proj, "1eee")
+	running, err := proj.ExperimentIsRunning("1eee")
+	require.NoError(t, err)
+	require.True(t, running)
+
+	h.Kill()
+	// killing twice is fine
+	h.Kill()
+	require.NoError(t, proj.StopExperiment("1eee"))
+	running, err = proj.ExperimentIsRunning("1eee")
+	require.NoError(t, err)
+	require.False(t, running)
+}
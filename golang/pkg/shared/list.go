@@ -0,0 +1,231 @@
+package shared
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+)
+
+// listExperiments returns a page of experiments that match the request's
+// filters in the request's sort order, and the token for the next page
+func listExperiments(proj *project.Project, req *servicepb.ListExperimentsRequest) (page []*project.Experiment, nextPageToken string, totalSize int, err error) {
+	if req.PageSize < 0 {
+		return nil, "", 0, fmt.Errorf("Page size must not be negative")
+	}
+	if err := validateFieldMask(req.FieldMask); err != nil {
+		return nil, "", 0, err
+	}
+	filters, err := param.MakeFilters(req.Filters)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	query := queryHash(req)
+	offset, err := parsePageToken(req.PageToken, query)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	experiments, err := proj.Experiments()
+	if err != nil {
+		return nil, "", 0, err
+	}
+	type match struct {
+		exp     *project.Experiment
+		listExp *project.ListExperiment
+	}
+	matches := []match{}
+	for _, exp := range experiments {
+		listExp, err := project.ListExperimentFromProject(proj, exp)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		ok, err := filters.Matches(listExp)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		if ok {
+			matches = append(matches, match{exp, listExp})
+		}
+	}
+
+	// sort by creation time and ID first, so pages are stable
+	// when the sort key has the same value for several experiments
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].exp.Created.Equal(matches[j].exp.Created) {
+			return matches[i].exp.Created.Before(matches[j].exp.Created)
+		}
+		return matches[i].exp.ID < matches[j].exp.ID
+	})
+	if req.Sort != "" {
+		sorter, err := param.NewSorter(req.Sort)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		sort.SliceStable(matches, func(i, j int) bool {
+			return sorter.LessThan(matches[i].listExp, matches[j].listExp)
+		})
+	}
+
+	totalSize = len(matches)
+	end := totalSize
+	if req.PageSize > 0 && offset+int(req.PageSize) < totalSize {
+		end = offset + int(req.PageSize)
+		nextPageToken = makePageToken(end, query)
+	}
+	page = []*project.Experiment{}
+	for i := offset; i < end; i++ {
+		page = append(page, matches[i].exp)
+	}
+	return page, nextPageToken, totalSize, nil
+}
+
+// queryHash identifies the filters and sort order of a request, so page
+// tokens can't be used with a different query
+func queryHash(req *servicepb.ListExperimentsRequest) string {
+	h := sha1.New()
+	for _, f := range req.Filters {
+		fmt.Fprintf(h, "%q\n", f)
+	}
+	fmt.Fprintf(h, "%q\n", req.Sort)
+	return hex.EncodeToString(h.Sum(nil))[:8]
+}
+
+func makePageToken(offset int, query string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset) + ":" + query))
+}
+
+func parsePageToken(token string, query string) (offset int, err error) {
+	if token == "" {
+		return 0, nil
+	}
+	invalid := fmt.Errorf("Invalid page token: %s", token)
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, invalid
+	}
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return 0, invalid
+	}
+	if parts[1] != query {
+		return 0, fmt.Errorf("Page token was returned for different filters or sort order")
+	}
+	offset, err = strconv.Atoi(parts[0])
+	if err != nil || offset < 0 {
+		return 0, invalid
+	}
+	return offset, nil
+}
+
+// applyFieldMask clears all fields of msg that aren't in the mask. A nil
+// mask keeps all fields.
+func applyFieldMask(msg proto.Message, mask *fieldmaskpb.FieldMask) {
+	if mask == nil || len(mask.Paths) == 0 {
+		return
+	}
+	pruneMessage(msg.ProtoReflect(), newFieldMaskTree(mask.Paths))
+}
+
+// validateFieldMask returns an error if any of the paths in mask aren't
+// fields of an experiment
+func validateFieldMask(mask *fieldmaskpb.FieldMask) error {
+	if mask == nil {
+		return nil
+	}
+	desc := (&servicepb.Experiment{}).ProtoReflect().Descriptor()
+	for _, p := range mask.Paths {
+		if !isValidFieldMaskPath(desc, p) {
+			return fmt.Errorf("Invalid field mask path: %s", p)
+		}
+	}
+	return nil
+}
+
+// isValidFieldMaskPath returns true if path is a dot-separated list of
+// fields in msg. Unlike FieldMask.IsValid, paths can go through lists of
+// messages, e.g. checkpoints.metrics
+func isValidFieldMaskPath(msg protoreflect.MessageDescriptor, path string) bool {
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		if msg == nil {
+			return false
+		}
+		fd := msg.Fields().ByName(protoreflect.Name(part))
+		if fd == nil {
+			return false
+		}
+		if i < len(parts)-1 && fd.IsMap() {
+			return false
+		}
+		msg = fd.Message()
+	}
+	return true
+}
+
+// fieldMaskTree is a tree of field names. A node without children means
+// the whole field is kept.
+type fieldMaskTree map[string]fieldMaskTree
+
+func newFieldMaskTree(paths []string) fieldMaskTree {
+	tree := fieldMaskTree{}
+	for _, p := range paths {
+		node := tree
+		parts := strings.Split(p, ".")
+		for i, part := range parts {
+			child, ok := node[part]
+			if ok && len(child) == 0 {
+				// this field is already kept as a whole
+				break
+			}
+			if i == len(parts)-1 {
+				// keep the whole field, even if a sub-path was added before
+				node[part] = fieldMaskTree{}
+				break
+			}
+			if !ok {
+				child = fieldMaskTree{}
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return tree
+}
+
+func pruneMessage(msg protoreflect.Message, tree fieldMaskTree) {
+	// fields are cleared after ranging, since the message must not
+	// be modified during Range
+	toClear := []protoreflect.FieldDescriptor{}
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		subtree, ok := tree[string(fd.Name())]
+		switch {
+		case !ok:
+			toClear = append(toClear, fd)
+		case len(subtree) == 0:
+			// keep the whole field
+		case fd.IsList() && fd.Message() != nil:
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				pruneMessage(l.Get(i).Message(), subtree)
+			}
+		case !fd.IsMap() && fd.Message() != nil:
+			pruneMessage(v.Message(), subtree)
+		}
+		return true
+	})
+	for _, fd := range toClear {
+		msg.Clear(fd)
+	}
+}
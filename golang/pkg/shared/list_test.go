@@ -0,0 +1,147 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+)
+
+func createListTestExperiments(t *testing.T, s *server) {
+	proj, _, err := s.projects.get(context.Background(), projectKey{})
+	require.NoError(t, err)
+	created := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, lr := range []float64{0.5, 0.1, 0.3, 0.2, 0.4} {
+		exp := &project.Experiment{
+			ID:             string(rune('1'+i)) + "eeeeeeeee",
+			Created:        created.Add(time.Duration(i) * time.Minute),
+			Params:         param.ValueMap{"lr": param.Float(lr)},
+			PythonPackages: map[string]string{"torch": "1.7.0"},
+			Checkpoints: []*project.Checkpoint{{
+				ID:      string(rune('1'+i)) + "ccccccccc",
+				Metrics: param.ValueMap{"loss": param.Float(lr * 2)},
+			}},
+		}
+		_, err := proj.SaveExperiment(exp, true)
+		require.NoError(t, err)
+	}
+}
+
+func experimentIDs(experiments []*servicepb.Experiment) []string {
+	ids := []string{}
+	for _, exp := range experiments {
+		ids = append(ids, exp.Id[:1])
+	}
+	return ids
+}
+
+func TestListExperimentsFilterAndSort(t *testing.T) {
+	s := newTestServer(t)
+	createListTestExperiments(t, s)
+	ctx := context.Background()
+
+	reply, err := s.ListExperiments(ctx, &servicepb.ListExperimentsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2", "3", "4", "5"}, experimentIDs(reply.Experiments))
+	require.Equal(t, int32(5), reply.TotalSize)
+	require.Empty(t, reply.NextPageToken)
+
+	reply, err = s.ListExperiments(ctx, &servicepb.ListExperimentsRequest{
+		Filters: []string{"lr >= 0.2"},
+		Sort:    "lr",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"4", "3", "5", "1"}, experimentIDs(reply.Experiments))
+
+	reply, err = s.ListExperiments(ctx, &servicepb.ListExperimentsRequest{Sort: "lr-desc"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "5", "3", "4", "2"}, experimentIDs(reply.Experiments))
+
+	_, err = s.ListExperiments(ctx, &servicepb.ListExperimentsRequest{Filters: []string{"lr"}})
+	require.Error(t, err)
+}
+
+func TestListExperimentsPagination(t *testing.T) {
+	s := newTestServer(t)
+	createListTestExperiments(t, s)
+	ctx := context.Background()
+
+	req := &servicepb.ListExperimentsRequest{Sort: "lr", PageSize: 2}
+	ids := []string{}
+	pages := 0
+	for {
+		reply, err := s.ListExperiments(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, int32(5), reply.TotalSize)
+		ids = append(ids, experimentIDs(reply.Experiments)...)
+		pages++
+		if reply.NextPageToken == "" {
+			break
+		}
+		req.PageToken = reply.NextPageToken
+	}
+	require.Equal(t, 3, pages)
+	require.Equal(t, []string{"2", "4", "3", "5", "1"}, ids)
+
+	// tokens can't be reused with a different query
+	_, err := s.ListExperiments(ctx, &servicepb.ListExperimentsRequest{Sort: "lr-desc", PageSize: 2, PageToken: req.PageToken})
+	require.Error(t, err)
+	_, err = s.ListExperiments(ctx, &servicepb.ListExperimentsRequest{PageToken: "garbage"})
+	require.Error(t, err)
+}
+
+func TestListExperimentsFieldMask(t *testing.T) {
+	s := newTestServer(t)
+	createListTestExperiments(t, s)
+	ctx := context.Background()
+
+	reply, err := s.ListExperiments(ctx, &servicepb.ListExperimentsRequest{
+		FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "params", "checkpoints.metrics"}},
+	})
+	require.NoError(t, err)
+	exp := reply.Experiments[0]
+	require.Equal(t, "1eeeeeeeee", exp.Id)
+	require.Nil(t, exp.Created)
+	require.Empty(t, exp.PythonPackages)
+	require.Len(t, exp.Params, 1)
+	require.Len(t, exp.Checkpoints, 1)
+	require.Empty(t, exp.Checkpoints[0].Id)
+	require.Len(t, exp.Checkpoints[0].Metrics, 1)
+
+	_, err = s.ListExperiments(ctx, &servicepb.ListExperimentsRequest{
+		FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "nonexistent"}},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid field mask path: nonexistent")
+}
+
+func TestIsValidFieldMaskPath(t *testing.T) {
+	desc := (&servicepb.Experiment{}).ProtoReflect().Descriptor()
+	for _, tt := range []struct {
+		path  string
+		valid bool
+	}{
+		{"id", true},
+		{"params", true},
+		{"config.repository", true},
+		// paths can go through lists of messages
+		{"checkpoints.metrics", true},
+		{"checkpoints.primaryMetric.name", true},
+		{"", false},
+		{"nonexistent", false},
+		{"checkpoints.nonexistent", false},
+		// only messages have fields
+		{"id.foo", false},
+		// map keys aren't fields
+		{"params.lr", false},
+		{"checkpoints.", false},
+	} {
+		require.Equal(t, tt.valid, isValidFieldMaskPath(desc, tt.path), tt.path)
+	}
+}
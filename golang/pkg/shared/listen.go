@@ -0,0 +1,96 @@
+package shared
+
+import (
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/listenaddress"
+)
+
+// ServeOptions configures where the daemon listens and how clients
+// authenticate with it
+type ServeOptions struct {
+	// Listen is either a path to a UNIX socket, a unix://<path> URL,
+	// or a tcp://<host>:<port> URL
+	Listen string
+
+	// If both TLSCertFile and TLSKeyFile are set, connections are
+	// served over TLS
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// If HTTPListen is set, the JSON over HTTP gateway is served on it,
+	// in the same format as Listen
+	HTTPListen string
+
+	// If MetricsListen is set, Prometheus metrics are served at /metrics
+	// on it, in the same format as Listen. Metrics aren't authenticated.
+	MetricsListen string
+
+	// If AuthToken is set, clients must pass it as a bearer token
+	// in the "authorization" metadata of every request, or in the
+	// Authorization header of HTTP requests
+	AuthToken string
+
+	// InsecureNoAuth allows listening on TCP without AuthToken, so
+	// anyone who can connect can read and write the repository.
+	// Otherwise, listening on TCP without a token is an error.
+	InsecureNoAuth bool
+}
+
+// listen listens on listenAddress, which is either opts.Listen or
+// opts.HTTPListen
+func listen(listenAddress string, opts ServeOptions) (net.Listener, error) {
+	network, address, err := listenaddress.Parse(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+	if network == "tcp" && opts.AuthToken == "" && !opts.InsecureNoAuth {
+		return nil, fmt.Errorf("Refusing to listen on %s without a token, because anyone who can connect to it could read and write your repository", address)
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		if network == "unix" {
+			return nil, fmt.Errorf("Failed to open UNIX socket on %s: %w", address, err)
+		}
+		return nil, fmt.Errorf("Failed to listen on %s: %w", address, err)
+	}
+
+	if network == "tcp" {
+		tlsEnabled := opts.TLSCertFile != "" && opts.TLSKeyFile != ""
+		if opts.AuthToken == "" {
+			console.Warn("The daemon is listening on %s without a token, so anyone who can connect to it can read and write your repository", address)
+		} else if !tlsEnabled {
+			console.Warn("The daemon is listening on %s without TLS, so the token is sent in plaintext", address)
+		}
+	}
+	console.Debug("Listening on %s://%s", network, address)
+
+	return listener, nil
+}
+
+func grpcServerOptions(opts ServeOptions) ([]grpc.ServerOption, error) {
+	serverOpts := []grpc.ServerOption{}
+
+	if (opts.TLSCertFile == "") != (opts.TLSKeyFile == "") {
+		return nil, fmt.Errorf("Both a TLS certificate and a TLS key must be provided to enable TLS")
+	}
+	if opts.TLSCertFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(opts.TLSCertFile, opts.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load TLS certificate: %w", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	}
+
+	if opts.AuthToken != "" {
+		unary, stream := tokenAuthInterceptors(opts.AuthToken)
+		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(unary), grpc.ChainStreamInterceptor(stream))
+	}
+
+	return serverOpts, nil
+}
@@ -0,0 +1,24 @@
+package shared
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenRequiresTokenOnTCP(t *testing.T) {
+	_, err := listen("tcp://127.0.0.1:0", ServeOptions{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "without a token")
+
+	for _, opts := range []ServeOptions{{AuthToken: "secret"}, {InsecureNoAuth: true}} {
+		listener, err := listen("tcp://127.0.0.1:0", opts)
+		require.NoError(t, err)
+		require.NoError(t, listener.Close())
+	}
+
+	listener, err := listen("unix://"+filepath.Join(t.TempDir(), "daemon.sock"), ServeOptions{})
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+}
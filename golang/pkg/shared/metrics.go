@@ -0,0 +1,182 @@
+package shared
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/project"
+)
+
+// how often buffered metric points are written to the repository
+var metricsFlushInterval = 10 * time.Second
+
+// the number of buffered points for an experiment that causes them to be
+// written immediately, rather than at the next flush
+const maxBufferedMetricPoints = 1000
+
+type metricsBufferKey struct {
+	project      projectKey
+	experimentID string
+}
+
+type bufferedMetrics struct {
+	proj   *project.Project
+	points []project.MetricPoint
+}
+
+// metricsBuffer collects logged metric points in memory, so that logging
+// a metric every step doesn't write to the repository every step
+type metricsBuffer struct {
+	// flushMu is held while points are written, so batches for the same
+	// experiment are appended in the order they were logged
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	buffers map[metricsBufferKey]*bufferedMetrics
+	// flushing counts the buffers of each project that have been taken out
+	// of buffers and are being written
+	flushing map[projectKey]int
+}
+
+func newMetricsBuffer() *metricsBuffer {
+	return &metricsBuffer{
+		buffers:  make(map[metricsBufferKey]*bufferedMetrics),
+		flushing: make(map[projectKey]int),
+	}
+}
+
+// hasProject returns true if a project has points that are buffered or
+// being written
+func (b *metricsBuffer) hasProject(key projectKey) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.flushing[key] > 0 {
+		return true
+	}
+	for bufKey := range b.buffers {
+		if bufKey.project == key {
+			return true
+		}
+	}
+	return false
+}
+
+// done marks a buffer that was taken out of buffers to be written as
+// written or restored
+func (b *metricsBuffer) done(bufKey metricsBufferKey) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flushing[bufKey.project]--
+	if b.flushing[bufKey.project] == 0 {
+		delete(b.flushing, bufKey.project)
+	}
+}
+
+// add buffers points for an experiment, and returns true if the buffer for
+// that experiment is full and should be flushed
+func (b *metricsBuffer) add(key projectKey, proj *project.Project, experimentID string, points []project.MetricPoint) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bufKey := metricsBufferKey{key, experimentID}
+	buf, ok := b.buffers[bufKey]
+	if !ok {
+		buf = &bufferedMetrics{proj: proj}
+		b.buffers[bufKey] = buf
+	}
+	buf.points = append(buf.points, points...)
+	return len(buf.points) >= maxBufferedMetricPoints
+}
+
+// discard drops the buffered points for an experiment
+func (b *metricsBuffer) discard(key projectKey, experimentID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.buffers, metricsBufferKey{key, experimentID})
+}
+
+// flush writes the buffered points for an experiment to the repository
+func (b *metricsBuffer) flush(key projectKey, experimentID string) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	bufKey := metricsBufferKey{key, experimentID}
+	b.mu.Lock()
+	buf, ok := b.buffers[bufKey]
+	if ok {
+		delete(b.buffers, bufKey)
+		b.flushing[key]++
+	}
+	b.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	defer b.done(bufKey)
+	if err := buf.proj.LogMetrics(experimentID, buf.points); err != nil {
+		b.restore(bufKey, buf)
+		return err
+	}
+	return nil
+}
+
+// flushAll writes all buffered points to the repository. Points that fail
+// to be written are kept, so they are retried on the next flush.
+func (b *metricsBuffer) flushAll() {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	buffers := b.buffers
+	b.buffers = make(map[metricsBufferKey]*bufferedMetrics)
+	for bufKey := range buffers {
+		b.flushing[bufKey.project]++
+	}
+	b.mu.Unlock()
+
+	for bufKey, buf := range buffers {
+		if err := buf.proj.LogMetrics(bufKey.experimentID, buf.points); err != nil {
+			console.Error("Failed to save metrics for experiment %s: %v", bufKey.experimentID, err)
+			b.restore(bufKey, buf)
+		}
+		b.done(bufKey)
+	}
+}
+
+// pending returns descriptions of the experiments that have buffered points
+func (b *metricsBuffer) pending() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	descriptions := []string{}
+	for bufKey := range b.buffers {
+		descriptions = append(descriptions, "metrics for experiment "+bufKey.experimentID)
+	}
+	sort.Strings(descriptions)
+	return descriptions
+}
+
+// restore puts points that failed to be written back at the front of the
+// buffer. It must be called with b.flushMu held.
+func (b *metricsBuffer) restore(bufKey metricsBufferKey, buf *bufferedMetrics) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if newer, ok := b.buffers[bufKey]; ok {
+		buf.points = append(buf.points, newer.points...)
+	}
+	b.buffers[bufKey] = buf
+}
+
+// flushPeriodically flushes all buffered points every metricsFlushInterval
+// until done is closed
+func (b *metricsBuffer) flushPeriodically(done <-chan struct{}) {
+	ticker := time.NewTicker(metricsFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			b.flushAll()
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package shared
+
+import (
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// OpenAPI returns an OpenAPI 3 description of the HTTP gateway, generated
+// from the Daemon service in keepsake.proto. It is checked in as
+// proto/keepsake.openapi.json.
+func OpenAPI() ([]byte, error) {
+	schemas := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"error": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"code":    map[string]interface{}{"type": "string"},
+						"message": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+	paths := map[string]interface{}{}
+
+	methods := daemonServiceDescriptor.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+		addOpenAPISchema(schemas, method.Input())
+		addOpenAPISchema(schemas, method.Output())
+
+		contentType := "application/json"
+		description := "The reply"
+		if method.IsStreamingServer() {
+			contentType = "application/x-ndjson"
+			description = "A stream of replies, one JSON object per line"
+		}
+		paths[gatewayPathPrefix+gatewayMethodName(method)] = map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": gatewayMethodName(method),
+				"requestBody": map[string]interface{}{
+					"required": true,
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": openAPIRef(method.Input())},
+					},
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": description,
+						"content": map[string]interface{}{
+							contentType: map[string]interface{}{"schema": openAPIRef(method.Output())},
+						},
+					},
+					"default": map[string]interface{}{
+						"description": "An error",
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	spec := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Keepsake daemon",
+			"description": "JSON over HTTP interface to keepsake-daemon. Generated from keepsake.proto, do not edit.",
+			"version":     "1",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []interface{}{map[string]interface{}{"bearerAuth": []interface{}{}}},
+	}
+	// map keys are sorted by json.Marshal, so the output is stable
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// openAPISchemaName returns the schema name of a message, e.g.
+// GetExperimentStatusReply.Status -> GetExperimentStatusReply_Status
+func openAPISchemaName(name protoreflect.FullName) string {
+	return strings.ReplaceAll(strings.TrimPrefix(string(name), "service."), ".", "_")
+}
+
+func openAPIRef(msg protoreflect.MessageDescriptor) map[string]interface{} {
+	if schema := openAPIWellKnownSchema(msg); schema != nil {
+		return schema
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + openAPISchemaName(msg.FullName())}
+}
+
+// openAPIWellKnownSchema returns the inline schema of messages that have a
+// special JSON representation in gateway_json.go
+func openAPIWellKnownSchema(msg protoreflect.MessageDescriptor) map[string]interface{} {
+	switch msg.FullName() {
+	case paramTypeName:
+		return map[string]interface{}{
+			"description": "Any JSON value",
+			"nullable":    true,
+		}
+	case timestampName:
+		return map[string]interface{}{"type": "string", "format": "date-time", "nullable": true}
+	case durationName:
+		return map[string]interface{}{
+			"type":        "string",
+			"description": "A duration like \"2s\" or \"500ms\"",
+			"nullable":    true,
+		}
+	case fieldMaskName:
+		return map[string]interface{}{
+			"type":     "array",
+			"items":    map[string]interface{}{"type": "string"},
+			"nullable": true,
+		}
+	}
+	return nil
+}
+
+// addOpenAPISchema adds the schemas of msg and of every message it uses
+func addOpenAPISchema(schemas map[string]interface{}, msg protoreflect.MessageDescriptor) {
+	name := openAPISchemaName(msg.FullName())
+	if _, ok := schemas[name]; ok || openAPIWellKnownSchema(msg) != nil {
+		return
+	}
+	properties := map[string]interface{}{}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	// add before recursing, in case messages refer to themselves
+	schemas[name] = schema
+
+	fields := msg.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		var fieldSchema map[string]interface{}
+		switch {
+		case fd.IsMap():
+			fieldSchema = map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": openAPIFieldSchema(schemas, fd.MapValue()),
+			}
+		case fd.IsList():
+			fieldSchema = map[string]interface{}{
+				"type":  "array",
+				"items": openAPIFieldSchema(schemas, fd),
+			}
+		default:
+			fieldSchema = openAPIFieldSchema(schemas, fd)
+		}
+		properties[jsonFieldName(fd)] = fieldSchema
+	}
+}
+
+// openAPIFieldSchema returns the schema of a single value of a field
+func openAPIFieldSchema(schemas map[string]interface{}, fd protoreflect.FieldDescriptor) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		addOpenAPISchema(schemas, fd.Message())
+		ref := openAPIRef(fd.Message())
+		if _, isRef := ref["$ref"]; isRef && !fd.IsList() && !fd.IsMap() {
+			// unset messages are written as null
+			return map[string]interface{}{"allOf": []interface{}{ref}, "nullable": true}
+		}
+		return ref
+	case protoreflect.EnumKind:
+		enum := []interface{}{}
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			enum = append(enum, jsonEnumName(values.Get(i)))
+		}
+		return map[string]interface{}{"type": "string", "enum": enum}
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	}
+	return map[string]interface{}{}
+}
@@ -0,0 +1,246 @@
+package shared
+
+import (
+	"container/list"
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+)
+
+// the maximum number of projects a daemon keeps loaded at once
+const maxCachedProjects = 16
+
+// ProjectGetter returns the project for a repository URL and project
+// directory. If both are empty, it returns the project the daemon
+// was started with.
+type ProjectGetter func(repositoryURL string, projectDir string) (proj *project.Project, err error)
+
+// projectKey identifies a project served by the daemon. The zero value
+// is the project the daemon was started with.
+//
+// Several keys can refer to the same project, e.g. a key that only has a
+// directory and a key that also has the repository URL configured in that
+// directory. The cache maps each of them to the project's canonical key,
+// which is made from the repository URL and directory the project was
+// loaded with, and heartbeats and buffered metrics are kept by canonical key.
+type projectKey struct {
+	repositoryURL string
+	directory     string
+}
+
+func projectKeyFromPb(pb *servicepb.Project) projectKey {
+	if pb == nil {
+		return projectKey{}
+	}
+	key := projectKey{
+		repositoryURL: strings.TrimRight(pb.RepositoryURL, "/"),
+		directory:     pb.Directory,
+	}
+	if key.directory != "" {
+		key.directory = filepath.Clean(key.directory)
+	}
+	return key
+}
+
+func projectKeyFromProject(proj *project.Project) projectKey {
+	return projectKey{
+		repositoryURL: strings.TrimRight(proj.RepositoryURL(), "/"),
+		directory:     filepath.Clean(proj.Directory()),
+	}
+}
+
+type projectCacheEntry struct {
+	key  projectKey
+	proj *project.Project
+}
+
+// pendingProjectLoad is a project that is being loaded. Concurrent requests
+// for the same key wait for it to be ready.
+type pendingProjectLoad struct {
+	ready chan struct{}
+	proj  *project.Project
+	key   projectKey
+	err   error
+}
+
+// projectCache is a least-recently-used cache of projects, so a single
+// daemon can serve several projects without reloading them on every request
+type projectCache struct {
+	getter  ProjectGetter
+	maxSize int
+	// inUse returns true if a project has state outside the cache, such as
+	// running heartbeats or buffered metrics. Those projects aren't evicted,
+	// so the same project is never loaded twice.
+	inUse func(key projectKey) bool
+
+	mu sync.Mutex
+	// entries and order are keyed by canonical key
+	entries map[projectKey]*list.Element
+	order   *list.List // most recently used at the front
+	// aliases maps requested keys to canonical keys of cached projects
+	aliases map[projectKey]projectKey
+	pending map[projectKey]*pendingProjectLoad
+}
+
+func newProjectCache(getter ProjectGetter, maxSize int) *projectCache {
+	return &projectCache{
+		getter:  getter,
+		maxSize: maxSize,
+		inUse:   func(projectKey) bool { return false },
+		entries: make(map[projectKey]*list.Element),
+		order:   list.New(),
+		aliases: make(map[projectKey]projectKey),
+		pending: make(map[projectKey]*pendingProjectLoad),
+	}
+}
+
+// get returns the project for key and its canonical key. The project
+// traces its operations as part of the request in ctx.
+func (c *projectCache) get(ctx context.Context, key projectKey) (*project.Project, projectKey, error) {
+	proj, canonical, err := c.load(key)
+	if err != nil {
+		return nil, projectKey{}, err
+	}
+	return proj.WithContext(ctx), canonical, nil
+}
+
+// load returns the project for key and its canonical key, loading the
+// project if it isn't cached. Concurrent requests for the same project wait
+// for a single load.
+func (c *projectCache) load(key projectKey) (*project.Project, projectKey, error) {
+	c.mu.Lock()
+	if canonical, ok := c.aliases[key]; ok {
+		elem := c.entries[canonical]
+		c.order.MoveToFront(elem)
+		c.mu.Unlock()
+		return elem.Value.(*projectCacheEntry).proj, canonical, nil
+	}
+	if load, ok := c.pending[key]; ok {
+		c.mu.Unlock()
+		<-load.ready
+		return load.proj, load.key, load.err
+	}
+	load := &pendingProjectLoad{ready: make(chan struct{})}
+	c.pending[key] = load
+	c.mu.Unlock()
+
+	proj, err := c.getter(key.repositoryURL, key.directory)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, key)
+	if err != nil {
+		// don't cache errors, so the next request tries again
+		load.err = err
+		close(load.ready)
+		return nil, projectKey{}, err
+	}
+	canonical := projectKeyFromProject(proj)
+	if elem, ok := c.entries[canonical]; ok {
+		// another key already loaded this project
+		c.order.MoveToFront(elem)
+		proj = elem.Value.(*projectCacheEntry).proj
+	} else {
+		c.entries[canonical] = c.order.PushFront(&projectCacheEntry{key: canonical, proj: proj})
+	}
+	c.aliases[key] = canonical
+	c.aliases[canonical] = canonical
+	c.evict()
+
+	load.proj, load.key = proj, canonical
+	close(load.ready)
+	return proj, canonical, nil
+}
+
+// evict removes the least recently used projects that aren't in use until
+// the cache is within its maximum size. The most recently used project is
+// never evicted. It must be called with c.mu held.
+func (c *projectCache) evict() {
+	elem := c.order.Back()
+	for c.order.Len() > c.maxSize && elem != c.order.Front() {
+		prev := elem.Prev()
+		key := elem.Value.(*projectCacheEntry).key
+		if !c.inUse(key) {
+			c.order.Remove(elem)
+			delete(c.entries, key)
+			for alias, canonical := range c.aliases {
+				if canonical == key {
+					delete(c.aliases, alias)
+				}
+			}
+		}
+		elem = prev
+	}
+}
+
+// heartbeatRegistry keeps track of the running heartbeats for each project
+type heartbeatRegistry struct {
+	mu         sync.Mutex
+	heartbeats map[projectKey]map[string]*HeartbeatProcess
+}
+
+func newHeartbeatRegistry() *heartbeatRegistry {
+	return &heartbeatRegistry{heartbeats: make(map[projectKey]map[string]*HeartbeatProcess)}
+}
+
+// add registers a heartbeat, killing any previous heartbeat for the same experiment
+func (r *heartbeatRegistry) add(key projectKey, experimentID string, hb *HeartbeatProcess) {
+	r.mu.Lock()
+	byExperimentID, ok := r.heartbeats[key]
+	if !ok {
+		byExperimentID = make(map[string]*HeartbeatProcess)
+		r.heartbeats[key] = byExperimentID
+	}
+	previous := byExperimentID[experimentID]
+	byExperimentID[experimentID] = hb
+	r.mu.Unlock()
+
+	if previous != nil {
+		previous.Kill()
+	}
+}
+
+// remove unregisters and returns the heartbeat for an experiment, or nil if
+// there isn't one
+func (r *heartbeatRegistry) remove(key projectKey, experimentID string) *HeartbeatProcess {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byExperimentID, ok := r.heartbeats[key]
+	if !ok {
+		return nil
+	}
+	hb, ok := byExperimentID[experimentID]
+	if !ok {
+		return nil
+	}
+	delete(byExperimentID, experimentID)
+	if len(byExperimentID) == 0 {
+		delete(r.heartbeats, key)
+	}
+	return hb
+}
+
+// removeAll unregisters and returns all heartbeats
+func (r *heartbeatRegistry) removeAll() []*HeartbeatProcess {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ret := []*HeartbeatProcess{}
+	for _, byExperimentID := range r.heartbeats {
+		for _, hb := range byExperimentID {
+			ret = append(ret, hb)
+		}
+	}
+	r.heartbeats = make(map[projectKey]map[string]*HeartbeatProcess)
+	return ret
+}
+
+// has returns true if a project has running heartbeats
+func (r *heartbeatRegistry) has(key projectKey) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.heartbeats[key]) > 0
+}
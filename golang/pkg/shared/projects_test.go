@@ -0,0 +1,177 @@
+package shared
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/repository"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+)
+
+func countingProjectGetter(t *testing.T) (ProjectGetter, map[projectKey]int, *sync.Mutex) {
+	counts := map[projectKey]int{}
+	mu := new(sync.Mutex)
+	getter := func(repositoryURL string, projectDir string) (*project.Project, error) {
+		mu.Lock()
+		counts[projectKey{repositoryURL, projectDir}]++
+		mu.Unlock()
+		if repositoryURL == "bad://" {
+			return nil, fmt.Errorf("bad repository")
+		}
+		repo, err := repository.NewDiskRepository(t.TempDir())
+		require.NoError(t, err)
+		return project.NewProject(repo, projectDir), nil
+	}
+	return getter, counts, mu
+}
+
+func TestProjectCacheReusesProjects(t *testing.T) {
+	getter, counts, _ := countingProjectGetter(t)
+	cache := newProjectCache(getter, 2)
+
+	key := projectKey{"file://foo", "/foo"}
+	proj1, _, err := cache.load(key)
+	require.NoError(t, err)
+	proj2, _, err := cache.load(key)
+	require.NoError(t, err)
+	require.Same(t, proj1, proj2)
+	require.Equal(t, 1, counts[key])
+
+	defaultProj, _, err := cache.load(projectKey{})
+	require.NoError(t, err)
+	require.NotSame(t, proj1, defaultProj)
+}
+
+func TestProjectCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	getter, counts, _ := countingProjectGetter(t)
+	cache := newProjectCache(getter, 2)
+
+	a := projectKey{"file://a", "/a"}
+	b := projectKey{"file://b", "/b"}
+	c := projectKey{"file://c", "/c"}
+
+	for _, key := range []projectKey{a, b, a, c, a, b} {
+		_, _, err := cache.load(key)
+		require.NoError(t, err)
+	}
+	// b was evicted when c was loaded, and c when b was loaded again
+	require.Equal(t, 1, counts[a])
+	require.Equal(t, 2, counts[b])
+	require.Equal(t, 1, counts[c])
+	require.Equal(t, 2, cache.order.Len())
+}
+
+func TestProjectCacheDoesNotCacheErrors(t *testing.T) {
+	getter, counts, _ := countingProjectGetter(t)
+	cache := newProjectCache(getter, 2)
+
+	key := projectKey{"bad://", ""}
+	_, _, err := cache.load(key)
+	require.Error(t, err)
+	_, _, err = cache.load(key)
+	require.Error(t, err)
+	require.Equal(t, 2, counts[key])
+}
+
+func TestProjectCacheConcurrentLoads(t *testing.T) {
+	getter, counts, mu := countingProjectGetter(t)
+	cache := newProjectCache(getter, 2)
+
+	key := projectKey{"file://foo", "/foo"}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := cache.load(key)
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, 1, counts[key])
+}
+
+// resolvingProjectGetter returns a getter that resolves an empty repository
+// URL and directory like the daemon does, using a repository in baseDir
+func resolvingProjectGetter(t *testing.T, baseDir string) (ProjectGetter, map[projectKey]int) {
+	counts := map[projectKey]int{}
+	getter := func(repositoryURL string, projectDir string) (*project.Project, error) {
+		counts[projectKey{repositoryURL, projectDir}]++
+		if repositoryURL == "" {
+			repositoryURL = "file://" + filepath.Join(baseDir, "repo")
+		}
+		if projectDir == "" {
+			projectDir = "/default"
+		}
+		repo, err := repository.NewDiskRepository(strings.TrimPrefix(repositoryURL, "file://"))
+		require.NoError(t, err)
+		return project.NewProject(repo, projectDir), nil
+	}
+	return getter, counts
+}
+
+func TestProjectCacheResolvesKeys(t *testing.T) {
+	baseDir := t.TempDir()
+	getter, counts := resolvingProjectGetter(t, baseDir)
+	cache := newProjectCache(getter, 2)
+
+	defaultProj, defaultKey, err := cache.load(projectKey{})
+	require.NoError(t, err)
+	require.Equal(t, projectKey{"file://" + filepath.Join(baseDir, "repo"), "/default"}, defaultKey)
+
+	for _, pb := range []*servicepb.Project{
+		nil,
+		{Directory: "/default"},
+		{Directory: "/default/"},
+		{Directory: "/other/../default"},
+		{RepositoryURL: "file://" + filepath.Join(baseDir, "repo"), Directory: "/default"},
+		{RepositoryURL: "file://" + filepath.Join(baseDir, "repo") + "/", Directory: "/default"},
+	} {
+		proj, key, err := cache.load(projectKeyFromPb(pb))
+		require.NoError(t, err)
+		require.Same(t, defaultProj, proj, "%v", pb)
+		require.Equal(t, defaultKey, key, "%v", pb)
+	}
+	// the directory is cleaned and trailing slashes are removed before the
+	// getter is called, so those keys don't load the project again
+	require.Equal(t, 1, counts[projectKey{"", ""}])
+	require.Equal(t, 1, counts[projectKey{"", "/default"}])
+	require.Equal(t, 1, cache.order.Len())
+
+	otherProj, otherKey, err := cache.load(projectKey{directory: "/other"})
+	require.NoError(t, err)
+	require.NotSame(t, defaultProj, otherProj)
+	require.NotEqual(t, defaultKey, otherKey)
+}
+
+func TestProjectCacheDoesNotEvictProjectsInUse(t *testing.T) {
+	getter, counts, _ := countingProjectGetter(t)
+	cache := newProjectCache(getter, 1)
+
+	a := projectKey{"file://a", "/a"}
+	b := projectKey{"file://b", "/b"}
+	c := projectKey{"file://c", "/c"}
+
+	projA, keyA, err := cache.load(a)
+	require.NoError(t, err)
+	cache.inUse = func(key projectKey) bool { return key == keyA }
+
+	for _, key := range []projectKey{b, c, b} {
+		_, _, err := cache.load(key)
+		require.NoError(t, err)
+	}
+	proj, _, err := cache.load(a)
+	require.NoError(t, err)
+	require.Same(t, projA, proj)
+	require.Equal(t, 1, counts[a])
+	require.Equal(t, 2, counts[b])
+	require.Equal(t, 2, cache.order.Len())
+}
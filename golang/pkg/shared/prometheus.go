@@ -0,0 +1,110 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/listenaddress"
+)
+
+var (
+	queueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "keepsake",
+		Subsystem: "daemon",
+		Name:      "work_queue_depth",
+		Help:      "Uploads waiting in the daemon's work queue, not counting the one that is running.",
+	})
+
+	queueRunning = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "keepsake",
+		Subsystem: "daemon",
+		Name:      "work_running",
+		Help:      "Uploads that are currently running.",
+	})
+
+	workDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "keepsake",
+		Subsystem: "daemon",
+		Name:      "work_duration_seconds",
+		Help:      "Time taken by uploads in the daemon's work queue.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8), // 10ms to ~160s
+	}, []string{"operation"})
+
+	workErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "keepsake",
+		Subsystem: "daemon",
+		Name:      "work_errors_total",
+		Help:      "Uploads in the daemon's work queue that failed.",
+	}, []string{"operation"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "keepsake",
+		Subsystem: "daemon",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to handle daemon requests, over gRPC or the HTTP gateway. Streams are measured until they end.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 9), // 1ms to ~65s
+	}, []string{"operation", "code"})
+)
+
+// observeRequest records a request to the daemon method operation that
+// started at start
+func observeRequest(operation string, start time.Time, err error) {
+	requestDuration.WithLabelValues(operation, status.Code(err).String()).Observe(time.Since(start).Seconds())
+}
+
+// grpcMethodName returns "CreateExperiment" for "/service.Daemon/CreateExperiment"
+func grpcMethodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+// metricsInterceptors return interceptors that record the duration and
+// status code of every request
+func metricsInterceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		reply, err := handler(ctx, req)
+		observeRequest(grpcMethodName(info.FullMethod), start, err)
+		return reply, err
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeRequest(grpcMethodName(info.FullMethod), start, err)
+		return err
+	}
+	return unary, stream
+}
+
+// serveMetrics serves Prometheus metrics on /metrics at listenAddress, in
+// the format of ServeOptions.Listen, until the returned server is closed
+func serveMetrics(listenAddress string) (*http.Server, error) {
+	network, address, err := listenaddress.Parse(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to listen on %s: %w", address, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Handler: mux}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			console.Error("Failed to serve metrics: %s", err)
+		}
+	}()
+	console.Debug("Serving metrics on %s://%s/metrics", network, address)
+	return server, nil
+}
@@ -0,0 +1,60 @@
+package shared
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetricsInterceptors(t *testing.T) {
+	unary, _ := metricsInterceptors()
+	before := testutil.CollectAndCount(requestDuration)
+	info := &grpc.UnaryServerInfo{FullMethod: "/service.Daemon/TestMethod"}
+
+	_, err := unary(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	_, err = unary(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	require.Error(t, err)
+
+	// a new series for each status code
+	require.Equal(t, before+2, testutil.CollectAndCount(requestDuration))
+}
+
+func TestServeMetrics(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "metrics.sock")
+	server, err := serveMetrics("unix://" + socketPath)
+	require.NoError(t, err)
+	defer server.Close()
+
+	// requests through the gateway are recorded
+	ts := newTestGateway(t, "")
+	code, reply := postGateway(t, ts, "create_experiment", `{"experiment": {"command": "train.py"}, "disable_heartbeat": true, "quiet": true}`)
+	require.Equal(t, http.StatusOK, code, reply)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+		},
+	}}
+	resp, err := client.Get("http://unix/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `keepsake_daemon_request_duration_seconds_count{code="OK",operation="CreateExperiment"}`)
+	require.Contains(t, string(body), "keepsake_daemon_work_queue_depth")
+}
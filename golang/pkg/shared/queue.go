@@ -0,0 +1,199 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+)
+
+// block requests if there already are this many uploads on the queue, in case
+// uploading is a bottleneck
+// TODO(andreas): warn the user if the queue is full, so they know that they should
+// upload at a lesser interval
+const maxQueuedWork = 2
+
+var errDraining = status.Error(codes.Unavailable, "The daemon is shutting down and isn't accepting new work")
+
+type queuedWork struct {
+	// the kind of work, e.g. "experiment", used to label metrics
+	operation   string
+	description string
+	run         func() error
+}
+
+// workQueue runs uploads in the background, one at a time.
+//
+// Requests that might queue work or write to the repository call begin()
+// before they start and end() when they have finished, so that drain() can
+// stop new requests and then wait for everything that was queued by the
+// requests in flight. Once the queue is draining, it stays draining.
+type workQueue struct {
+	// called with the errors returned by work
+	onError func(error)
+
+	mu       sync.Mutex
+	items    []*queuedWork
+	running  *queuedWork
+	inFlight int
+	draining bool
+	stopped  bool
+	// closed and replaced every time the state above changes
+	changed chan struct{}
+}
+
+func newWorkQueue() *workQueue {
+	return &workQueue{
+		onError: func(err error) {
+			console.Error("%v", err)
+			// TODO(andreas): poll status endpoint, put errors in chan of messages to return. also include progress in these messages
+		},
+		changed: make(chan struct{}),
+	}
+}
+
+// notify wakes everyone waiting for the queue to change. It must be called
+// with q.mu held.
+func (q *workQueue) notify() {
+	queueDepth.Set(float64(len(q.items)))
+	if q.running != nil {
+		queueRunning.Set(1)
+	} else {
+		queueRunning.Set(0)
+	}
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// begin returns an error if the queue is draining, otherwise it registers
+// a request that might add work
+func (q *workQueue) begin() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.draining {
+		return errDraining
+	}
+	q.inFlight++
+	return nil
+}
+
+func (q *workQueue) end() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.inFlight--
+	q.notify()
+}
+
+// add queues work, blocking while the queue is full. It must be called
+// between begin() and end().
+func (q *workQueue) add(operation string, description string, run func() error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) >= maxQueuedWork && !q.stopped {
+		changed := q.changed
+		q.mu.Unlock()
+		<-changed
+		q.mu.Lock()
+	}
+	q.items = append(q.items, &queuedWork{operation: operation, description: description, run: run})
+	q.notify()
+}
+
+// addFromProject adds the work that a project method sent to workChan, if
+// there is any
+func (q *workQueue) addFromProject(operation string, description string, workChan chan func() error) {
+	select {
+	case run := <-workChan:
+		q.add(operation, description, run)
+	default:
+	}
+}
+
+// process runs queued work until stop() is called
+func (q *workQueue) process() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for {
+		for len(q.items) == 0 && !q.stopped {
+			changed := q.changed
+			q.mu.Unlock()
+			<-changed
+			q.mu.Lock()
+		}
+		if q.stopped {
+			return
+		}
+		q.running, q.items = q.items[0], q.items[1:]
+		q.notify()
+		q.mu.Unlock()
+
+		start := time.Now()
+		err := q.running.run()
+		workDuration.WithLabelValues(q.running.operation).Observe(time.Since(start).Seconds())
+		if err != nil {
+			workErrors.WithLabelValues(q.running.operation).Inc()
+			q.onError(err)
+		}
+
+		q.mu.Lock()
+		q.running = nil
+		q.notify()
+	}
+}
+
+// drain stops new requests from adding work, and waits until the requests
+// in flight have finished and all queued work has run, or until ctx is
+// done. It returns the descriptions of the work that hasn't completed.
+func (q *workQueue) drain(ctx context.Context) []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.draining {
+		q.draining = true
+		q.notify()
+	}
+	for q.inFlight > 0 || len(q.items) > 0 || q.running != nil {
+		if q.stopped {
+			break
+		}
+		changed := q.changed
+		q.mu.Unlock()
+		select {
+		case <-changed:
+			q.mu.Lock()
+		case <-ctx.Done():
+			q.mu.Lock()
+			return q.pending()
+		}
+	}
+	return q.pending()
+}
+
+// pending returns the descriptions of running and queued work, and of
+// requests that might still queue work. It must be called with q.mu held.
+func (q *workQueue) pending() []string {
+	descriptions := []string{}
+	if q.inFlight > 0 {
+		descriptions = append(descriptions, fmt.Sprintf("%d requests in progress", q.inFlight))
+	}
+	if q.running != nil {
+		descriptions = append(descriptions, q.running.description)
+	}
+	for _, item := range q.items {
+		descriptions = append(descriptions, item.description)
+	}
+	return descriptions
+}
+
+// stop stops processing after the work that is currently running. Work
+// that is still queued is dropped.
+func (q *workQueue) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stopped = true
+	q.notify()
+}
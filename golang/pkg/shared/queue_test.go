@@ -0,0 +1,71 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWorkQueueDrain(t *testing.T) {
+	q := newWorkQueue()
+	go q.process()
+	defer q.stop()
+
+	require.NoError(t, q.begin())
+	done := []string{}
+	release := make(chan struct{})
+	q.add("experiment", "first", func() error {
+		<-release
+		done = append(done, "first")
+		return nil
+	})
+	q.add("experiment", "second", func() error {
+		done = append(done, "second")
+		return nil
+	})
+	q.end()
+
+	// times out while the first item is blocked
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.Equal(t, []string{"first", "second"}, q.drain(ctx))
+
+	// new work is rejected once draining has started
+	err := q.begin()
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	close(release)
+	require.Equal(t, []string{}, q.drain(context.Background()))
+	require.Equal(t, []string{"first", "second"}, done)
+}
+
+func TestWorkQueueDrainWaitsForRequestsInFlight(t *testing.T) {
+	q := newWorkQueue()
+	go q.process()
+	defer q.stop()
+
+	require.NoError(t, q.begin())
+	drained := make(chan []string)
+	go func() {
+		drained <- q.drain(context.Background())
+	}()
+
+	ran := false
+	select {
+	case <-drained:
+		t.Fatal("Drain returned while a request was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	// requests that started before draining can still add work
+	q.add("checkpoint", "upload", func() error {
+		ran = true
+		return nil
+	})
+	q.end()
+	require.Equal(t, []string{}, <-drained)
+	require.True(t, ran)
+}
@@ -1,36 +1,40 @@
 package shared
 
-// TODO(andreas): document this for R API etc
-
 import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 
 	"github.com/replicate/keepsake/golang/pkg/console"
 	"github.com/replicate/keepsake/golang/pkg/errors"
 	"github.com/replicate/keepsake/golang/pkg/project"
 	"github.com/replicate/keepsake/golang/pkg/servicepb"
+	"github.com/replicate/keepsake/golang/pkg/servicepb/convert"
+	"github.com/replicate/keepsake/golang/pkg/tracing"
 )
 
-type projectGetter func() (proj *project.Project, err error)
-
 type server struct {
 	servicepb.UnimplementedDaemonServer
 
-	workChan                 chan func() error
-	projectGetter            projectGetter
-	project                  *project.Project
-	heartbeatsByExperimentID map[string]*HeartbeatProcess
+	queue      *workQueue
+	health     *health.Server
+	projects   *projectCache
+	heartbeats *heartbeatRegistry
+	metrics    *metricsBuffer
 }
 
 func (s *server) CreateExperiment(ctx context.Context, req *servicepb.CreateExperimentRequest) (*servicepb.CreateExperimentReply, error) {
@@ -38,23 +42,29 @@ func (s *server) CreateExperiment(ctx context.Context, req *servicepb.CreateExpe
 	args := project.CreateExperimentArgs{
 		Path:           pbReqExp.GetPath(),
 		Command:        pbReqExp.GetCommand(),
-		Params:         valueMapFromPb(pbReqExp.GetParams()),
+		Params:         convert.ValueMapFromPb(pbReqExp.GetParams()),
 		PythonPackages: pbReqExp.GetPythonPackages(),
 		PythonVersion:  pbReqExp.GetPythonVersion(),
 	}
-	proj, err := s.getProject()
+	if err := s.queue.begin(); err != nil {
+		return nil, err
+	}
+	defer s.queue.end()
+	proj, key, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
 	if err != nil {
 		return nil, handleError(err)
 	}
-	exp, err := proj.CreateExperiment(args, true, s.workChan, req.Quiet)
+	workChan := make(chan func() error, 1)
+	exp, err := proj.CreateExperiment(args, true, workChan, req.Quiet)
 	if err != nil {
 		return nil, handleError(err)
 	}
+	s.queue.addFromProject("experiment", "files for experiment "+exp.ShortID(), workChan)
 	if !req.DisableHeartbeat {
-		s.heartbeatsByExperimentID[exp.ID] = StartHeartbeat(s.project, exp.ID)
+		s.heartbeats.add(key, exp.ID, StartHeartbeat(proj, exp.ID))
 	}
 
-	pbRetExp := experimentToPb(exp)
+	pbRetExp := convert.ExperimentToPb(exp)
 	return &servicepb.CreateExperimentReply{Experiment: pbRetExp}, nil
 }
 
@@ -62,27 +72,37 @@ func (s *server) CreateCheckpoint(ctx context.Context, req *servicepb.CreateChec
 	pbReqChk := req.GetCheckpoint()
 	args := project.CreateCheckpointArgs{
 		Path:          pbReqChk.GetPath(),
-		Metrics:       valueMapFromPb(pbReqChk.GetMetrics()),
-		PrimaryMetric: primaryMetricFromPb(pbReqChk.PrimaryMetric),
+		Metrics:       convert.ValueMapFromPb(pbReqChk.GetMetrics()),
+		PrimaryMetric: convert.PrimaryMetricFromPb(pbReqChk.PrimaryMetric),
 		Step:          pbReqChk.GetStep(),
 	}
-	proj, err := s.getProject()
+	if err := s.queue.begin(); err != nil {
+		return nil, err
+	}
+	defer s.queue.end()
+	proj, _, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
 	if err != nil {
 		return nil, handleError(err)
 	}
-	chk, err := proj.CreateCheckpoint(args, true, s.workChan, req.Quiet)
+	workChan := make(chan func() error, 1)
+	chk, err := proj.CreateCheckpoint(args, true, workChan, req.Quiet)
 	if err != nil {
 		return nil, handleError(err)
 	}
+	s.queue.addFromProject("checkpoint", "files for checkpoint "+chk.ShortID(), workChan)
 
-	pbRetChk := checkpointToPb(chk)
+	pbRetChk := convert.CheckpointToPb(chk)
 	return &servicepb.CreateCheckpointReply{Checkpoint: pbRetChk}, nil
 }
 
 func (s *server) SaveExperiment(ctx context.Context, req *servicepb.SaveExperimentRequest) (*servicepb.SaveExperimentReply, error) {
 	expPb := req.GetExperiment()
-	exp := experimentFromPb(expPb)
-	proj, err := s.getProject()
+	exp := convert.ExperimentFromPb(expPb)
+	if err := s.queue.begin(); err != nil {
+		return nil, err
+	}
+	defer s.queue.end()
+	proj, _, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
 	if err != nil {
 		return nil, handleError(err)
 	}
@@ -90,18 +110,24 @@ func (s *server) SaveExperiment(ctx context.Context, req *servicepb.SaveExperime
 	if err != nil {
 		return nil, handleError(err)
 	}
-	return &servicepb.SaveExperimentReply{Experiment: experimentToPb(exp)}, nil
+	return &servicepb.SaveExperimentReply{Experiment: convert.ExperimentToPb(exp)}, nil
 }
 
 func (s *server) StopExperiment(ctx context.Context, req *servicepb.StopExperimentRequest) (*servicepb.StopExperimentReply, error) {
-	if _, ok := s.heartbeatsByExperimentID[req.ExperimentID]; ok {
-		s.heartbeatsByExperimentID[req.ExperimentID].Kill()
-		delete(s.heartbeatsByExperimentID, req.ExperimentID)
+	if err := s.queue.begin(); err != nil {
+		return nil, err
 	}
-	proj, err := s.getProject()
+	defer s.queue.end()
+	proj, key, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
 	if err != nil {
 		return nil, handleError(err)
 	}
+	if hb := s.heartbeats.remove(key, req.ExperimentID); hb != nil {
+		hb.Kill()
+	}
+	if err := s.metrics.flush(key, req.ExperimentID); err != nil {
+		return nil, handleError(err)
+	}
 	if err := proj.StopExperiment(req.ExperimentID); err != nil {
 		return nil, handleError(err)
 	}
@@ -109,7 +135,7 @@ func (s *server) StopExperiment(ctx context.Context, req *servicepb.StopExperime
 }
 
 func (s *server) GetExperiment(ctx context.Context, req *servicepb.GetExperimentRequest) (*servicepb.GetExperimentReply, error) {
-	proj, err := s.getProject()
+	proj, _, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
 	if err != nil {
 		return nil, handleError(err)
 	}
@@ -117,38 +143,50 @@ func (s *server) GetExperiment(ctx context.Context, req *servicepb.GetExperiment
 	if err != nil {
 		return nil, handleError(err)
 	}
-	expPb := experimentToPb(exp)
+	expPb := convert.ExperimentToPb(exp)
 	return &servicepb.GetExperimentReply{Experiment: expPb}, nil
 }
 
 func (s *server) ListExperiments(ctx context.Context, req *servicepb.ListExperimentsRequest) (*servicepb.ListExperimentsReply, error) {
-	proj, err := s.getProject()
+	proj, _, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
 	if err != nil {
 		return nil, handleError(err)
 	}
-	experiments, err := proj.Experiments()
+	experiments, nextPageToken, totalSize, err := listExperiments(proj, req)
 	if err != nil {
 		return nil, handleError(err)
 	}
-	experimentsPb := experimentsToPb(experiments)
-	return &servicepb.ListExperimentsReply{Experiments: experimentsPb}, nil
+	experimentsPb := convert.ExperimentsToPb(experiments)
+	for _, expPb := range experimentsPb {
+		applyFieldMask(expPb, req.FieldMask)
+	}
+	return &servicepb.ListExperimentsReply{
+		Experiments:   experimentsPb,
+		NextPageToken: nextPageToken,
+		TotalSize:     int32(totalSize),
+	}, nil
 }
 
 func (s *server) DeleteExperiment(ctx context.Context, req *servicepb.DeleteExperimentRequest) (*servicepb.DeleteExperimentReply, error) {
-	proj, err := s.getProject()
+	if err := s.queue.begin(); err != nil {
+		return nil, err
+	}
+	defer s.queue.end()
+	proj, key, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
 	if err != nil {
 		return nil, handleError(err)
 	}
+	s.metrics.discard(key, req.ExperimentID)
 	exp, err := proj.ExperimentByID(req.ExperimentID)
 	if err != nil {
 		return nil, handleError(err)
 	}
-	if err := s.project.DeleteExperiment(exp); err != nil {
+	if err := proj.DeleteExperiment(exp); err != nil {
 		return nil, handleError(err)
 	}
 	// This is slow, see https://github.com/replicate/keepsake/issues/333
 	for _, checkpoint := range exp.Checkpoints {
-		if err := s.project.DeleteCheckpoint(checkpoint); err != nil {
+		if err := proj.DeleteCheckpoint(checkpoint); err != nil {
 			return nil, handleError(err)
 		}
 	}
@@ -157,7 +195,7 @@ func (s *server) DeleteExperiment(ctx context.Context, req *servicepb.DeleteExpe
 }
 
 func (s *server) CheckoutCheckpoint(ctx context.Context, req *servicepb.CheckoutCheckpointRequest) (*servicepb.CheckoutCheckpointReply, error) {
-	proj, err := s.getProject()
+	proj, _, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
 	if err != nil {
 		return nil, handleError(err)
 	}
@@ -166,7 +204,7 @@ func (s *server) CheckoutCheckpoint(ctx context.Context, req *servicepb.Checkout
 		return nil, handleError(err)
 	}
 
-	err = s.project.CheckoutCheckpoint(chk, exp, req.OutputDirectory, req.Quiet)
+	err = proj.CheckoutCheckpoint(chk, exp, req.OutputDirectory, req.Quiet)
 	if err != nil {
 		return nil, handleError(err)
 	}
@@ -174,7 +212,7 @@ func (s *server) CheckoutCheckpoint(ctx context.Context, req *servicepb.Checkout
 }
 
 func (s *server) GetExperimentStatus(ctx context.Context, req *servicepb.GetExperimentStatusRequest) (*servicepb.GetExperimentStatusReply, error) {
-	proj, err := s.getProject()
+	proj, _, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
 	if err != nil {
 		return nil, handleError(err)
 	}
@@ -191,46 +229,188 @@ func (s *server) GetExperimentStatus(ctx context.Context, req *servicepb.GetExpe
 	return &servicepb.GetExperimentStatusReply{Status: status}, nil
 }
 
-func (s *server) getProject() (*project.Project, error) {
-	// we get the project lazily so that we can return a protobuf exception to the client
-	// as part of a request flow
-
-	if s.project != nil {
-		return s.project, nil
+func (s *server) LogMetrics(ctx context.Context, req *servicepb.LogMetricsRequest) (*servicepb.LogMetricsReply, error) {
+	if err := s.queue.begin(); err != nil {
+		return nil, err
 	}
-
-	proj, err := s.projectGetter()
+	defer s.queue.end()
+	proj, key, err := s.projects.get(ctx, projectKeyFromPb(req.Project))
 	if err != nil {
-		return nil, err
+		return nil, handleError(err)
 	}
-	s.project = proj
-	return proj, nil
+	// check now, because points are only written when they are flushed
+	if err := proj.ValidateExperimentID(req.ExperimentID); err != nil {
+		return nil, handleError(err)
+	}
+	points := convert.MetricPointsFromPb(req.Points)
+	if err := project.ValidateMetricPoints(points); err != nil {
+		return nil, handleError(err)
+	}
+	if full := s.metrics.add(key, proj, req.ExperimentID, points); full {
+		if err := s.metrics.flush(key, req.ExperimentID); err != nil {
+			return nil, handleError(err)
+		}
+	}
+	return &servicepb.LogMetricsReply{}, nil
+}
+
+func (s *server) Drain(ctx context.Context, req *servicepb.DrainRequest) (*servicepb.DrainReply, error) {
+	return &servicepb.DrainReply{PendingWork: s.drain(ctx)}, nil
+}
+
+// drain marks the daemon as not serving, stops it from accepting requests
+// that write to the repository, and waits until queued uploads have
+// completed and buffered metrics are saved, or until ctx is done. It returns
+// descriptions of the work that hasn't been saved. Draining is permanent:
+// the daemon must be restarted to accept writes again.
+func (s *server) drain(ctx context.Context) []string {
+	s.health.Shutdown()
+	pending := s.queue.drain(ctx)
+	if ctx.Err() == nil {
+		s.metrics.flushAll()
+	}
+	return append(pending, s.metrics.pending()...)
+}
+
+// the service name the health service reports the daemon's status for
+var daemonServiceName = string(daemonServiceDescriptor.FullName())
+
+func newServer(projGetter ProjectGetter) *server {
+	s := &server{
+		queue:  newWorkQueue(),
+		health: health.NewServer(),
+		// we get projects lazily so that we can return a protobuf exception to the client
+		// as part of a request flow
+		projects:   newProjectCache(projGetter, maxCachedProjects),
+		heartbeats: newHeartbeatRegistry(),
+		metrics:    newMetricsBuffer(),
+	}
+	s.projects.inUse = func(key projectKey) bool {
+		return s.heartbeats.has(key) || s.metrics.hasProject(key)
+	}
+	s.health.SetServingStatus(daemonServiceName, healthpb.HealthCheckResponse_SERVING)
+	return s
+}
+
+// Server is a daemon gRPC server. Serve() wraps it to listen on a socket
+// and shut down on signals, but it can also be served in-process.
+type Server struct {
+	grpcServer *grpc.Server
+	s          *server
+
+	metricsDone chan struct{}
+	stopOnce    sync.Once
+}
+
+// NewServer returns a server that gets projects from projGetter. It
+// starts processing uploads straight away, so Shutdown() must be called
+// when it is no longer needed.
+func NewServer(projGetter ProjectGetter, serverOpts ...grpc.ServerOption) *Server {
+	// the metrics and tracing interceptors come first so they also
+	// record requests that are rejected by interceptors in serverOpts
+	metricsUnary, metricsStream := metricsInterceptors()
+	tracingUnary, tracingStream := tracing.ServerInterceptors()
+	serverOpts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(tracingUnary, metricsUnary),
+		grpc.ChainStreamInterceptor(tracingStream, metricsStream),
+	}, serverOpts...)
+	srv := &Server{
+		grpcServer:  grpc.NewServer(serverOpts...),
+		s:           newServer(projGetter),
+		metricsDone: make(chan struct{}),
+	}
+	servicepb.RegisterDaemonServer(srv.grpcServer, srv.s)
+	healthpb.RegisterHealthServer(srv.grpcServer, srv.s.health)
+
+	go srv.s.metrics.flushPeriodically(srv.metricsDone)
+	go srv.s.queue.process()
+
+	return srv
 }
 
-func Serve(projGetter projectGetter, socketPath string) error {
+// Serve accepts connections on listener until Shutdown() or Stop() is called
+func (srv *Server) Serve(listener net.Listener) error {
+	return srv.grpcServer.Serve(listener)
+}
+
+// Drain stops the server from accepting new work and waits until pending
+// uploads and metrics are saved, or until ctx is done. It returns
+// descriptions of the work that hasn't been saved. The health service
+// reports the server as not serving from when Drain is called. It can't be
+// undone, so it should only be called before the server is stopped.
+func (srv *Server) Drain(ctx context.Context) []string {
+	return srv.s.drain(ctx)
+}
+
+// Stop stops heartbeats and the server without waiting for pending
+// uploads. Call Drain() first to save them.
+func (srv *Server) Stop() {
+	srv.stopOnce.Do(func() {
+		srv.s.queue.stop()
+		close(srv.metricsDone)
+		for _, hb := range srv.s.heartbeats.removeAll() {
+			hb.Kill()
+		}
+		srv.grpcServer.Stop()
+	})
+}
+
+// Shutdown waits for pending uploads to complete, saves buffered metrics,
+// stops heartbeats, and stops the server
+func (srv *Server) Shutdown() {
+	srv.Drain(context.Background())
+	srv.Stop()
+}
+
+func Serve(projGetter ProjectGetter, opts ServeOptions) error {
 	console.Debug("Starting daemon")
 
-	listener, err := net.Listen("unix", socketPath)
+	serverOpts, err := grpcServerOptions(opts)
 	if err != nil {
-		return fmt.Errorf("Failed to open UNIX socket on %s: %w", socketPath, err)
+		return err
 	}
-
-	grpcServer := grpc.NewServer()
-	s := &server{
-		// block if there already are two items on the queue, in case uploading is a bottleneck
-		// TODO(andreas): warn the user if the queue is full, so they know that they should
-		// upload at a lesser interval
-		workChan:                 make(chan func() error, 2),
-		projectGetter:            projGetter,
-		heartbeatsByExperimentID: make(map[string]*HeartbeatProcess),
+	listener, err := listen(opts.Listen, opts)
+	if err != nil {
+		return err
+	}
+	var httpListener net.Listener
+	if opts.HTTPListen != "" {
+		httpListener, err = listen(opts.HTTPListen, opts)
+		if err != nil {
+			closeListeners(listener)
+			return err
+		}
+	}
+	// metrics are served before the gateway is started, so nothing else
+	// is running if this fails
+	var metricsServer *http.Server
+	if opts.MetricsListen != "" {
+		metricsServer, err = serveMetrics(opts.MetricsListen)
+		if err != nil {
+			closeListeners(listener, httpListener)
+			return err
+		}
 	}
-	servicepb.RegisterDaemonServer(grpcServer, s)
 
-	// when the process exits, make sure any pending
-	// uploads are completed
-	completedChan := make(chan struct{})
+	srv := NewServer(projGetter, serverOpts...)
+
+	var httpServer *http.Server
+	if httpListener != nil {
+		httpServer = &http.Server{Handler: srv.HTTPHandler(opts.AuthToken)}
+		go func() {
+			var err error
+			if opts.TLSCertFile != "" {
+				err = httpServer.ServeTLS(httpListener, opts.TLSCertFile, opts.TLSKeyFile)
+			} else {
+				err = httpServer.Serve(httpListener)
+			}
+			if err != nil && err != http.ErrServerClosed {
+				console.Error("Failed to serve HTTP: %s", err)
+			}
+		}()
+	}
 
-	sigc := make(chan os.Signal, 1)
+	sigc := make(chan os.Signal, 2)
 	signal.Notify(sigc,
 		syscall.SIGHUP,
 		syscall.SIGINT,
@@ -240,13 +420,31 @@ func Serve(projGetter projectGetter, socketPath string) error {
 	go func() {
 		<-sigc
 		console.Debug("Exiting...")
-		s.workChan <- nil // nil is an exit sentinel
 
-		// Wait a short sec so completedChan gets filled if workChan is empty. (Surely there's a more elegant way to do this.)
-		time.Sleep(1 * time.Millisecond)
+		// stop accepting HTTP requests first, the gRPC server
+		// stops accepting work when it is drained
+		if httpServer != nil {
+			if err := httpServer.Close(); err != nil {
+				console.Debug("Failed to close HTTP server: %s", err)
+			}
+		}
 
+		// when the process exits, make sure any pending uploads are
+		// completed, unless we get another signal
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-sigc
+			cancel()
+		}()
+		drained := make(chan []string, 1)
+		go func() {
+			drained <- srv.Drain(ctx)
+		}()
+
+		var pending []string
 		select {
-		case <-completedChan:
+		case pending = <-drained:
 			console.Debug("No work left to do, exiting immediately")
 		// Wait a sec so the log messages displays after KeyboardInterrupt traceback from Python.
 		// If tasks complete within this time, then previous case will be selected and message will never be displayed.
@@ -255,35 +453,33 @@ func Serve(projGetter projectGetter, socketPath string) error {
 		case <-time.After(250 * time.Millisecond):
 			console.Info("Your program has ended, but Keepsake is still saving data. It will exit when it has finished. Hold on...")
 			select {
-			case <-completedChan:
+			case pending = <-drained:
 				console.Debug("Work completed")
 			case <-time.After(5 * time.Second):
 				console.Info("Keepsake is still saving. If you force quit, you might lose data.")
-				<-completedChan
+				pending = <-drained
 			}
 		}
-
-		for _, hb := range s.heartbeatsByExperimentID {
-			hb.Kill()
+		if len(pending) > 0 {
+			console.Warn("Keepsake exited without saving %s", strings.Join(pending, ", "))
 		}
-		grpcServer.Stop()
-	}()
-
-	go func() {
-		for {
-			work := <-s.workChan
-			if work == nil {
-				completedChan <- struct{}{}
-				return
-			}
-			if err := work(); err != nil {
-				console.Error("%v", err)
-				// TODO(andreas): poll status endpoint, put errors in chan of messages to return. also include progress in these messages
+		// metrics are served until the work is drained, so the
+		// drain can be watched
+		if metricsServer != nil {
+			if err := metricsServer.Close(); err != nil {
+				console.Debug("Failed to close metrics server: %s", err)
 			}
 		}
+		srv.Stop()
 	}()
 
-	if err := grpcServer.Serve(listener); err != nil {
+	if err := srv.Serve(listener); err != nil {
+		for _, server := range []*http.Server{httpServer, metricsServer} {
+			if server != nil {
+				server.Close()
+			}
+		}
+		srv.Stop()
 		return fmt.Errorf("Failed to start server: %w", err)
 	}
 
@@ -303,3 +499,16 @@ func handleError(err error) error {
 	}
 	return status.Error(codes.Unknown, err.Error())
 }
+
+// closeListeners closes the listeners that were opened before Serve failed.
+// Listeners can be nil.
+func closeListeners(listeners ...net.Listener) {
+	for _, l := range listeners {
+		if l == nil {
+			continue
+		}
+		if err := l.Close(); err != nil {
+			console.Debug("Failed to close listener on %s: %s", l.Addr(), err)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package shared
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/repository"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+	"github.com/replicate/keepsake/golang/pkg/servicepb/convert"
+)
+
+// newTestServer returns a server for a project in a temporary disk
+// repository, with a worker that runs queued work until the test ends
+func newTestServer(t *testing.T) *server {
+	repo, err := repository.NewDiskRepository(t.TempDir())
+	require.NoError(t, err)
+	return newTestServerForProject(t, project.NewProject(repo, t.TempDir()))
+}
+
+// newTestServerForProject returns a server that uses proj for all requests
+func newTestServerForProject(t *testing.T, proj *project.Project) *server {
+	getter := func(repositoryURL string, projectDir string) (*project.Project, error) {
+		return proj, nil
+	}
+	s := newServer(getter)
+	s.queue.onError = func(err error) {
+		assert.NoError(t, err)
+	}
+	go s.queue.process()
+	t.Cleanup(func() {
+		s.queue.stop()
+		for _, hb := range s.heartbeats.removeAll() {
+			hb.Kill()
+		}
+	})
+	return s
+}
+
+func TestServerConcurrentRequests(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	numExperiments := 20
+	ids := make(chan string, numExperiments)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numExperiments; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			reply, err := s.CreateExperiment(ctx, &servicepb.CreateExperimentRequest{
+				Experiment: &servicepb.Experiment{Command: "train.py"},
+				Quiet:      true,
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			expID := reply.Experiment.Id
+
+			_, err = s.StopExperiment(ctx, &servicepb.StopExperimentRequest{ExperimentID: expID})
+			assert.NoError(t, err)
+			// stopping twice must not block on the heartbeat
+			_, err = s.StopExperiment(ctx, &servicepb.StopExperimentRequest{ExperimentID: expID})
+			assert.NoError(t, err)
+			ids <- expID
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := s.ListExperiments(ctx, &servicepb.ListExperimentsRequest{})
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := s.CreateCheckpoint(ctx, &servicepb.CreateCheckpointRequest{
+				Checkpoint: &servicepb.Checkpoint{Step: 1},
+				Quiet:      true,
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	reply, err := s.ListExperiments(ctx, &servicepb.ListExperimentsRequest{})
+	require.NoError(t, err)
+	require.Len(t, reply.Experiments, numExperiments)
+
+	for expID := range ids {
+		status, err := s.GetExperimentStatus(ctx, &servicepb.GetExperimentStatusRequest{ExperimentID: expID})
+		require.NoError(t, err)
+		require.Equal(t, servicepb.GetExperimentStatusReply_STOPPED, status.Status)
+	}
+}
+
+func TestHeartbeatKillIsIdempotent(t *testing.T) {
+	repo, err := repository.NewDiskRepository(t.TempDir())
+	require.NoError(t, err)
+	proj := project.NewProject(repo, t.TempDir())
+
+	hb := StartHeartbeat(proj, "1eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hb.Kill()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestServerLogMetrics(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	reply, err := s.CreateExperiment(ctx, &servicepb.CreateExperimentRequest{
+		Experiment:       &servicepb.Experiment{},
+		DisableHeartbeat: true,
+		Quiet:            true,
+	})
+	require.NoError(t, err)
+	expID := reply.Experiment.Id
+	proj, key, err := s.projects.get(context.Background(), projectKey{})
+	require.NoError(t, err)
+
+	for step := int64(0); step < 3; step++ {
+		_, err = s.LogMetrics(ctx, &servicepb.LogMetricsRequest{
+			ExperimentID: expID,
+			Points: []*servicepb.MetricPoint{{
+				Step:    step,
+				Metrics: map[string]*servicepb.ParamType{"loss": {Value: &servicepb.ParamType_FloatValue{FloatValue: 1 / float64(step+1)}}},
+			}},
+		})
+		require.NoError(t, err)
+	}
+
+	// points are buffered until they are flushed
+	metrics, err := proj.ExperimentMetrics(expID)
+	require.NoError(t, err)
+	require.Empty(t, metrics.Series)
+	// so the project isn't evicted from the cache
+	require.True(t, s.projects.inUse(key))
+
+	_, err = s.StopExperiment(ctx, &servicepb.StopExperimentRequest{ExperimentID: expID})
+	require.NoError(t, err)
+	require.False(t, s.projects.inUse(key))
+	metrics, err = proj.ExperimentMetrics(expID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{0, 1, 2}, metrics.Series["loss"].Steps)
+
+	_, err = s.LogMetrics(ctx, &servicepb.LogMetricsRequest{
+		ExperimentID: expID,
+		Points: []*servicepb.MetricPoint{{
+			Step:    3,
+			Metrics: map[string]*servicepb.ParamType{"loss": {Value: &servicepb.ParamType_StringValue{StringValue: "low"}}},
+		}},
+	})
+	require.Error(t, err)
+	_, err = s.LogMetrics(ctx, &servicepb.LogMetricsRequest{
+		ExperimentID: "missing",
+		Points: []*servicepb.MetricPoint{{
+			Step:    0,
+			Metrics: map[string]*servicepb.ParamType{"loss": {Value: &servicepb.ParamType_FloatValue{FloatValue: 1}}},
+		}},
+	})
+	require.Error(t, err)
+	require.Empty(t, s.metrics.pending())
+}
+
+func TestServerDrain(t *testing.T) {
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "train.py"), []byte("print(1)"), 0644))
+	repo, err := repository.NewDiskRepository(t.TempDir())
+	require.NoError(t, err)
+	proj := project.NewProject(repo, projectDir)
+	srv := NewServer(func(repositoryURL string, projectDir string) (*project.Project, error) {
+		return proj, nil
+	})
+	defer srv.Stop()
+
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := servicepb.NewDaemonClient(conn)
+	healthClient := healthpb.NewHealthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	healthReply, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: "service.Daemon"})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, healthReply.Status)
+
+	createReply, err := client.CreateExperiment(ctx, &servicepb.CreateExperimentRequest{
+		Experiment:       &servicepb.Experiment{Path: "."},
+		DisableHeartbeat: true,
+		Quiet:            true,
+	})
+	require.NoError(t, err)
+	exp := convert.ExperimentFromPb(createReply.Experiment)
+
+	drainReply, err := client.Drain(ctx, &servicepb.DrainRequest{})
+	require.NoError(t, err)
+	require.Empty(t, drainReply.PendingWork)
+	// the upload has completed
+	_, err = repo.Get(exp.StorageTarPath())
+	require.NoError(t, err)
+
+	healthReply, err = healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: "service.Daemon"})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthReply.Status)
+
+	// requests that write to the repository are rejected
+	_, err = client.CreateExperiment(ctx, &servicepb.CreateExperimentRequest{Quiet: true})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = client.SaveExperiment(ctx, &servicepb.SaveExperimentRequest{Experiment: createReply.Experiment, Quiet: true})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = client.StopExperiment(ctx, &servicepb.StopExperimentRequest{ExperimentID: exp.ID})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = client.DeleteExperiment(ctx, &servicepb.DeleteExperimentRequest{ExperimentID: exp.ID})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	// reads still work
+	_, err = client.GetExperiment(ctx, &servicepb.GetExperimentRequest{ExperimentIDPrefix: exp.ID})
+	require.NoError(t, err)
+}
+
+func TestServeClosesListenersOnError(t *testing.T) {
+	// something else is already listening on the HTTP address
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer busy.Close()
+
+	socketPath := filepath.Join(t.TempDir(), "daemon.sock")
+	err = Serve(nil, ServeOptions{
+		Listen:         "unix://" + socketPath,
+		HTTPListen:     "tcp://" + busy.Addr().String(),
+		InsecureNoAuth: true,
+	})
+	require.Error(t, err)
+
+	// closing the gRPC listener removes its socket
+	_, err = os.Stat(socketPath)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestServeClosesListenersWhenMetricsFail(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer busy.Close()
+
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "daemon.sock")
+	httpSocketPath := filepath.Join(dir, "http.sock")
+	err = Serve(nil, ServeOptions{
+		Listen:        "unix://" + socketPath,
+		HTTPListen:    "unix://" + httpSocketPath,
+		MetricsListen: "tcp://" + busy.Addr().String(),
+	})
+	require.Error(t, err)
+
+	for _, path := range []string{socketPath, httpSocketPath} {
+		_, err = os.Stat(path)
+		require.True(t, os.IsNotExist(err), path)
+	}
+}
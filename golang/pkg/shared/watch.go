@@ -0,0 +1,81 @@
+package shared
+
+import (
+	"time"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/param"
+	"github.com/replicate/keepsake/golang/pkg/project"
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+	"github.com/replicate/keepsake/golang/pkg/servicepb/convert"
+)
+
+const defaultWatchPollInterval = 2 * time.Second
+
+// polling more often than this would mostly be spent listing the repository
+const minWatchPollInterval = 100 * time.Millisecond
+
+func (s *server) WatchExperiments(req *servicepb.WatchExperimentsRequest, stream servicepb.Daemon_WatchExperimentsServer) error {
+	proj, _, err := s.projects.get(stream.Context(), projectKeyFromPb(req.Project))
+	if err != nil {
+		return handleError(err)
+	}
+	filters, err := param.MakeFilters(req.Filters)
+	if err != nil {
+		return handleError(err)
+	}
+	interval := defaultWatchPollInterval
+	if req.PollInterval != nil {
+		interval = req.PollInterval.AsDuration()
+		if interval < minWatchPollInterval {
+			interval = minWatchPollInterval
+		}
+	}
+
+	watcher := proj.Watch()
+	// the first poll returns all existing experiments
+	events, err := watcher.Poll()
+	if err != nil {
+		return handleError(err)
+	}
+	if req.IncludeExisting {
+		if err := sendExperimentEvents(stream, filters, events); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+			events, err := watcher.Poll()
+			if err != nil {
+				// the repository might be temporarily unavailable, so keep watching
+				console.Warn("Failed to check for changes to experiments: %s", err)
+				continue
+			}
+			if err := sendExperimentEvents(stream, filters, events); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func sendExperimentEvents(stream servicepb.Daemon_WatchExperimentsServer, filters *param.Filters, events []*project.ExperimentEvent) error {
+	for _, event := range events {
+		match, err := filters.Matches(project.ListExperimentFromExperiment(event.Experiment, event.Running))
+		if err != nil {
+			return handleError(err)
+		}
+		if !match {
+			continue
+		}
+		if err := stream.Send(convert.ExperimentEventToPb(event)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
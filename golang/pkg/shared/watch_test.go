@@ -0,0 +1,96 @@
+package shared
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/replicate/keepsake/golang/pkg/servicepb"
+)
+
+// dialTestServer serves s in process and returns a client connected to it
+func dialTestServer(t *testing.T, s *server) servicepb.DaemonClient {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	servicepb.RegisterDaemonServer(grpcServer, s)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return servicepb.NewDaemonClient(conn)
+}
+
+func TestWatchExperiments(t *testing.T) {
+	s := newTestServer(t)
+	client := dialTestServer(t, s)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	createExperiment := func(lr float64) string {
+		reply, err := client.CreateExperiment(ctx, &servicepb.CreateExperimentRequest{
+			Experiment: &servicepb.Experiment{
+				Params: map[string]*servicepb.ParamType{"lr": {Value: &servicepb.ParamType_FloatValue{FloatValue: lr}}},
+			},
+			Quiet: true,
+		})
+		require.NoError(t, err)
+		return reply.Experiment.Id
+	}
+
+	existingID := createExperiment(0.1)
+
+	stream, err := client.WatchExperiments(ctx, &servicepb.WatchExperimentsRequest{
+		Filters:         []string{"lr < 1"},
+		IncludeExisting: true,
+		PollInterval:    durationpb.New(10 * time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, servicepb.ExperimentEvent_CREATED, event.Type)
+	require.Equal(t, existingID, event.Experiment.Id)
+	require.True(t, event.Running)
+
+	// filtered out
+	createExperiment(10)
+	newID := createExperiment(0.01)
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, servicepb.ExperimentEvent_CREATED, event.Type)
+	require.Equal(t, newID, event.Experiment.Id)
+
+	_, err = client.StopExperiment(ctx, &servicepb.StopExperimentRequest{ExperimentID: existingID})
+	require.NoError(t, err)
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, servicepb.ExperimentEvent_STOPPED, event.Type)
+	require.Equal(t, existingID, event.Experiment.Id)
+	require.False(t, event.Running)
+}
+
+func TestWatchExperimentsBadFilter(t *testing.T) {
+	s := newTestServer(t)
+	client := dialTestServer(t, s)
+
+	stream, err := client.WatchExperiments(context.Background(), &servicepb.WatchExperimentsRequest{
+		Filters: []string{"lr"},
+	})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Error(t, err)
+}
@@ -3,10 +3,13 @@ package testutil
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/replicate/keepsake/golang/pkg/repository"
 )
 
 func IP(i int) *int64 {
@@ -52,3 +55,16 @@ func TrimRightLines(s string) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+// PutTarFiles saves files, a map of paths to contents, as the tarball at
+// tarPath in repo. The files are inside includePath in the tarball, like
+// the path of an experiment or checkpoint.
+func PutTarFiles(t *testing.T, repo repository.Repository, tarPath string, includePath string, files map[string]string) {
+	dir := t.TempDir()
+	for p, content := range files {
+		p = filepath.Join(dir, includePath, p)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+	require.NoError(t, repo.PutPathTar(dir, tarPath, includePath))
+}
@@ -0,0 +1,142 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadataCarrier lets trace context be read from and written to gRPC
+// metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// rpcAttributes returns attributes for a full gRPC method name like
+// "/service.Daemon/CreateExperiment"
+func rpcAttributes(fullMethod string) []attribute.KeyValue {
+	service, method := splitMethod(fullMethod)
+	return []attribute.KeyValue{
+		semconv.RPCSystemKey.String("grpc"),
+		semconv.RPCServiceKey.String(service),
+		semconv.RPCMethodKey.String(method),
+	}
+}
+
+func splitMethod(fullMethod string) (service string, method string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	i := strings.LastIndex(fullMethod, "/")
+	if i < 0 {
+		return "", fullMethod
+	}
+	return fullMethod[:i], fullMethod[i+1:]
+}
+
+// StartServer starts a span for a request to the daemon method fullMethod,
+// continuing the trace that the client propagated in carrier, if any
+func StartServer(ctx context.Context, fullMethod string, carrier propagation.TextMapCarrier) (context.Context, trace.Span) {
+	ctx = propagator.Extract(ctx, carrier)
+	return otel.Tracer(instrumentationName).Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(rpcAttributes(fullMethod)...))
+}
+
+// StartHTTPServer is StartServer for requests to the JSON over HTTP gateway,
+// which propagate trace context in HTTP headers
+func StartHTTPServer(ctx context.Context, fullMethod string, header http.Header) (context.Context, trace.Span) {
+	return StartServer(ctx, fullMethod, propagation.HeaderCarrier(header))
+}
+
+// endRPC ends span with the gRPC status of err
+func endRPC(span trace.Span, err error) {
+	span.SetAttributes(attribute.Key("rpc.grpc.status_code").Int(int(status.Code(err))))
+	End(span, err)
+}
+
+// ServerInterceptors return interceptors that continue traces propagated by
+// clients and record a span for every request
+func ServerInterceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx, span := StartServer(ctx, info.FullMethod, metadataCarrier(md.Copy()))
+		reply, err := handler(ctx, req)
+		endRPC(span, err)
+		return reply, err
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		ctx, span := StartServer(ss.Context(), info.FullMethod, metadataCarrier(md.Copy()))
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		endRPC(span, err)
+		return err
+	}
+	return unary, stream
+}
+
+// serverStream replaces the context of a stream with one that contains
+// the request's span
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// ClientInterceptors return interceptors that propagate the trace in the
+// context of every request to the daemon. Unary requests also get a span of
+// their own.
+func ClientInterceptors() (grpc.UnaryClientInterceptor, grpc.StreamClientInterceptor) {
+	unary := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := otel.Tracer(instrumentationName).Start(ctx, strings.TrimPrefix(method, "/"),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(rpcAttributes(method)...))
+		err := invoker(inject(ctx), method, req, reply, cc, opts...)
+		endRPC(span, err)
+		return err
+	}
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(inject(ctx), desc, cc, method, opts...)
+	}
+	return unary, stream
+}
+
+// inject adds the trace context in ctx to its outgoing metadata
+func inject(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	propagator.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
@@ -0,0 +1,131 @@
+// Package tracing records OpenTelemetry traces of Keepsake's commands,
+// daemon requests and repository operations.
+//
+// Tracing is disabled unless Setup() is called with an exporter, in which
+// case spans are either sent to an OTLP collector or written to a local
+// file as JSON.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/replicate/keepsake/golang/pkg/console"
+	"github.com/replicate/keepsake/golang/pkg/global"
+)
+
+// EnvironmentVariable overrides the tracing option in keepsake.yaml
+const EnvironmentVariable = "KEEPSAKE_TRACE"
+
+const (
+	exporterOTLP       = "otlp"
+	exporterFilePrefix = "file:"
+
+	instrumentationName = "github.com/replicate/keepsake/golang"
+)
+
+// trace context is propagated in W3C traceparent headers, over both gRPC
+// metadata and HTTP
+var propagator = propagation.TraceContext{}
+
+// ValidateExporter returns an error if exporter isn't a valid value for
+// KEEPSAKE_TRACE or the tracing option in keepsake.yaml
+func ValidateExporter(exporter string) error {
+	if exporter == "" || exporter == exporterOTLP {
+		return nil
+	}
+	if strings.HasPrefix(exporter, exporterFilePrefix) {
+		if strings.TrimPrefix(exporter, exporterFilePrefix) == "" {
+			return fmt.Errorf("Missing path in tracing exporter %q, it should be file:<path>", exporter)
+		}
+		return nil
+	}
+	return fmt.Errorf("Unknown tracing exporter %q, it must be either %q or %q", exporter, exporterOTLP, exporterFilePrefix+"<path>")
+}
+
+// Setup starts exporting spans for serviceName, and returns a function
+// that flushes and stops the exporter.
+//
+// exporter is either "otlp", to send spans to an OTLP collector over HTTP
+// as configured by the standard OTEL_EXPORTER_OTLP_* environment variables,
+// or "file:<path>", to append spans to path as JSON, one per line. Tracing
+// is disabled if exporter is "".
+func Setup(exporter string, serviceName string) (shutdown func(), err error) {
+	if err := ValidateExporter(exporter); err != nil {
+		return nil, err
+	}
+	if exporter == "" {
+		return func() {}, nil
+	}
+
+	var spanExporter sdktrace.SpanExporter
+	var file *os.File
+	if exporter == exporterOTLP {
+		spanExporter, err = otlptracehttp.New(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create OTLP exporter: %w", err)
+		}
+	} else {
+		path := strings.TrimPrefix(exporter, exporterFilePrefix)
+		file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to open trace file: %w", err)
+		}
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("Failed to create file exporter: %w", err)
+		}
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(serviceName),
+		semconv.ServiceVersionKey.String(global.Version),
+	)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	console.Debug("Exporting traces to %s", exporter)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			console.Warn("Failed to export traces: %s", err)
+		}
+		if file != nil {
+			file.Close()
+		}
+	}, nil
+}
+
+// Start starts a span that is a child of the span in ctx, if there is one
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends span, marking it as failed if err is not nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestValidateExporter(t *testing.T) {
+	require.NoError(t, ValidateExporter(""))
+	require.NoError(t, ValidateExporter("otlp"))
+	require.NoError(t, ValidateExporter("file:traces.json"))
+	require.Error(t, ValidateExporter("file:"))
+	require.Error(t, ValidateExporter("jaeger"))
+}
+
+func TestSetupFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup("file:"+path, "keepsake-test")
+	require.NoError(t, err)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	End(child, nil)
+	End(parent, nil)
+	shutdown()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	spans := map[string]map[string]interface{}{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		span := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &span))
+		spans[span["Name"].(string)] = span
+	}
+	require.Len(t, spans, 2)
+	parentContext := spans["parent"]["SpanContext"].(map[string]interface{})
+	childParent := spans["child"]["Parent"].(map[string]interface{})
+	require.Equal(t, parentContext["SpanID"], childParent["SpanID"])
+}
+
+// recordSpans records spans until the test ends
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	})
+	return recorder
+}
+
+func TestPropagation(t *testing.T) {
+	recorder := recordSpans(t)
+	clientUnary, _ := ClientInterceptors()
+	serverUnary, _ := ServerInterceptors()
+	info := &grpc.UnaryServerInfo{FullMethod: "/service.Daemon/ListExperiments"}
+
+	ctx, root := Start(context.Background(), "keepsake ls")
+	var serverSpan trace.SpanContext
+	// the invoker passes the outgoing metadata to the server, as gRPC would
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		_, err := serverUnary(metadata.NewIncomingContext(context.Background(), md), req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			serverSpan = trace.SpanContextFromContext(ctx)
+			return nil, nil
+		})
+		return err
+	}
+	require.NoError(t, clientUnary(ctx, info.FullMethod, nil, nil, nil, invoker))
+	root.End()
+
+	require.Equal(t, root.SpanContext().TraceID(), serverSpan.TraceID())
+	ended := recorder.Ended()
+	require.Len(t, ended, 3)
+	names := []string{}
+	for _, span := range ended {
+		names = append(names, span.Name())
+	}
+	require.Equal(t, []string{"service.Daemon/ListExperiments", "service.Daemon/ListExperiments", "keepsake ls"}, names)
+	require.Equal(t, trace.SpanKindServer, ended[0].SpanKind())
+	require.Equal(t, trace.SpanKindClient, ended[1].SpanKind())
+	require.Equal(t, ended[1].SpanContext().SpanID(), ended[0].Parent().SpanID())
+}
@@ -11,6 +11,8 @@ from google.protobuf import symbol_database as _symbol_database
 _sym_db = _symbol_database.Default()
 
 
+from google.protobuf import duration_pb2 as google_dot_protobuf_dot_duration__pb2
+from google.protobuf import field_mask_pb2 as google_dot_protobuf_dot_field__mask__pb2
 from google.protobuf import timestamp_pb2 as google_dot_protobuf_dot_timestamp__pb2
 
 
@@ -18,11 +20,11 @@ DESCRIPTOR = _descriptor.FileDescriptor(
   name='keepsake.proto',
   package='service',
   syntax='proto3',
-  serialized_options=b'Z.github.com/replicate/keepsake/golang/pkg/servicepb',
+  serialized_options=b'Z2github.com/replicate/keepsake/golang/pkg/servicepb',
   create_key=_descriptor._internal_create_key,
-  serialized_pb=b'\n\x0ekeepsake.proto\x12\x07service\x1a\x1fgoogle/protobuf/timestamp.proto\"k\n\x17\x43reateExperimentRequest\x12\'\n\nexperiment\x18\x01 \x01(\x0b\x32\x13.service.Experiment\x12\x18\n\x10\x64isableHeartbeat\x18\x02 \x01(\x08\x12\r\n\x05quiet\x18\x03 \x01(\x08\"@\n\x15\x43reateExperimentReply\x12\'\n\nexperiment\x18\x01 \x01(\x0b\x32\x13.service.Experiment\"Q\n\x17\x43reateCheckpointRequest\x12\'\n\ncheckpoint\x18\x01 \x01(\x0b\x32\x13.service.Checkpoint\x12\r\n\x05quiet\x18\x02 \x01(\x08\"@\n\x15\x43reateCheckpointReply\x12\'\n\ncheckpoint\x18\x01 \x01(\x0b\x32\x13.service.Checkpoint\"O\n\x15SaveExperimentRequest\x12\'\n\nexperiment\x18\x01 \x01(\x0b\x32\x13.service.Experiment\x12\r\n\x05quiet\x18\x02 \x01(\x08\">\n\x13SaveExperimentReply\x12\'\n\nexperiment\x18\x01 \x01(\x0b\x32\x13.service.Experiment\"-\n\x15StopExperimentRequest\x12\x14\n\x0c\x65xperimentID\x18\x01 \x01(\t\"\x15\n\x13StopExperimentReply\"2\n\x14GetExperimentRequest\x12\x1a\n\x12\x65xperimentIDPrefix\x18\x01 \x01(\t\"=\n\x12GetExperimentReply\x12\'\n\nexperiment\x18\x01 \x01(\x0b\x32\x13.service.Experiment\"\x18\n\x16ListExperimentsRequest\"@\n\x14ListExperimentsReply\x12(\n\x0b\x65xperiments\x18\x01 \x03(\x0b\x32\x13.service.Experiment\"/\n\x17\x44\x65leteExperimentRequest\x12\x14\n\x0c\x65xperimentID\x18\x01 \x01(\t\"\x17\n\x15\x44\x65leteExperimentReply\"_\n\x19\x43heckoutCheckpointRequest\x12\x1a\n\x12\x63heckpointIDPrefix\x18\x01 \x01(\t\x12\x17\n\x0foutputDirectory\x18\x02 \x01(\t\x12\r\n\x05quiet\x18\x03 \x01(\x08\"\x19\n\x17\x43heckoutCheckpointReply\"2\n\x1aGetExperimentStatusRequest\x12\x14\n\x0c\x65xperimentID\x18\x01 \x01(\t\"x\n\x18GetExperimentStatusReply\x12\x38\n\x06status\x18\x01 \x01(\x0e\x32(.service.GetExperimentStatusReply.Status\"\"\n\x06Status\x12\x0b\n\x07RUNNING\x10\x00\x12\x0b\n\x07STOPPED\x10\x01\"\xe7\x03\n\nExperiment\x12\n\n\x02id\x18\x01 \x01(\t\x12+\n\x07\x63reated\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12/\n\x06params\x18\x03 \x03(\x0b\x32\x1f.service.Experiment.ParamsEntry\x12\x0c\n\x04host\x18\x04 \x01(\t\x12\x0c\n\x04user\x18\x05 \x01(\t\x12\x1f\n\x06\x63onfig\x18\x06 \x01(\x0b\x32\x0f.service.Config\x12\x0f\n\x07\x63ommand\x18\x07 \x01(\t\x12\x0c\n\x04path\x18\x08 \x01(\t\x12?\n\x0epythonPackages\x18\t \x03(\x0b\x32\'.service.Experiment.PythonPackagesEntry\x12\x15\n\rpythonVersion\x18\n \x01(\t\x12(\n\x0b\x63heckpoints\x18\x0b \x03(\x0b\x32\x13.service.Checkpoint\x12\x17\n\x0fkeepsakeVersion\x18\x0c \x01(\t\x1a\x41\n\x0bParamsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12!\n\x05value\x18\x02 \x01(\x0b\x32\x12.service.ParamType:\x02\x38\x01\x1a\x35\n\x13PythonPackagesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"-\n\x06\x43onfig\x12\x12\n\nrepository\x18\x01 \x01(\t\x12\x0f\n\x07storage\x18\x02 \x01(\t\"\x87\x02\n\nCheckpoint\x12\n\n\x02id\x18\x01 \x01(\t\x12+\n\x07\x63reated\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x31\n\x07metrics\x18\x03 \x03(\x0b\x32 .service.Checkpoint.MetricsEntry\x12\x0c\n\x04step\x18\x04 \x01(\x03\x12\x0c\n\x04path\x18\x05 \x01(\t\x12-\n\rprimaryMetric\x18\x06 \x01(\x0b\x32\x16.service.PrimaryMetric\x1a\x42\n\x0cMetricsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12!\n\x05value\x18\x02 \x01(\x0b\x32\x12.service.ParamType:\x02\x38\x01\"l\n\rPrimaryMetric\x12\x0c\n\x04name\x18\x01 \x01(\t\x12)\n\x04goal\x18\x02 \x01(\x0e\x32\x1b.service.PrimaryMetric.Goal\"\"\n\x04Goal\x12\x0c\n\x08MAXIMIZE\x10\x00\x12\x0c\n\x08MINIMIZE\x10\x01\"\x85\x01\n\tParamType\x12\x13\n\tboolValue\x18\x01 \x01(\x08H\x00\x12\x12\n\x08intValue\x18\x02 \x01(\x03H\x00\x12\x14\n\nfloatValue\x18\x03 \x01(\x01H\x00\x12\x15\n\x0bstringValue\x18\x04 \x01(\tH\x00\x12\x19\n\x0fobjectValueJson\x18\x05 \x01(\tH\x00\x42\x07\n\x05value2\x97\x06\n\x06\x44\x61\x65mon\x12V\n\x10\x43reateExperiment\x12 .service.CreateExperimentRequest\x1a\x1e.service.CreateExperimentReply\"\x00\x12V\n\x10\x43reateCheckpoint\x12 .service.CreateCheckpointRequest\x1a\x1e.service.CreateCheckpointReply\"\x00\x12P\n\x0eSaveExperiment\x12\x1e.service.SaveExperimentRequest\x1a\x1c.service.SaveExperimentReply\"\x00\x12P\n\x0eStopExperiment\x12\x1e.service.StopExperimentRequest\x1a\x1c.service.StopExperimentReply\"\x00\x12M\n\rGetExperiment\x12\x1d.service.GetExperimentRequest\x1a\x1b.service.GetExperimentReply\"\x00\x12S\n\x0fListExperiments\x12\x1f.service.ListExperimentsRequest\x1a\x1d.service.ListExperimentsReply\"\x00\x12V\n\x10\x44\x65leteExperiment\x12 .service.DeleteExperimentRequest\x1a\x1e.service.DeleteExperimentReply\"\x00\x12\\\n\x12\x43heckoutCheckpoint\x12\".service.CheckoutCheckpointRequest\x1a .service.CheckoutCheckpointReply\"\x00\x12_\n\x13GetExperimentStatus\x12#.service.GetExperimentStatusRequest\x1a!.service.GetExperimentStatusReply\"\x00\x42\x30Z.github.com/replicate/keepsake/golang/pkg/servicepbb\x06proto3'
+  serialized_pb=b'\n\x0ekeepsake.proto\x12\x07service\x1a\x1egoogle/protobuf/duration.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8e\x01\n\x17\x43reateExperimentRequest\x12\'\n\nexperiment\x18\x01 \x01(\x0b\x32\x13.service.Experiment\x12\x18\n\x10\x64isableHeartbeat\x18\x02 \x01(\x08\x12\r\n\x05quiet\x18\x03 \x01(\x08\x12!\n\x07project\x18\x04 \x01(\x0b\x32\x10.service.Project\"@\n\x15\x43reateExperimentReply\x12\'\n\nexperiment\x18\x01 \x01(\x0b\x32\x13.service.Experiment\"t\n\x17\x43reateCheckpointRequest\x12\'\n\ncheckpoint\x18\x01 \x01(\x0b\x32\x13.service.Checkpoint\x12\r\n\x05quiet\x18\x02 \x01(\x08\x12!\n\x07project\x18\x03 \x01(\x0b\x32\x10.service.Project\"@\n\x15\x43reateCheckpointReply\x12\'\n\ncheckpoint\x18\x01 \x01(\x0b\x32\x13.service.Checkpoint\"r\n\x15SaveExperimentRequest\x12\'\n\nexperiment\x18\x01 \x01(\x0b\x32\x13.service.Experiment\x12\r\n\x05quiet\x18\x02 \x01(\x08\x12!\n\x07project\x18\x03 \x01(\x0b\x32\x10.service.Project\">\n\x13SaveExperimentReply\x12\'\n\nexperiment\x18\x01 \x01(\x0b\x32\x13.service.Experiment\"P\n\x15StopExperimentRequest\x12\x14\n\x0c\x65xperimentID\x18\x01 \x01(\t\x12!\n\x07project\x18\x02 \x01(\x0b\x32\x10.service.Project\"\x15\n\x13StopExperimentReply\"U\n\x14GetExperimentRequest\x12\x1a\n\x12\x65xperimentIDPrefix\x18\x01 \x01(\t\x12!\n\x07project\x18\x02 \x01(\x0b\x32\x10.service.Project\"=\n\x12GetExperimentReply\x12\'\n\nexperiment\x18\x01 \x01(\x0b\x32\x13.service.Experiment\"\xae\x01\n\x16ListExperimentsRequest\x12!\n\x07project\x18\x01 \x01(\x0b\x32\x10.service.Project\x12\x0f\n\x07\x66ilters\x18\x02 \x03(\t\x12\x0c\n\x04sort\x18\x03 \x01(\t\x12\x10\n\x08pageSize\x18\x04 \x01(\x05\x12\x11\n\tpageToken\x18\x05 \x01(\t\x12-\n\tfieldMask\x18\x06 \x01(\x0b\x32\x1a.google.protobuf.FieldMask\"j\n\x14ListExperimentsReply\x12(\n\x0b\x65xperiments\x18\x01 \x03(\x0b\x32\x13.service.Experiment\x12\x15\n\rnextPageToken\x18\x02 \x01(\t\x12\x11\n\ttotalSize\x18\x03 \x01(\x05\"R\n\x17\x44\x65leteExperimentRequest\x12\x14\n\x0c\x65xperimentID\x18\x01 \x01(\t\x12!\n\x07project\x18\x02 \x01(\x0b\x32\x10.service.Project\"\x17\n\x15\x44\x65leteExperimentReply\"\x82\x01\n\x19\x43heckoutCheckpointRequest\x12\x1a\n\x12\x63heckpointIDPrefix\x18\x01 \x01(\t\x12\x17\n\x0foutputDirectory\x18\x02 \x01(\t\x12\r\n\x05quiet\x18\x03 \x01(\x08\x12!\n\x07project\x18\x04 \x01(\x0b\x32\x10.service.Project\"\x19\n\x17\x43heckoutCheckpointReply\"U\n\x1aGetExperimentStatusRequest\x12\x14\n\x0c\x65xperimentID\x18\x01 \x01(\t\x12!\n\x07project\x18\x02 \x01(\x0b\x32\x10.service.Project\"x\n\x18GetExperimentStatusReply\x12\x38\n\x06status\x18\x01 \x01(\x0e\x32(.service.GetExperimentStatusReply.Status\"\"\n\x06Status\x12\x0b\n\x07RUNNING\x10\x00\x12\x0b\n\x07STOPPED\x10\x01\"r\n\x11LogMetricsRequest\x12\x14\n\x0c\x65xperimentID\x18\x01 \x01(\t\x12$\n\x06points\x18\x02 \x03(\x0b\x32\x14.service.MetricPoint\x12!\n\x07project\x18\x03 \x01(\x0b\x32\x10.service.Project\"\x11\n\x0fLogMetricsReply\"\x93\x01\n\x0bMetricPoint\x12\x0c\n\x04step\x18\x01 \x01(\x03\x12\x32\n\x07metrics\x18\x02 \x03(\x0b\x32!.service.MetricPoint.MetricsEntry\x1a\x42\n\x0cMetricsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12!\n\x05value\x18\x02 \x01(\x0b\x32\x12.service.ParamType:\x02\x38\x01\"\x97\x01\n\x17WatchExperimentsRequest\x12!\n\x07project\x18\x01 \x01(\x0b\x32\x10.service.Project\x12\x0f\n\x07\x66ilters\x18\x02 \x03(\t\x12\x17\n\x0fincludeExisting\x18\x03 \x01(\x08\x12/\n\x0cpollInterval\x18\x04 \x01(\x0b\x32\x19.google.protobuf.Duration\"\xe6\x01\n\x0f\x45xperimentEvent\x12+\n\x04type\x18\x01 \x01(\x0e\x32\x1d.service.ExperimentEvent.Type\x12\'\n\nexperiment\x18\x02 \x01(\x0b\x32\x13.service.Experiment\x12\'\n\ncheckpoint\x18\x03 \x01(\x0b\x32\x13.service.Checkpoint\x12\x0f\n\x07running\x18\x04 \x01(\x08\"C\n\x04Type\x12\x0b\n\x07\x43REATED\x10\x00\x12\x0b\n\x07UPDATED\x10\x01\x12\x14\n\x10\x43HECKPOINT_ADDED\x10\x02\x12\x0b\n\x07STOPPED\x10\x03\"U\n\x14GetCheckpointRequest\x12\x1a\n\x12\x63heckpointIDPrefix\x18\x01 \x01(\t\x12!\n\x07project\x18\x02 \x01(\x0b\x32\x10.service.Project\"f\n\x12GetCheckpointReply\x12\'\n\ncheckpoint\x18\x01 \x01(\x0b\x32\x13.service.Checkpoint\x12\'\n\nexperiment\x18\x02 \x01(\x0b\x32\x13.service.Experiment\"R\n\x17\x44\x65leteCheckpointRequest\x12\x14\n\x0c\x63heckpointID\x18\x01 \x01(\t\x12!\n\x07project\x18\x02 \x01(\x0b\x32\x10.service.Project\"\x17\n\x15\x44\x65leteCheckpointReply\"[\n\x1aListCheckpointFilesRequest\x12\x1a\n\x12\x63heckpointIDPrefix\x18\x01 \x01(\t\x12!\n\x07project\x18\x02 \x01(\x0b\x32\x10.service.Project\")\n\x18ListCheckpointFilesReply\x12\r\n\x05paths\x18\x01 \x03(\t\"\x80\x01\n\x13\x43heckoutPathRequest\x12\x10\n\x08idPrefix\x18\x01 \x01(\t\x12\x0c\n\x04path\x18\x02 \x01(\t\x12\x17\n\x0foutputDirectory\x18\x03 \x01(\t\x12\r\n\x05quiet\x18\x04 \x01(\x08\x12!\n\x07project\x18\x05 \x01(\x0b\x32\x10.service.Project\"\x13\n\x11\x43heckoutPathReply\"h\n\x16\x44iffCheckpointsRequest\x12\x14\n\x0cleftIDPrefix\x18\x01 \x01(\t\x12\x15\n\rrightIDPrefix\x18\x02 \x01(\t\x12!\n\x07project\x18\x03 \x01(\x0b\x32\x10.service.Project\"\xba\x01\n\x14\x44iffCheckpointsReply\x12!\n\x04left\x18\x01 \x01(\x0b\x32\x13.service.Checkpoint\x12\"\n\x05right\x18\x02 \x01(\x0b\x32\x13.service.Checkpoint\x12\x18\n\x10leftExperimentID\x18\x03 \x01(\t\x12\x19\n\x11rightExperimentID\x18\x04 \x01(\t\x12&\n\x08sections\x18\x05 \x03(\x0b\x32\x14.service.DiffSection\"E\n\x0b\x44iffSection\x12\x0c\n\x04name\x18\x01 \x01(\t\x12(\n\x0b\x64ifferences\x18\x02 \x03(\x0b\x32\x13.service.Difference\"^\n\nDifference\x12\x0b\n\x03key\x18\x01 \x01(\t\x12 \n\x04left\x18\x02 \x01(\x0b\x32\x12.service.ParamType\x12!\n\x05right\x18\x03 \x01(\x0b\x32\x12.service.ParamType\"\x0e\n\x0c\x44rainRequest\"!\n\nDrainReply\x12\x13\n\x0bpendingWork\x18\x01 \x03(\t\"3\n\x07Project\x12\x15\n\rrepositoryURL\x18\x01 \x01(\t\x12\x11\n\tdirectory\x18\x02 \x01(\t\"\xe7\x03\n\nExperiment\x12\n\n\x02id\x18\x01 \x01(\t\x12+\n\x07\x63reated\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12/\n\x06params\x18\x03 \x03(\x0b\x32\x1f.service.Experiment.ParamsEntry\x12\x0c\n\x04host\x18\x04 \x01(\t\x12\x0c\n\x04user\x18\x05 \x01(\t\x12\x1f\n\x06\x63onfig\x18\x06 \x01(\x0b\x32\x0f.service.Config\x12\x0f\n\x07\x63ommand\x18\x07 \x01(\t\x12\x0c\n\x04path\x18\x08 \x01(\t\x12?\n\x0epythonPackages\x18\t \x03(\x0b\x32\'.service.Experiment.PythonPackagesEntry\x12\x15\n\rpythonVersion\x18\n \x01(\t\x12(\n\x0b\x63heckpoints\x18\x0b \x03(\x0b\x32\x13.service.Checkpoint\x12\x17\n\x0fkeepsakeVersion\x18\x0c \x01(\t\x1a\x41\n\x0bParamsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12!\n\x05value\x18\x02 \x01(\x0b\x32\x12.service.ParamType:\x02\x38\x01\x1a\x35\n\x13PythonPackagesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"-\n\x06\x43onfig\x12\x12\n\nrepository\x18\x01 \x01(\t\x12\x0f\n\x07storage\x18\x02 \x01(\t\"\x87\x02\n\nCheckpoint\x12\n\n\x02id\x18\x01 \x01(\t\x12+\n\x07\x63reated\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x31\n\x07metrics\x18\x03 \x03(\x0b\x32 .service.Checkpoint.MetricsEntry\x12\x0c\n\x04step\x18\x04 \x01(\x03\x12\x0c\n\x04path\x18\x05 \x01(\t\x12-\n\rprimaryMetric\x18\x06 \x01(\x0b\x32\x16.service.PrimaryMetric\x1a\x42\n\x0cMetricsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12!\n\x05value\x18\x02 \x01(\x0b\x32\x12.service.ParamType:\x02\x38\x01\"l\n\rPrimaryMetric\x12\x0c\n\x04name\x18\x01 \x01(\t\x12)\n\x04goal\x18\x02 \x01(\x0e\x32\x1b.service.PrimaryMetric.Goal\"\"\n\x04Goal\x12\x0c\n\x08MAXIMIZE\x10\x00\x12\x0c\n\x08MINIMIZE\x10\x01\"\x85\x01\n\tParamType\x12\x13\n\tboolValue\x18\x01 \x01(\x08H\x00\x12\x12\n\x08intValue\x18\x02 \x01(\x03H\x00\x12\x14\n\nfloatValue\x18\x03 \x01(\x01H\x00\x12\x15\n\x0bstringValue\x18\x04 \x01(\tH\x00\x12\x19\n\x0fobjectValueJson\x18\x05 \x01(\tH\x00\x42\x07\n\x05value2\x91\x0b\n\x06\x44\x61\x65mon\x12V\n\x10\x43reateExperiment\x12 .service.CreateExperimentRequest\x1a\x1e.service.CreateExperimentReply\"\x00\x12V\n\x10\x43reateCheckpoint\x12 .service.CreateCheckpointRequest\x1a\x1e.service.CreateCheckpointReply\"\x00\x12P\n\x0eSaveExperiment\x12\x1e.service.SaveExperimentRequest\x1a\x1c.service.SaveExperimentReply\"\x00\x12P\n\x0eStopExperiment\x12\x1e.service.StopExperimentRequest\x1a\x1c.service.StopExperimentReply\"\x00\x12M\n\rGetExperiment\x12\x1d.service.GetExperimentRequest\x1a\x1b.service.GetExperimentReply\"\x00\x12S\n\x0fListExperiments\x12\x1f.service.ListExperimentsRequest\x1a\x1d.service.ListExperimentsReply\"\x00\x12V\n\x10\x44\x65leteExperiment\x12 .service.DeleteExperimentRequest\x1a\x1e.service.DeleteExperimentReply\"\x00\x12\\\n\x12\x43heckoutCheckpoint\x12\".service.CheckoutCheckpointRequest\x1a .service.CheckoutCheckpointReply\"\x00\x12_\n\x13GetExperimentStatus\x12#.service.GetExperimentStatusRequest\x1a!.service.GetExperimentStatusReply\"\x00\x12\x44\n\nLogMetrics\x12\x1a.service.LogMetricsRequest\x1a\x18.service.LogMetricsReply\"\x00\x12R\n\x10WatchExperiments\x12 .service.WatchExperimentsRequest\x1a\x18.service.ExperimentEvent\"\x00\x30\x01\x12M\n\rGetCheckpoint\x12\x1d.service.GetCheckpointRequest\x1a\x1b.service.GetCheckpointReply\"\x00\x12V\n\x10\x44\x65leteCheckpoint\x12 .service.DeleteCheckpointRequest\x1a\x1e.service.DeleteCheckpointReply\"\x00\x12_\n\x13ListCheckpointFiles\x12#.service.ListCheckpointFilesRequest\x1a!.service.ListCheckpointFilesReply\"\x00\x12J\n\x0c\x43heckoutPath\x12\x1c.service.CheckoutPathRequest\x1a\x1a.service.CheckoutPathReply\"\x00\x12S\n\x0f\x44iffCheckpoints\x12\x1f.service.DiffCheckpointsRequest\x1a\x1d.service.DiffCheckpointsReply\"\x00\x12\x35\n\x05\x44rain\x12\x15.service.DrainRequest\x1a\x13.service.DrainReply\"\x00\x42\x34Z2github.com/replicate/keepsake/golang/pkg/servicepbb\x06proto3'
   ,
-  dependencies=[google_dot_protobuf_dot_timestamp__pb2.DESCRIPTOR,])
+  dependencies=[google_dot_protobuf_dot_duration__pb2.DESCRIPTOR,google_dot_protobuf_dot_field__mask__pb2.DESCRIPTOR,google_dot_protobuf_dot_timestamp__pb2.DESCRIPTOR,])
 
 
 
@@ -46,11 +48,46 @@ _GETEXPERIMENTSTATUSREPLY_STATUS = _descriptor.EnumDescriptor(
   ],
   containing_type=None,
   serialized_options=None,
-  serialized_start=1142,
-  serialized_end=1176,
+  serialized_start=1683,
+  serialized_end=1717,
 )
 _sym_db.RegisterEnumDescriptor(_GETEXPERIMENTSTATUSREPLY_STATUS)
 
+_EXPERIMENTEVENT_TYPE = _descriptor.EnumDescriptor(
+  name='Type',
+  full_name='service.ExperimentEvent.Type',
+  filename=None,
+  file=DESCRIPTOR,
+  create_key=_descriptor._internal_create_key,
+  values=[
+    _descriptor.EnumValueDescriptor(
+      name='CREATED', index=0, number=0,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='UPDATED', index=1, number=1,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='CHECKPOINT_ADDED', index=2, number=2,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='STOPPED', index=3, number=3,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+  ],
+  containing_type=None,
+  serialized_options=None,
+  serialized_start=2322,
+  serialized_end=2389,
+)
+_sym_db.RegisterEnumDescriptor(_EXPERIMENTEVENT_TYPE)
+
 _PRIMARYMETRIC_GOAL = _descriptor.EnumDescriptor(
   name='Goal',
   full_name='service.PrimaryMetric.Goal',
@@ -71,8 +108,8 @@ _PRIMARYMETRIC_GOAL = _descriptor.EnumDescriptor(
   ],
   containing_type=None,
   serialized_options=None,
-  serialized_start=2055,
-  serialized_end=2089,
+  serialized_start=4422,
+  serialized_end=4456,
 )
 _sym_db.RegisterEnumDescriptor(_PRIMARYMETRIC_GOAL)
 
@@ -106,6 +143,13 @@ _CREATEEXPERIMENTREQUEST = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.CreateExperimentRequest.project', index=3,
+      number=4, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -118,8 +162,8 @@ _CREATEEXPERIMENTREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=60,
-  serialized_end=167,
+  serialized_start=127,
+  serialized_end=269,
 )
 
 
@@ -150,8 +194,8 @@ _CREATEEXPERIMENTREPLY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=169,
-  serialized_end=233,
+  serialized_start=271,
+  serialized_end=335,
 )
 
 
@@ -177,6 +221,13 @@ _CREATECHECKPOINTREQUEST = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.CreateCheckpointRequest.project', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -189,8 +240,8 @@ _CREATECHECKPOINTREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=235,
-  serialized_end=316,
+  serialized_start=337,
+  serialized_end=453,
 )
 
 
@@ -221,8 +272,8 @@ _CREATECHECKPOINTREPLY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=318,
-  serialized_end=382,
+  serialized_start=455,
+  serialized_end=519,
 )
 
 
@@ -248,6 +299,13 @@ _SAVEEXPERIMENTREQUEST = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.SaveExperimentRequest.project', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -260,8 +318,8 @@ _SAVEEXPERIMENTREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=384,
-  serialized_end=463,
+  serialized_start=521,
+  serialized_end=635,
 )
 
 
@@ -292,8 +350,8 @@ _SAVEEXPERIMENTREPLY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=465,
-  serialized_end=527,
+  serialized_start=637,
+  serialized_end=699,
 )
 
 
@@ -312,6 +370,13 @@ _STOPEXPERIMENTREQUEST = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.StopExperimentRequest.project', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -324,8 +389,8 @@ _STOPEXPERIMENTREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=529,
-  serialized_end=574,
+  serialized_start=701,
+  serialized_end=781,
 )
 
 
@@ -349,23 +414,857 @@ _STOPEXPERIMENTREPLY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=576,
-  serialized_end=597,
+  serialized_start=783,
+  serialized_end=804,
+)
+
+
+_GETEXPERIMENTREQUEST = _descriptor.Descriptor(
+  name='GetExperimentRequest',
+  full_name='service.GetExperimentRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='experimentIDPrefix', full_name='service.GetExperimentRequest.experimentIDPrefix', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.GetExperimentRequest.project', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=806,
+  serialized_end=891,
+)
+
+
+_GETEXPERIMENTREPLY = _descriptor.Descriptor(
+  name='GetExperimentReply',
+  full_name='service.GetExperimentReply',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='experiment', full_name='service.GetExperimentReply.experiment', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=893,
+  serialized_end=954,
+)
+
+
+_LISTEXPERIMENTSREQUEST = _descriptor.Descriptor(
+  name='ListExperimentsRequest',
+  full_name='service.ListExperimentsRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.ListExperimentsRequest.project', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='filters', full_name='service.ListExperimentsRequest.filters', index=1,
+      number=2, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='sort', full_name='service.ListExperimentsRequest.sort', index=2,
+      number=3, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='pageSize', full_name='service.ListExperimentsRequest.pageSize', index=3,
+      number=4, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='pageToken', full_name='service.ListExperimentsRequest.pageToken', index=4,
+      number=5, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='fieldMask', full_name='service.ListExperimentsRequest.fieldMask', index=5,
+      number=6, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=957,
+  serialized_end=1131,
+)
+
+
+_LISTEXPERIMENTSREPLY = _descriptor.Descriptor(
+  name='ListExperimentsReply',
+  full_name='service.ListExperimentsReply',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='experiments', full_name='service.ListExperimentsReply.experiments', index=0,
+      number=1, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='nextPageToken', full_name='service.ListExperimentsReply.nextPageToken', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='totalSize', full_name='service.ListExperimentsReply.totalSize', index=2,
+      number=3, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1133,
+  serialized_end=1239,
+)
+
+
+_DELETEEXPERIMENTREQUEST = _descriptor.Descriptor(
+  name='DeleteExperimentRequest',
+  full_name='service.DeleteExperimentRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='experimentID', full_name='service.DeleteExperimentRequest.experimentID', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.DeleteExperimentRequest.project', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1241,
+  serialized_end=1323,
+)
+
+
+_DELETEEXPERIMENTREPLY = _descriptor.Descriptor(
+  name='DeleteExperimentReply',
+  full_name='service.DeleteExperimentReply',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1325,
+  serialized_end=1348,
+)
+
+
+_CHECKOUTCHECKPOINTREQUEST = _descriptor.Descriptor(
+  name='CheckoutCheckpointRequest',
+  full_name='service.CheckoutCheckpointRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='checkpointIDPrefix', full_name='service.CheckoutCheckpointRequest.checkpointIDPrefix', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='outputDirectory', full_name='service.CheckoutCheckpointRequest.outputDirectory', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='quiet', full_name='service.CheckoutCheckpointRequest.quiet', index=2,
+      number=3, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.CheckoutCheckpointRequest.project', index=3,
+      number=4, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1351,
+  serialized_end=1481,
+)
+
+
+_CHECKOUTCHECKPOINTREPLY = _descriptor.Descriptor(
+  name='CheckoutCheckpointReply',
+  full_name='service.CheckoutCheckpointReply',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1483,
+  serialized_end=1508,
+)
+
+
+_GETEXPERIMENTSTATUSREQUEST = _descriptor.Descriptor(
+  name='GetExperimentStatusRequest',
+  full_name='service.GetExperimentStatusRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='experimentID', full_name='service.GetExperimentStatusRequest.experimentID', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.GetExperimentStatusRequest.project', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1510,
+  serialized_end=1595,
+)
+
+
+_GETEXPERIMENTSTATUSREPLY = _descriptor.Descriptor(
+  name='GetExperimentStatusReply',
+  full_name='service.GetExperimentStatusReply',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='status', full_name='service.GetExperimentStatusReply.status', index=0,
+      number=1, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+    _GETEXPERIMENTSTATUSREPLY_STATUS,
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1597,
+  serialized_end=1717,
+)
+
+
+_LOGMETRICSREQUEST = _descriptor.Descriptor(
+  name='LogMetricsRequest',
+  full_name='service.LogMetricsRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='experimentID', full_name='service.LogMetricsRequest.experimentID', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='points', full_name='service.LogMetricsRequest.points', index=1,
+      number=2, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.LogMetricsRequest.project', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1719,
+  serialized_end=1833,
+)
+
+
+_LOGMETRICSREPLY = _descriptor.Descriptor(
+  name='LogMetricsReply',
+  full_name='service.LogMetricsReply',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1835,
+  serialized_end=1852,
+)
+
+
+_METRICPOINT_METRICSENTRY = _descriptor.Descriptor(
+  name='MetricsEntry',
+  full_name='service.MetricPoint.MetricsEntry',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='key', full_name='service.MetricPoint.MetricsEntry.key', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='value', full_name='service.MetricPoint.MetricsEntry.value', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=b'8\001',
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1936,
+  serialized_end=2002,
+)
+
+_METRICPOINT = _descriptor.Descriptor(
+  name='MetricPoint',
+  full_name='service.MetricPoint',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='step', full_name='service.MetricPoint.step', index=0,
+      number=1, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='metrics', full_name='service.MetricPoint.metrics', index=1,
+      number=2, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[_METRICPOINT_METRICSENTRY, ],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1855,
+  serialized_end=2002,
+)
+
+
+_WATCHEXPERIMENTSREQUEST = _descriptor.Descriptor(
+  name='WatchExperimentsRequest',
+  full_name='service.WatchExperimentsRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.WatchExperimentsRequest.project', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='filters', full_name='service.WatchExperimentsRequest.filters', index=1,
+      number=2, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='includeExisting', full_name='service.WatchExperimentsRequest.includeExisting', index=2,
+      number=3, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='pollInterval', full_name='service.WatchExperimentsRequest.pollInterval', index=3,
+      number=4, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2005,
+  serialized_end=2156,
+)
+
+
+_EXPERIMENTEVENT = _descriptor.Descriptor(
+  name='ExperimentEvent',
+  full_name='service.ExperimentEvent',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='type', full_name='service.ExperimentEvent.type', index=0,
+      number=1, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='experiment', full_name='service.ExperimentEvent.experiment', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='checkpoint', full_name='service.ExperimentEvent.checkpoint', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='running', full_name='service.ExperimentEvent.running', index=3,
+      number=4, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+    _EXPERIMENTEVENT_TYPE,
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2159,
+  serialized_end=2389,
+)
+
+
+_GETCHECKPOINTREQUEST = _descriptor.Descriptor(
+  name='GetCheckpointRequest',
+  full_name='service.GetCheckpointRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='checkpointIDPrefix', full_name='service.GetCheckpointRequest.checkpointIDPrefix', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.GetCheckpointRequest.project', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2391,
+  serialized_end=2476,
+)
+
+
+_GETCHECKPOINTREPLY = _descriptor.Descriptor(
+  name='GetCheckpointReply',
+  full_name='service.GetCheckpointReply',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='checkpoint', full_name='service.GetCheckpointReply.checkpoint', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='experiment', full_name='service.GetCheckpointReply.experiment', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2478,
+  serialized_end=2580,
+)
+
+
+_DELETECHECKPOINTREQUEST = _descriptor.Descriptor(
+  name='DeleteCheckpointRequest',
+  full_name='service.DeleteCheckpointRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='checkpointID', full_name='service.DeleteCheckpointRequest.checkpointID', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.DeleteCheckpointRequest.project', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2582,
+  serialized_end=2664,
+)
+
+
+_DELETECHECKPOINTREPLY = _descriptor.Descriptor(
+  name='DeleteCheckpointReply',
+  full_name='service.DeleteCheckpointReply',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2666,
+  serialized_end=2689,
+)
+
+
+_LISTCHECKPOINTFILESREQUEST = _descriptor.Descriptor(
+  name='ListCheckpointFilesRequest',
+  full_name='service.ListCheckpointFilesRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='checkpointIDPrefix', full_name='service.ListCheckpointFilesRequest.checkpointIDPrefix', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.ListCheckpointFilesRequest.project', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2691,
+  serialized_end=2782,
 )
 
 
-_GETEXPERIMENTREQUEST = _descriptor.Descriptor(
-  name='GetExperimentRequest',
-  full_name='service.GetExperimentRequest',
+_LISTCHECKPOINTFILESREPLY = _descriptor.Descriptor(
+  name='ListCheckpointFilesReply',
+  full_name='service.ListCheckpointFilesReply',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   create_key=_descriptor._internal_create_key,
   fields=[
     _descriptor.FieldDescriptor(
-      name='experimentIDPrefix', full_name='service.GetExperimentRequest.experimentIDPrefix', index=0,
-      number=1, type=9, cpp_type=9, label=1,
-      has_default_value=False, default_value=b"".decode('utf-8'),
+      name='paths', full_name='service.ListCheckpointFilesReply.paths', index=0,
+      number=1, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
@@ -381,22 +1280,50 @@ _GETEXPERIMENTREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=599,
-  serialized_end=649,
+  serialized_start=2784,
+  serialized_end=2825,
 )
 
 
-_GETEXPERIMENTREPLY = _descriptor.Descriptor(
-  name='GetExperimentReply',
-  full_name='service.GetExperimentReply',
+_CHECKOUTPATHREQUEST = _descriptor.Descriptor(
+  name='CheckoutPathRequest',
+  full_name='service.CheckoutPathRequest',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   create_key=_descriptor._internal_create_key,
   fields=[
     _descriptor.FieldDescriptor(
-      name='experiment', full_name='service.GetExperimentReply.experiment', index=0,
-      number=1, type=11, cpp_type=10, label=1,
+      name='idPrefix', full_name='service.CheckoutPathRequest.idPrefix', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='path', full_name='service.CheckoutPathRequest.path', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='outputDirectory', full_name='service.CheckoutPathRequest.outputDirectory', index=2,
+      number=3, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='quiet', full_name='service.CheckoutPathRequest.quiet', index=3,
+      number=4, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.CheckoutPathRequest.project', index=4,
+      number=5, type=11, cpp_type=10, label=1,
       has_default_value=False, default_value=None,
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
@@ -413,14 +1340,14 @@ _GETEXPERIMENTREPLY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=651,
-  serialized_end=712,
+  serialized_start=2828,
+  serialized_end=2956,
 )
 
 
-_LISTEXPERIMENTSREQUEST = _descriptor.Descriptor(
-  name='ListExperimentsRequest',
-  full_name='service.ListExperimentsRequest',
+_CHECKOUTPATHREPLY = _descriptor.Descriptor(
+  name='CheckoutPathReply',
+  full_name='service.CheckoutPathReply',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
@@ -438,23 +1365,37 @@ _LISTEXPERIMENTSREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=714,
-  serialized_end=738,
+  serialized_start=2958,
+  serialized_end=2977,
 )
 
 
-_LISTEXPERIMENTSREPLY = _descriptor.Descriptor(
-  name='ListExperimentsReply',
-  full_name='service.ListExperimentsReply',
+_DIFFCHECKPOINTSREQUEST = _descriptor.Descriptor(
+  name='DiffCheckpointsRequest',
+  full_name='service.DiffCheckpointsRequest',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   create_key=_descriptor._internal_create_key,
   fields=[
     _descriptor.FieldDescriptor(
-      name='experiments', full_name='service.ListExperimentsReply.experiments', index=0,
-      number=1, type=11, cpp_type=10, label=3,
-      has_default_value=False, default_value=[],
+      name='leftIDPrefix', full_name='service.DiffCheckpointsRequest.leftIDPrefix', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='rightIDPrefix', full_name='service.DiffCheckpointsRequest.rightIDPrefix', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='project', full_name='service.DiffCheckpointsRequest.project', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
@@ -470,26 +1411,54 @@ _LISTEXPERIMENTSREPLY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=740,
-  serialized_end=804,
+  serialized_start=2979,
+  serialized_end=3083,
 )
 
 
-_DELETEEXPERIMENTREQUEST = _descriptor.Descriptor(
-  name='DeleteExperimentRequest',
-  full_name='service.DeleteExperimentRequest',
+_DIFFCHECKPOINTSREPLY = _descriptor.Descriptor(
+  name='DiffCheckpointsReply',
+  full_name='service.DiffCheckpointsReply',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   create_key=_descriptor._internal_create_key,
   fields=[
     _descriptor.FieldDescriptor(
-      name='experimentID', full_name='service.DeleteExperimentRequest.experimentID', index=0,
-      number=1, type=9, cpp_type=9, label=1,
+      name='left', full_name='service.DiffCheckpointsReply.left', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='right', full_name='service.DiffCheckpointsReply.right', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='leftExperimentID', full_name='service.DiffCheckpointsReply.leftExperimentID', index=2,
+      number=3, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='rightExperimentID', full_name='service.DiffCheckpointsReply.rightExperimentID', index=3,
+      number=4, type=9, cpp_type=9, label=1,
       has_default_value=False, default_value=b"".decode('utf-8'),
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='sections', full_name='service.DiffCheckpointsReply.sections', index=4,
+      number=5, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -502,19 +1471,33 @@ _DELETEEXPERIMENTREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=806,
-  serialized_end=853,
+  serialized_start=3086,
+  serialized_end=3272,
 )
 
 
-_DELETEEXPERIMENTREPLY = _descriptor.Descriptor(
-  name='DeleteExperimentReply',
-  full_name='service.DeleteExperimentReply',
+_DIFFSECTION = _descriptor.Descriptor(
+  name='DiffSection',
+  full_name='service.DiffSection',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   create_key=_descriptor._internal_create_key,
   fields=[
+    _descriptor.FieldDescriptor(
+      name='name', full_name='service.DiffSection.name', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='differences', full_name='service.DiffSection.differences', index=1,
+      number=2, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -527,37 +1510,37 @@ _DELETEEXPERIMENTREPLY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=855,
-  serialized_end=878,
+  serialized_start=3274,
+  serialized_end=3343,
 )
 
 
-_CHECKOUTCHECKPOINTREQUEST = _descriptor.Descriptor(
-  name='CheckoutCheckpointRequest',
-  full_name='service.CheckoutCheckpointRequest',
+_DIFFERENCE = _descriptor.Descriptor(
+  name='Difference',
+  full_name='service.Difference',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   create_key=_descriptor._internal_create_key,
   fields=[
     _descriptor.FieldDescriptor(
-      name='checkpointIDPrefix', full_name='service.CheckoutCheckpointRequest.checkpointIDPrefix', index=0,
+      name='key', full_name='service.Difference.key', index=0,
       number=1, type=9, cpp_type=9, label=1,
       has_default_value=False, default_value=b"".decode('utf-8'),
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
     _descriptor.FieldDescriptor(
-      name='outputDirectory', full_name='service.CheckoutCheckpointRequest.outputDirectory', index=1,
-      number=2, type=9, cpp_type=9, label=1,
-      has_default_value=False, default_value=b"".decode('utf-8'),
+      name='left', full_name='service.Difference.left', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
     _descriptor.FieldDescriptor(
-      name='quiet', full_name='service.CheckoutCheckpointRequest.quiet', index=2,
-      number=3, type=8, cpp_type=7, label=1,
-      has_default_value=False, default_value=False,
+      name='right', full_name='service.Difference.right', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
@@ -573,14 +1556,14 @@ _CHECKOUTCHECKPOINTREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=880,
-  serialized_end=975,
+  serialized_start=3345,
+  serialized_end=3439,
 )
 
 
-_CHECKOUTCHECKPOINTREPLY = _descriptor.Descriptor(
-  name='CheckoutCheckpointReply',
-  full_name='service.CheckoutCheckpointReply',
+_DRAINREQUEST = _descriptor.Descriptor(
+  name='DrainRequest',
+  full_name='service.DrainRequest',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
@@ -598,23 +1581,23 @@ _CHECKOUTCHECKPOINTREPLY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=977,
-  serialized_end=1002,
+  serialized_start=3441,
+  serialized_end=3455,
 )
 
 
-_GETEXPERIMENTSTATUSREQUEST = _descriptor.Descriptor(
-  name='GetExperimentStatusRequest',
-  full_name='service.GetExperimentStatusRequest',
+_DRAINREPLY = _descriptor.Descriptor(
+  name='DrainReply',
+  full_name='service.DrainReply',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   create_key=_descriptor._internal_create_key,
   fields=[
     _descriptor.FieldDescriptor(
-      name='experimentID', full_name='service.GetExperimentStatusRequest.experimentID', index=0,
-      number=1, type=9, cpp_type=9, label=1,
-      has_default_value=False, default_value=b"".decode('utf-8'),
+      name='pendingWork', full_name='service.DrainReply.pendingWork', index=0,
+      number=1, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
@@ -630,23 +1613,30 @@ _GETEXPERIMENTSTATUSREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1004,
-  serialized_end=1054,
+  serialized_start=3457,
+  serialized_end=3490,
 )
 
 
-_GETEXPERIMENTSTATUSREPLY = _descriptor.Descriptor(
-  name='GetExperimentStatusReply',
-  full_name='service.GetExperimentStatusReply',
+_PROJECT = _descriptor.Descriptor(
+  name='Project',
+  full_name='service.Project',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   create_key=_descriptor._internal_create_key,
   fields=[
     _descriptor.FieldDescriptor(
-      name='status', full_name='service.GetExperimentStatusReply.status', index=0,
-      number=1, type=14, cpp_type=8, label=1,
-      has_default_value=False, default_value=0,
+      name='repositoryURL', full_name='service.Project.repositoryURL', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='directory', full_name='service.Project.directory', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
@@ -655,7 +1645,6 @@ _GETEXPERIMENTSTATUSREPLY = _descriptor.Descriptor(
   ],
   nested_types=[],
   enum_types=[
-    _GETEXPERIMENTSTATUSREPLY_STATUS,
   ],
   serialized_options=None,
   is_extendable=False,
@@ -663,8 +1652,8 @@ _GETEXPERIMENTSTATUSREPLY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1056,
-  serialized_end=1176,
+  serialized_start=3492,
+  serialized_end=3543,
 )
 
 
@@ -702,8 +1691,8 @@ _EXPERIMENT_PARAMSENTRY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1546,
-  serialized_end=1611,
+  serialized_start=3913,
+  serialized_end=3978,
 )
 
 _EXPERIMENT_PYTHONPACKAGESENTRY = _descriptor.Descriptor(
@@ -740,8 +1729,8 @@ _EXPERIMENT_PYTHONPACKAGESENTRY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1613,
-  serialized_end=1666,
+  serialized_start=3980,
+  serialized_end=4033,
 )
 
 _EXPERIMENT = _descriptor.Descriptor(
@@ -848,8 +1837,8 @@ _EXPERIMENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1179,
-  serialized_end=1666,
+  serialized_start=3546,
+  serialized_end=4033,
 )
 
 
@@ -887,8 +1876,8 @@ _CONFIG = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1668,
-  serialized_end=1713,
+  serialized_start=4035,
+  serialized_end=4080,
 )
 
 
@@ -926,8 +1915,8 @@ _CHECKPOINT_METRICSENTRY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1913,
-  serialized_end=1979,
+  serialized_start=1936,
+  serialized_end=2002,
 )
 
 _CHECKPOINT = _descriptor.Descriptor(
@@ -992,8 +1981,8 @@ _CHECKPOINT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1716,
-  serialized_end=1979,
+  serialized_start=4083,
+  serialized_end=4346,
 )
 
 
@@ -1032,8 +2021,8 @@ _PRIMARYMETRIC = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1981,
-  serialized_end=2089,
+  serialized_start=4348,
+  serialized_end=4456,
 )
 
 
@@ -1097,20 +2086,54 @@ _PARAMTYPE = _descriptor.Descriptor(
       create_key=_descriptor._internal_create_key,
     fields=[]),
   ],
-  serialized_start=2092,
-  serialized_end=2225,
+  serialized_start=4459,
+  serialized_end=4592,
 )
 
 _CREATEEXPERIMENTREQUEST.fields_by_name['experiment'].message_type = _EXPERIMENT
+_CREATEEXPERIMENTREQUEST.fields_by_name['project'].message_type = _PROJECT
 _CREATEEXPERIMENTREPLY.fields_by_name['experiment'].message_type = _EXPERIMENT
 _CREATECHECKPOINTREQUEST.fields_by_name['checkpoint'].message_type = _CHECKPOINT
+_CREATECHECKPOINTREQUEST.fields_by_name['project'].message_type = _PROJECT
 _CREATECHECKPOINTREPLY.fields_by_name['checkpoint'].message_type = _CHECKPOINT
 _SAVEEXPERIMENTREQUEST.fields_by_name['experiment'].message_type = _EXPERIMENT
+_SAVEEXPERIMENTREQUEST.fields_by_name['project'].message_type = _PROJECT
 _SAVEEXPERIMENTREPLY.fields_by_name['experiment'].message_type = _EXPERIMENT
+_STOPEXPERIMENTREQUEST.fields_by_name['project'].message_type = _PROJECT
+_GETEXPERIMENTREQUEST.fields_by_name['project'].message_type = _PROJECT
 _GETEXPERIMENTREPLY.fields_by_name['experiment'].message_type = _EXPERIMENT
+_LISTEXPERIMENTSREQUEST.fields_by_name['project'].message_type = _PROJECT
+_LISTEXPERIMENTSREQUEST.fields_by_name['fieldMask'].message_type = google_dot_protobuf_dot_field__mask__pb2._FIELDMASK
 _LISTEXPERIMENTSREPLY.fields_by_name['experiments'].message_type = _EXPERIMENT
+_DELETEEXPERIMENTREQUEST.fields_by_name['project'].message_type = _PROJECT
+_CHECKOUTCHECKPOINTREQUEST.fields_by_name['project'].message_type = _PROJECT
+_GETEXPERIMENTSTATUSREQUEST.fields_by_name['project'].message_type = _PROJECT
 _GETEXPERIMENTSTATUSREPLY.fields_by_name['status'].enum_type = _GETEXPERIMENTSTATUSREPLY_STATUS
 _GETEXPERIMENTSTATUSREPLY_STATUS.containing_type = _GETEXPERIMENTSTATUSREPLY
+_LOGMETRICSREQUEST.fields_by_name['points'].message_type = _METRICPOINT
+_LOGMETRICSREQUEST.fields_by_name['project'].message_type = _PROJECT
+_METRICPOINT_METRICSENTRY.fields_by_name['value'].message_type = _PARAMTYPE
+_METRICPOINT_METRICSENTRY.containing_type = _METRICPOINT
+_METRICPOINT.fields_by_name['metrics'].message_type = _METRICPOINT_METRICSENTRY
+_WATCHEXPERIMENTSREQUEST.fields_by_name['project'].message_type = _PROJECT
+_WATCHEXPERIMENTSREQUEST.fields_by_name['pollInterval'].message_type = google_dot_protobuf_dot_duration__pb2._DURATION
+_EXPERIMENTEVENT.fields_by_name['type'].enum_type = _EXPERIMENTEVENT_TYPE
+_EXPERIMENTEVENT.fields_by_name['experiment'].message_type = _EXPERIMENT
+_EXPERIMENTEVENT.fields_by_name['checkpoint'].message_type = _CHECKPOINT
+_EXPERIMENTEVENT_TYPE.containing_type = _EXPERIMENTEVENT
+_GETCHECKPOINTREQUEST.fields_by_name['project'].message_type = _PROJECT
+_GETCHECKPOINTREPLY.fields_by_name['checkpoint'].message_type = _CHECKPOINT
+_GETCHECKPOINTREPLY.fields_by_name['experiment'].message_type = _EXPERIMENT
+_DELETECHECKPOINTREQUEST.fields_by_name['project'].message_type = _PROJECT
+_LISTCHECKPOINTFILESREQUEST.fields_by_name['project'].message_type = _PROJECT
+_CHECKOUTPATHREQUEST.fields_by_name['project'].message_type = _PROJECT
+_DIFFCHECKPOINTSREQUEST.fields_by_name['project'].message_type = _PROJECT
+_DIFFCHECKPOINTSREPLY.fields_by_name['left'].message_type = _CHECKPOINT
+_DIFFCHECKPOINTSREPLY.fields_by_name['right'].message_type = _CHECKPOINT
+_DIFFCHECKPOINTSREPLY.fields_by_name['sections'].message_type = _DIFFSECTION
+_DIFFSECTION.fields_by_name['differences'].message_type = _DIFFERENCE
+_DIFFERENCE.fields_by_name['left'].message_type = _PARAMTYPE
+_DIFFERENCE.fields_by_name['right'].message_type = _PARAMTYPE
 _EXPERIMENT_PARAMSENTRY.fields_by_name['value'].message_type = _PARAMTYPE
 _EXPERIMENT_PARAMSENTRY.containing_type = _EXPERIMENT
 _EXPERIMENT_PYTHONPACKAGESENTRY.containing_type = _EXPERIMENT
@@ -1159,6 +2182,26 @@ DESCRIPTOR.message_types_by_name['CheckoutCheckpointRequest'] = _CHECKOUTCHECKPO
 DESCRIPTOR.message_types_by_name['CheckoutCheckpointReply'] = _CHECKOUTCHECKPOINTREPLY
 DESCRIPTOR.message_types_by_name['GetExperimentStatusRequest'] = _GETEXPERIMENTSTATUSREQUEST
 DESCRIPTOR.message_types_by_name['GetExperimentStatusReply'] = _GETEXPERIMENTSTATUSREPLY
+DESCRIPTOR.message_types_by_name['LogMetricsRequest'] = _LOGMETRICSREQUEST
+DESCRIPTOR.message_types_by_name['LogMetricsReply'] = _LOGMETRICSREPLY
+DESCRIPTOR.message_types_by_name['MetricPoint'] = _METRICPOINT
+DESCRIPTOR.message_types_by_name['WatchExperimentsRequest'] = _WATCHEXPERIMENTSREQUEST
+DESCRIPTOR.message_types_by_name['ExperimentEvent'] = _EXPERIMENTEVENT
+DESCRIPTOR.message_types_by_name['GetCheckpointRequest'] = _GETCHECKPOINTREQUEST
+DESCRIPTOR.message_types_by_name['GetCheckpointReply'] = _GETCHECKPOINTREPLY
+DESCRIPTOR.message_types_by_name['DeleteCheckpointRequest'] = _DELETECHECKPOINTREQUEST
+DESCRIPTOR.message_types_by_name['DeleteCheckpointReply'] = _DELETECHECKPOINTREPLY
+DESCRIPTOR.message_types_by_name['ListCheckpointFilesRequest'] = _LISTCHECKPOINTFILESREQUEST
+DESCRIPTOR.message_types_by_name['ListCheckpointFilesReply'] = _LISTCHECKPOINTFILESREPLY
+DESCRIPTOR.message_types_by_name['CheckoutPathRequest'] = _CHECKOUTPATHREQUEST
+DESCRIPTOR.message_types_by_name['CheckoutPathReply'] = _CHECKOUTPATHREPLY
+DESCRIPTOR.message_types_by_name['DiffCheckpointsRequest'] = _DIFFCHECKPOINTSREQUEST
+DESCRIPTOR.message_types_by_name['DiffCheckpointsReply'] = _DIFFCHECKPOINTSREPLY
+DESCRIPTOR.message_types_by_name['DiffSection'] = _DIFFSECTION
+DESCRIPTOR.message_types_by_name['Difference'] = _DIFFERENCE
+DESCRIPTOR.message_types_by_name['DrainRequest'] = _DRAINREQUEST
+DESCRIPTOR.message_types_by_name['DrainReply'] = _DRAINREPLY
+DESCRIPTOR.message_types_by_name['Project'] = _PROJECT
 DESCRIPTOR.message_types_by_name['Experiment'] = _EXPERIMENT
 DESCRIPTOR.message_types_by_name['Config'] = _CONFIG
 DESCRIPTOR.message_types_by_name['Checkpoint'] = _CHECKPOINT
@@ -1292,6 +2335,154 @@ GetExperimentStatusReply = _reflection.GeneratedProtocolMessageType('GetExperime
   })
 _sym_db.RegisterMessage(GetExperimentStatusReply)
 
+LogMetricsRequest = _reflection.GeneratedProtocolMessageType('LogMetricsRequest', (_message.Message,), {
+  'DESCRIPTOR' : _LOGMETRICSREQUEST,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.LogMetricsRequest)
+  })
+_sym_db.RegisterMessage(LogMetricsRequest)
+
+LogMetricsReply = _reflection.GeneratedProtocolMessageType('LogMetricsReply', (_message.Message,), {
+  'DESCRIPTOR' : _LOGMETRICSREPLY,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.LogMetricsReply)
+  })
+_sym_db.RegisterMessage(LogMetricsReply)
+
+MetricPoint = _reflection.GeneratedProtocolMessageType('MetricPoint', (_message.Message,), {
+
+  'MetricsEntry' : _reflection.GeneratedProtocolMessageType('MetricsEntry', (_message.Message,), {
+    'DESCRIPTOR' : _METRICPOINT_METRICSENTRY,
+    '__module__' : 'keepsake_pb2'
+    # @@protoc_insertion_point(class_scope:service.MetricPoint.MetricsEntry)
+    })
+  ,
+  'DESCRIPTOR' : _METRICPOINT,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.MetricPoint)
+  })
+_sym_db.RegisterMessage(MetricPoint)
+_sym_db.RegisterMessage(MetricPoint.MetricsEntry)
+
+WatchExperimentsRequest = _reflection.GeneratedProtocolMessageType('WatchExperimentsRequest', (_message.Message,), {
+  'DESCRIPTOR' : _WATCHEXPERIMENTSREQUEST,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.WatchExperimentsRequest)
+  })
+_sym_db.RegisterMessage(WatchExperimentsRequest)
+
+ExperimentEvent = _reflection.GeneratedProtocolMessageType('ExperimentEvent', (_message.Message,), {
+  'DESCRIPTOR' : _EXPERIMENTEVENT,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.ExperimentEvent)
+  })
+_sym_db.RegisterMessage(ExperimentEvent)
+
+GetCheckpointRequest = _reflection.GeneratedProtocolMessageType('GetCheckpointRequest', (_message.Message,), {
+  'DESCRIPTOR' : _GETCHECKPOINTREQUEST,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.GetCheckpointRequest)
+  })
+_sym_db.RegisterMessage(GetCheckpointRequest)
+
+GetCheckpointReply = _reflection.GeneratedProtocolMessageType('GetCheckpointReply', (_message.Message,), {
+  'DESCRIPTOR' : _GETCHECKPOINTREPLY,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.GetCheckpointReply)
+  })
+_sym_db.RegisterMessage(GetCheckpointReply)
+
+DeleteCheckpointRequest = _reflection.GeneratedProtocolMessageType('DeleteCheckpointRequest', (_message.Message,), {
+  'DESCRIPTOR' : _DELETECHECKPOINTREQUEST,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.DeleteCheckpointRequest)
+  })
+_sym_db.RegisterMessage(DeleteCheckpointRequest)
+
+DeleteCheckpointReply = _reflection.GeneratedProtocolMessageType('DeleteCheckpointReply', (_message.Message,), {
+  'DESCRIPTOR' : _DELETECHECKPOINTREPLY,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.DeleteCheckpointReply)
+  })
+_sym_db.RegisterMessage(DeleteCheckpointReply)
+
+ListCheckpointFilesRequest = _reflection.GeneratedProtocolMessageType('ListCheckpointFilesRequest', (_message.Message,), {
+  'DESCRIPTOR' : _LISTCHECKPOINTFILESREQUEST,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.ListCheckpointFilesRequest)
+  })
+_sym_db.RegisterMessage(ListCheckpointFilesRequest)
+
+ListCheckpointFilesReply = _reflection.GeneratedProtocolMessageType('ListCheckpointFilesReply', (_message.Message,), {
+  'DESCRIPTOR' : _LISTCHECKPOINTFILESREPLY,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.ListCheckpointFilesReply)
+  })
+_sym_db.RegisterMessage(ListCheckpointFilesReply)
+
+CheckoutPathRequest = _reflection.GeneratedProtocolMessageType('CheckoutPathRequest', (_message.Message,), {
+  'DESCRIPTOR' : _CHECKOUTPATHREQUEST,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.CheckoutPathRequest)
+  })
+_sym_db.RegisterMessage(CheckoutPathRequest)
+
+CheckoutPathReply = _reflection.GeneratedProtocolMessageType('CheckoutPathReply', (_message.Message,), {
+  'DESCRIPTOR' : _CHECKOUTPATHREPLY,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.CheckoutPathReply)
+  })
+_sym_db.RegisterMessage(CheckoutPathReply)
+
+DiffCheckpointsRequest = _reflection.GeneratedProtocolMessageType('DiffCheckpointsRequest', (_message.Message,), {
+  'DESCRIPTOR' : _DIFFCHECKPOINTSREQUEST,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.DiffCheckpointsRequest)
+  })
+_sym_db.RegisterMessage(DiffCheckpointsRequest)
+
+DiffCheckpointsReply = _reflection.GeneratedProtocolMessageType('DiffCheckpointsReply', (_message.Message,), {
+  'DESCRIPTOR' : _DIFFCHECKPOINTSREPLY,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.DiffCheckpointsReply)
+  })
+_sym_db.RegisterMessage(DiffCheckpointsReply)
+
+DiffSection = _reflection.GeneratedProtocolMessageType('DiffSection', (_message.Message,), {
+  'DESCRIPTOR' : _DIFFSECTION,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.DiffSection)
+  })
+_sym_db.RegisterMessage(DiffSection)
+
+Difference = _reflection.GeneratedProtocolMessageType('Difference', (_message.Message,), {
+  'DESCRIPTOR' : _DIFFERENCE,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.Difference)
+  })
+_sym_db.RegisterMessage(Difference)
+
+DrainRequest = _reflection.GeneratedProtocolMessageType('DrainRequest', (_message.Message,), {
+  'DESCRIPTOR' : _DRAINREQUEST,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.DrainRequest)
+  })
+_sym_db.RegisterMessage(DrainRequest)
+
+DrainReply = _reflection.GeneratedProtocolMessageType('DrainReply', (_message.Message,), {
+  'DESCRIPTOR' : _DRAINREPLY,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.DrainReply)
+  })
+_sym_db.RegisterMessage(DrainReply)
+
+Project = _reflection.GeneratedProtocolMessageType('Project', (_message.Message,), {
+  'DESCRIPTOR' : _PROJECT,
+  '__module__' : 'keepsake_pb2'
+  # @@protoc_insertion_point(class_scope:service.Project)
+  })
+_sym_db.RegisterMessage(Project)
+
 Experiment = _reflection.GeneratedProtocolMessageType('Experiment', (_message.Message,), {
 
   'ParamsEntry' : _reflection.GeneratedProtocolMessageType('ParamsEntry', (_message.Message,), {
@@ -1353,6 +2544,7 @@ _sym_db.RegisterMessage(ParamType)
 
 
 DESCRIPTOR._options = None
+_METRICPOINT_METRICSENTRY._options = None
 _EXPERIMENT_PARAMSENTRY._options = None
 _EXPERIMENT_PYTHONPACKAGESENTRY._options = None
 _CHECKPOINT_METRICSENTRY._options = None
@@ -1364,8 +2556,8 @@ _DAEMON = _descriptor.ServiceDescriptor(
   index=0,
   serialized_options=None,
   create_key=_descriptor._internal_create_key,
-  serialized_start=2228,
-  serialized_end=3019,
+  serialized_start=4595,
+  serialized_end=6020,
   methods=[
   _descriptor.MethodDescriptor(
     name='CreateExperiment',
@@ -1457,6 +2649,86 @@ _DAEMON = _descriptor.ServiceDescriptor(
     serialized_options=None,
     create_key=_descriptor._internal_create_key,
   ),
+  _descriptor.MethodDescriptor(
+    name='LogMetrics',
+    full_name='service.Daemon.LogMetrics',
+    index=9,
+    containing_service=None,
+    input_type=_LOGMETRICSREQUEST,
+    output_type=_LOGMETRICSREPLY,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
+  _descriptor.MethodDescriptor(
+    name='WatchExperiments',
+    full_name='service.Daemon.WatchExperiments',
+    index=10,
+    containing_service=None,
+    input_type=_WATCHEXPERIMENTSREQUEST,
+    output_type=_EXPERIMENTEVENT,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
+  _descriptor.MethodDescriptor(
+    name='GetCheckpoint',
+    full_name='service.Daemon.GetCheckpoint',
+    index=11,
+    containing_service=None,
+    input_type=_GETCHECKPOINTREQUEST,
+    output_type=_GETCHECKPOINTREPLY,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
+  _descriptor.MethodDescriptor(
+    name='DeleteCheckpoint',
+    full_name='service.Daemon.DeleteCheckpoint',
+    index=12,
+    containing_service=None,
+    input_type=_DELETECHECKPOINTREQUEST,
+    output_type=_DELETECHECKPOINTREPLY,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
+  _descriptor.MethodDescriptor(
+    name='ListCheckpointFiles',
+    full_name='service.Daemon.ListCheckpointFiles',
+    index=13,
+    containing_service=None,
+    input_type=_LISTCHECKPOINTFILESREQUEST,
+    output_type=_LISTCHECKPOINTFILESREPLY,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
+  _descriptor.MethodDescriptor(
+    name='CheckoutPath',
+    full_name='service.Daemon.CheckoutPath',
+    index=14,
+    containing_service=None,
+    input_type=_CHECKOUTPATHREQUEST,
+    output_type=_CHECKOUTPATHREPLY,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
+  _descriptor.MethodDescriptor(
+    name='DiffCheckpoints',
+    full_name='service.Daemon.DiffCheckpoints',
+    index=15,
+    containing_service=None,
+    input_type=_DIFFCHECKPOINTSREQUEST,
+    output_type=_DIFFCHECKPOINTSREPLY,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
+  _descriptor.MethodDescriptor(
+    name='Drain',
+    full_name='service.Daemon.Drain',
+    index=16,
+    containing_service=None,
+    input_type=_DRAINREQUEST,
+    output_type=_DRAINREPLY,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
 ])
 _sym_db.RegisterServiceDescriptor(_DAEMON)
 
@@ -6,8 +6,17 @@ from google.protobuf.descriptor import (
     FileDescriptor as google___protobuf___descriptor___FileDescriptor,
 )
 
+from google.protobuf.duration_pb2 import (
+    Duration as google___protobuf___duration_pb2___Duration,
+)
+
+from google.protobuf.field_mask_pb2 import (
+    FieldMask as google___protobuf___field_mask_pb2___FieldMask,
+)
+
 from google.protobuf.internal.containers import (
     RepeatedCompositeFieldContainer as google___protobuf___internal___containers___RepeatedCompositeFieldContainer,
+    RepeatedScalarFieldContainer as google___protobuf___internal___containers___RepeatedScalarFieldContainer,
 )
 
 from google.protobuf.internal.enum_type_wrapper import (
@@ -53,14 +62,18 @@ class CreateExperimentRequest(google___protobuf___message___Message):
     @property
     def experiment(self) -> type___Experiment: ...
 
+    @property
+    def project(self) -> type___Project: ...
+
     def __init__(self,
         *,
         experiment : typing___Optional[type___Experiment] = None,
         disableHeartbeat : typing___Optional[builtin___bool] = None,
         quiet : typing___Optional[builtin___bool] = None,
+        project : typing___Optional[type___Project] = None,
         ) -> None: ...
-    def HasField(self, field_name: typing_extensions___Literal[u"experiment",b"experiment"]) -> builtin___bool: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"disableHeartbeat",b"disableHeartbeat",u"experiment",b"experiment",u"quiet",b"quiet"]) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"experiment",b"experiment",u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"disableHeartbeat",b"disableHeartbeat",u"experiment",b"experiment",u"project",b"project",u"quiet",b"quiet"]) -> None: ...
 type___CreateExperimentRequest = CreateExperimentRequest
 
 class CreateExperimentReply(google___protobuf___message___Message):
@@ -84,13 +97,17 @@ class CreateCheckpointRequest(google___protobuf___message___Message):
     @property
     def checkpoint(self) -> type___Checkpoint: ...
 
+    @property
+    def project(self) -> type___Project: ...
+
     def __init__(self,
         *,
         checkpoint : typing___Optional[type___Checkpoint] = None,
         quiet : typing___Optional[builtin___bool] = None,
+        project : typing___Optional[type___Project] = None,
         ) -> None: ...
-    def HasField(self, field_name: typing_extensions___Literal[u"checkpoint",b"checkpoint"]) -> builtin___bool: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"checkpoint",b"checkpoint",u"quiet",b"quiet"]) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"checkpoint",b"checkpoint",u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"checkpoint",b"checkpoint",u"project",b"project",u"quiet",b"quiet"]) -> None: ...
 type___CreateCheckpointRequest = CreateCheckpointRequest
 
 class CreateCheckpointReply(google___protobuf___message___Message):
@@ -114,13 +131,17 @@ class SaveExperimentRequest(google___protobuf___message___Message):
     @property
     def experiment(self) -> type___Experiment: ...
 
+    @property
+    def project(self) -> type___Project: ...
+
     def __init__(self,
         *,
         experiment : typing___Optional[type___Experiment] = None,
         quiet : typing___Optional[builtin___bool] = None,
+        project : typing___Optional[type___Project] = None,
         ) -> None: ...
-    def HasField(self, field_name: typing_extensions___Literal[u"experiment",b"experiment"]) -> builtin___bool: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"experiment",b"experiment",u"quiet",b"quiet"]) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"experiment",b"experiment",u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"experiment",b"experiment",u"project",b"project",u"quiet",b"quiet"]) -> None: ...
 type___SaveExperimentRequest = SaveExperimentRequest
 
 class SaveExperimentReply(google___protobuf___message___Message):
@@ -141,11 +162,16 @@ class StopExperimentRequest(google___protobuf___message___Message):
     DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
     experimentID: typing___Text = ...
 
+    @property
+    def project(self) -> type___Project: ...
+
     def __init__(self,
         *,
         experimentID : typing___Optional[typing___Text] = None,
+        project : typing___Optional[type___Project] = None,
         ) -> None: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"experimentID",b"experimentID"]) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"experimentID",b"experimentID",u"project",b"project"]) -> None: ...
 type___StopExperimentRequest = StopExperimentRequest
 
 class StopExperimentReply(google___protobuf___message___Message):
@@ -159,11 +185,16 @@ class GetExperimentRequest(google___protobuf___message___Message):
     DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
     experimentIDPrefix: typing___Text = ...
 
+    @property
+    def project(self) -> type___Project: ...
+
     def __init__(self,
         *,
         experimentIDPrefix : typing___Optional[typing___Text] = None,
+        project : typing___Optional[type___Project] = None,
         ) -> None: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"experimentIDPrefix",b"experimentIDPrefix"]) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"experimentIDPrefix",b"experimentIDPrefix",u"project",b"project"]) -> None: ...
 type___GetExperimentRequest = GetExperimentRequest
 
 class GetExperimentReply(google___protobuf___message___Message):
@@ -182,13 +213,34 @@ type___GetExperimentReply = GetExperimentReply
 
 class ListExperimentsRequest(google___protobuf___message___Message):
     DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    filters: google___protobuf___internal___containers___RepeatedScalarFieldContainer[typing___Text] = ...
+    sort: typing___Text = ...
+    pageSize: builtin___int = ...
+    pageToken: typing___Text = ...
+
+    @property
+    def project(self) -> type___Project: ...
+
+    @property
+    def fieldMask(self) -> google___protobuf___field_mask_pb2___FieldMask: ...
 
     def __init__(self,
+        *,
+        project : typing___Optional[type___Project] = None,
+        filters : typing___Optional[typing___Iterable[typing___Text]] = None,
+        sort : typing___Optional[typing___Text] = None,
+        pageSize : typing___Optional[builtin___int] = None,
+        pageToken : typing___Optional[typing___Text] = None,
+        fieldMask : typing___Optional[google___protobuf___field_mask_pb2___FieldMask] = None,
         ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"fieldMask",b"fieldMask",u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"fieldMask",b"fieldMask",u"filters",b"filters",u"pageSize",b"pageSize",u"pageToken",b"pageToken",u"project",b"project",u"sort",b"sort"]) -> None: ...
 type___ListExperimentsRequest = ListExperimentsRequest
 
 class ListExperimentsReply(google___protobuf___message___Message):
     DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    nextPageToken: typing___Text = ...
+    totalSize: builtin___int = ...
 
     @property
     def experiments(self) -> google___protobuf___internal___containers___RepeatedCompositeFieldContainer[type___Experiment]: ...
@@ -196,19 +248,26 @@ class ListExperimentsReply(google___protobuf___message___Message):
     def __init__(self,
         *,
         experiments : typing___Optional[typing___Iterable[type___Experiment]] = None,
+        nextPageToken : typing___Optional[typing___Text] = None,
+        totalSize : typing___Optional[builtin___int] = None,
         ) -> None: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"experiments",b"experiments"]) -> None: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"experiments",b"experiments",u"nextPageToken",b"nextPageToken",u"totalSize",b"totalSize"]) -> None: ...
 type___ListExperimentsReply = ListExperimentsReply
 
 class DeleteExperimentRequest(google___protobuf___message___Message):
     DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
     experimentID: typing___Text = ...
 
+    @property
+    def project(self) -> type___Project: ...
+
     def __init__(self,
         *,
         experimentID : typing___Optional[typing___Text] = None,
+        project : typing___Optional[type___Project] = None,
         ) -> None: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"experimentID",b"experimentID"]) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"experimentID",b"experimentID",u"project",b"project"]) -> None: ...
 type___DeleteExperimentRequest = DeleteExperimentRequest
 
 class DeleteExperimentReply(google___protobuf___message___Message):
@@ -224,13 +283,18 @@ class CheckoutCheckpointRequest(google___protobuf___message___Message):
     outputDirectory: typing___Text = ...
     quiet: builtin___bool = ...
 
+    @property
+    def project(self) -> type___Project: ...
+
     def __init__(self,
         *,
         checkpointIDPrefix : typing___Optional[typing___Text] = None,
         outputDirectory : typing___Optional[typing___Text] = None,
         quiet : typing___Optional[builtin___bool] = None,
+        project : typing___Optional[type___Project] = None,
         ) -> None: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"checkpointIDPrefix",b"checkpointIDPrefix",u"outputDirectory",b"outputDirectory",u"quiet",b"quiet"]) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"checkpointIDPrefix",b"checkpointIDPrefix",u"outputDirectory",b"outputDirectory",u"project",b"project",u"quiet",b"quiet"]) -> None: ...
 type___CheckoutCheckpointRequest = CheckoutCheckpointRequest
 
 class CheckoutCheckpointReply(google___protobuf___message___Message):
@@ -244,11 +308,16 @@ class GetExperimentStatusRequest(google___protobuf___message___Message):
     DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
     experimentID: typing___Text = ...
 
+    @property
+    def project(self) -> type___Project: ...
+
     def __init__(self,
         *,
         experimentID : typing___Optional[typing___Text] = None,
+        project : typing___Optional[type___Project] = None,
         ) -> None: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"experimentID",b"experimentID"]) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"experimentID",b"experimentID",u"project",b"project"]) -> None: ...
 type___GetExperimentStatusRequest = GetExperimentStatusRequest
 
 class GetExperimentStatusReply(google___protobuf___message___Message):
@@ -273,6 +342,346 @@ class GetExperimentStatusReply(google___protobuf___message___Message):
     def ClearField(self, field_name: typing_extensions___Literal[u"status",b"status"]) -> None: ...
 type___GetExperimentStatusReply = GetExperimentStatusReply
 
+class LogMetricsRequest(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    experimentID: typing___Text = ...
+
+    @property
+    def points(self) -> google___protobuf___internal___containers___RepeatedCompositeFieldContainer[type___MetricPoint]: ...
+
+    @property
+    def project(self) -> type___Project: ...
+
+    def __init__(self,
+        *,
+        experimentID : typing___Optional[typing___Text] = None,
+        points : typing___Optional[typing___Iterable[type___MetricPoint]] = None,
+        project : typing___Optional[type___Project] = None,
+        ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"experimentID",b"experimentID",u"points",b"points",u"project",b"project"]) -> None: ...
+type___LogMetricsRequest = LogMetricsRequest
+
+class LogMetricsReply(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+
+    def __init__(self,
+        ) -> None: ...
+type___LogMetricsReply = LogMetricsReply
+
+class MetricPoint(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    class MetricsEntry(google___protobuf___message___Message):
+        DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+        key: typing___Text = ...
+
+        @property
+        def value(self) -> type___ParamType: ...
+
+        def __init__(self,
+            *,
+            key : typing___Optional[typing___Text] = None,
+            value : typing___Optional[type___ParamType] = None,
+            ) -> None: ...
+        def HasField(self, field_name: typing_extensions___Literal[u"value",b"value"]) -> builtin___bool: ...
+        def ClearField(self, field_name: typing_extensions___Literal[u"key",b"key",u"value",b"value"]) -> None: ...
+    type___MetricsEntry = MetricsEntry
+
+    step: builtin___int = ...
+
+    @property
+    def metrics(self) -> typing___MutableMapping[typing___Text, type___ParamType]: ...
+
+    def __init__(self,
+        *,
+        step : typing___Optional[builtin___int] = None,
+        metrics : typing___Optional[typing___Mapping[typing___Text, type___ParamType]] = None,
+        ) -> None: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"metrics",b"metrics",u"step",b"step"]) -> None: ...
+type___MetricPoint = MetricPoint
+
+class WatchExperimentsRequest(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    filters: google___protobuf___internal___containers___RepeatedScalarFieldContainer[typing___Text] = ...
+    includeExisting: builtin___bool = ...
+
+    @property
+    def project(self) -> type___Project: ...
+
+    @property
+    def pollInterval(self) -> google___protobuf___duration_pb2___Duration: ...
+
+    def __init__(self,
+        *,
+        project : typing___Optional[type___Project] = None,
+        filters : typing___Optional[typing___Iterable[typing___Text]] = None,
+        includeExisting : typing___Optional[builtin___bool] = None,
+        pollInterval : typing___Optional[google___protobuf___duration_pb2___Duration] = None,
+        ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"pollInterval",b"pollInterval",u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"filters",b"filters",u"includeExisting",b"includeExisting",u"pollInterval",b"pollInterval",u"project",b"project"]) -> None: ...
+type___WatchExperimentsRequest = WatchExperimentsRequest
+
+class ExperimentEvent(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    TypeValue = typing___NewType('TypeValue', builtin___int)
+    type___TypeValue = TypeValue
+    Type: _Type
+    class _Type(google___protobuf___internal___enum_type_wrapper____EnumTypeWrapper[ExperimentEvent.TypeValue]):
+        DESCRIPTOR: google___protobuf___descriptor___EnumDescriptor = ...
+        CREATED = typing___cast(ExperimentEvent.TypeValue, 0)
+        UPDATED = typing___cast(ExperimentEvent.TypeValue, 1)
+        CHECKPOINT_ADDED = typing___cast(ExperimentEvent.TypeValue, 2)
+        STOPPED = typing___cast(ExperimentEvent.TypeValue, 3)
+    CREATED = typing___cast(ExperimentEvent.TypeValue, 0)
+    UPDATED = typing___cast(ExperimentEvent.TypeValue, 1)
+    CHECKPOINT_ADDED = typing___cast(ExperimentEvent.TypeValue, 2)
+    STOPPED = typing___cast(ExperimentEvent.TypeValue, 3)
+    type___Type = Type
+
+    type: type___ExperimentEvent.TypeValue = ...
+    running: builtin___bool = ...
+
+    @property
+    def experiment(self) -> type___Experiment: ...
+
+    @property
+    def checkpoint(self) -> type___Checkpoint: ...
+
+    def __init__(self,
+        *,
+        type : typing___Optional[type___ExperimentEvent.TypeValue] = None,
+        experiment : typing___Optional[type___Experiment] = None,
+        checkpoint : typing___Optional[type___Checkpoint] = None,
+        running : typing___Optional[builtin___bool] = None,
+        ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"checkpoint",b"checkpoint",u"experiment",b"experiment"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"checkpoint",b"checkpoint",u"experiment",b"experiment",u"running",b"running",u"type",b"type"]) -> None: ...
+type___ExperimentEvent = ExperimentEvent
+
+class GetCheckpointRequest(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    checkpointIDPrefix: typing___Text = ...
+
+    @property
+    def project(self) -> type___Project: ...
+
+    def __init__(self,
+        *,
+        checkpointIDPrefix : typing___Optional[typing___Text] = None,
+        project : typing___Optional[type___Project] = None,
+        ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"checkpointIDPrefix",b"checkpointIDPrefix",u"project",b"project"]) -> None: ...
+type___GetCheckpointRequest = GetCheckpointRequest
+
+class GetCheckpointReply(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+
+    @property
+    def checkpoint(self) -> type___Checkpoint: ...
+
+    @property
+    def experiment(self) -> type___Experiment: ...
+
+    def __init__(self,
+        *,
+        checkpoint : typing___Optional[type___Checkpoint] = None,
+        experiment : typing___Optional[type___Experiment] = None,
+        ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"checkpoint",b"checkpoint",u"experiment",b"experiment"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"checkpoint",b"checkpoint",u"experiment",b"experiment"]) -> None: ...
+type___GetCheckpointReply = GetCheckpointReply
+
+class DeleteCheckpointRequest(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    checkpointID: typing___Text = ...
+
+    @property
+    def project(self) -> type___Project: ...
+
+    def __init__(self,
+        *,
+        checkpointID : typing___Optional[typing___Text] = None,
+        project : typing___Optional[type___Project] = None,
+        ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"checkpointID",b"checkpointID",u"project",b"project"]) -> None: ...
+type___DeleteCheckpointRequest = DeleteCheckpointRequest
+
+class DeleteCheckpointReply(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+
+    def __init__(self,
+        ) -> None: ...
+type___DeleteCheckpointReply = DeleteCheckpointReply
+
+class ListCheckpointFilesRequest(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    checkpointIDPrefix: typing___Text = ...
+
+    @property
+    def project(self) -> type___Project: ...
+
+    def __init__(self,
+        *,
+        checkpointIDPrefix : typing___Optional[typing___Text] = None,
+        project : typing___Optional[type___Project] = None,
+        ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"checkpointIDPrefix",b"checkpointIDPrefix",u"project",b"project"]) -> None: ...
+type___ListCheckpointFilesRequest = ListCheckpointFilesRequest
+
+class ListCheckpointFilesReply(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    paths: google___protobuf___internal___containers___RepeatedScalarFieldContainer[typing___Text] = ...
+
+    def __init__(self,
+        *,
+        paths : typing___Optional[typing___Iterable[typing___Text]] = None,
+        ) -> None: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"paths",b"paths"]) -> None: ...
+type___ListCheckpointFilesReply = ListCheckpointFilesReply
+
+class CheckoutPathRequest(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    idPrefix: typing___Text = ...
+    path: typing___Text = ...
+    outputDirectory: typing___Text = ...
+    quiet: builtin___bool = ...
+
+    @property
+    def project(self) -> type___Project: ...
+
+    def __init__(self,
+        *,
+        idPrefix : typing___Optional[typing___Text] = None,
+        path : typing___Optional[typing___Text] = None,
+        outputDirectory : typing___Optional[typing___Text] = None,
+        quiet : typing___Optional[builtin___bool] = None,
+        project : typing___Optional[type___Project] = None,
+        ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"idPrefix",b"idPrefix",u"outputDirectory",b"outputDirectory",u"path",b"path",u"project",b"project",u"quiet",b"quiet"]) -> None: ...
+type___CheckoutPathRequest = CheckoutPathRequest
+
+class CheckoutPathReply(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+
+    def __init__(self,
+        ) -> None: ...
+type___CheckoutPathReply = CheckoutPathReply
+
+class DiffCheckpointsRequest(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    leftIDPrefix: typing___Text = ...
+    rightIDPrefix: typing___Text = ...
+
+    @property
+    def project(self) -> type___Project: ...
+
+    def __init__(self,
+        *,
+        leftIDPrefix : typing___Optional[typing___Text] = None,
+        rightIDPrefix : typing___Optional[typing___Text] = None,
+        project : typing___Optional[type___Project] = None,
+        ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"project",b"project"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"leftIDPrefix",b"leftIDPrefix",u"project",b"project",u"rightIDPrefix",b"rightIDPrefix"]) -> None: ...
+type___DiffCheckpointsRequest = DiffCheckpointsRequest
+
+class DiffCheckpointsReply(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    leftExperimentID: typing___Text = ...
+    rightExperimentID: typing___Text = ...
+
+    @property
+    def left(self) -> type___Checkpoint: ...
+
+    @property
+    def right(self) -> type___Checkpoint: ...
+
+    @property
+    def sections(self) -> google___protobuf___internal___containers___RepeatedCompositeFieldContainer[type___DiffSection]: ...
+
+    def __init__(self,
+        *,
+        left : typing___Optional[type___Checkpoint] = None,
+        right : typing___Optional[type___Checkpoint] = None,
+        leftExperimentID : typing___Optional[typing___Text] = None,
+        rightExperimentID : typing___Optional[typing___Text] = None,
+        sections : typing___Optional[typing___Iterable[type___DiffSection]] = None,
+        ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"left",b"left",u"right",b"right"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"left",b"left",u"leftExperimentID",b"leftExperimentID",u"right",b"right",u"rightExperimentID",b"rightExperimentID",u"sections",b"sections"]) -> None: ...
+type___DiffCheckpointsReply = DiffCheckpointsReply
+
+class DiffSection(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    name: typing___Text = ...
+
+    @property
+    def differences(self) -> google___protobuf___internal___containers___RepeatedCompositeFieldContainer[type___Difference]: ...
+
+    def __init__(self,
+        *,
+        name : typing___Optional[typing___Text] = None,
+        differences : typing___Optional[typing___Iterable[type___Difference]] = None,
+        ) -> None: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"differences",b"differences",u"name",b"name"]) -> None: ...
+type___DiffSection = DiffSection
+
+class Difference(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    key: typing___Text = ...
+
+    @property
+    def left(self) -> type___ParamType: ...
+
+    @property
+    def right(self) -> type___ParamType: ...
+
+    def __init__(self,
+        *,
+        key : typing___Optional[typing___Text] = None,
+        left : typing___Optional[type___ParamType] = None,
+        right : typing___Optional[type___ParamType] = None,
+        ) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"left",b"left",u"right",b"right"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"key",b"key",u"left",b"left",u"right",b"right"]) -> None: ...
+type___Difference = Difference
+
+class DrainRequest(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+
+    def __init__(self,
+        ) -> None: ...
+type___DrainRequest = DrainRequest
+
+class DrainReply(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    pendingWork: google___protobuf___internal___containers___RepeatedScalarFieldContainer[typing___Text] = ...
+
+    def __init__(self,
+        *,
+        pendingWork : typing___Optional[typing___Iterable[typing___Text]] = None,
+        ) -> None: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"pendingWork",b"pendingWork"]) -> None: ...
+type___DrainReply = DrainReply
+
+class Project(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    repositoryURL: typing___Text = ...
+    directory: typing___Text = ...
+
+    def __init__(self,
+        *,
+        repositoryURL : typing___Optional[typing___Text] = None,
+        directory : typing___Optional[typing___Text] = None,
+        ) -> None: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"directory",b"directory",u"repositoryURL",b"repositoryURL"]) -> None: ...
+type___Project = Project
+
 class Experiment(google___protobuf___message___Message):
     DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
     class ParamsEntry(google___protobuf___message___Message):
@@ -59,6 +59,46 @@ class DaemonStub(object):
                 request_serializer=keepsake__pb2.GetExperimentStatusRequest.SerializeToString,
                 response_deserializer=keepsake__pb2.GetExperimentStatusReply.FromString,
                 )
+        self.LogMetrics = channel.unary_unary(
+                '/service.Daemon/LogMetrics',
+                request_serializer=keepsake__pb2.LogMetricsRequest.SerializeToString,
+                response_deserializer=keepsake__pb2.LogMetricsReply.FromString,
+                )
+        self.WatchExperiments = channel.unary_stream(
+                '/service.Daemon/WatchExperiments',
+                request_serializer=keepsake__pb2.WatchExperimentsRequest.SerializeToString,
+                response_deserializer=keepsake__pb2.ExperimentEvent.FromString,
+                )
+        self.GetCheckpoint = channel.unary_unary(
+                '/service.Daemon/GetCheckpoint',
+                request_serializer=keepsake__pb2.GetCheckpointRequest.SerializeToString,
+                response_deserializer=keepsake__pb2.GetCheckpointReply.FromString,
+                )
+        self.DeleteCheckpoint = channel.unary_unary(
+                '/service.Daemon/DeleteCheckpoint',
+                request_serializer=keepsake__pb2.DeleteCheckpointRequest.SerializeToString,
+                response_deserializer=keepsake__pb2.DeleteCheckpointReply.FromString,
+                )
+        self.ListCheckpointFiles = channel.unary_unary(
+                '/service.Daemon/ListCheckpointFiles',
+                request_serializer=keepsake__pb2.ListCheckpointFilesRequest.SerializeToString,
+                response_deserializer=keepsake__pb2.ListCheckpointFilesReply.FromString,
+                )
+        self.CheckoutPath = channel.unary_unary(
+                '/service.Daemon/CheckoutPath',
+                request_serializer=keepsake__pb2.CheckoutPathRequest.SerializeToString,
+                response_deserializer=keepsake__pb2.CheckoutPathReply.FromString,
+                )
+        self.DiffCheckpoints = channel.unary_unary(
+                '/service.Daemon/DiffCheckpoints',
+                request_serializer=keepsake__pb2.DiffCheckpointsRequest.SerializeToString,
+                response_deserializer=keepsake__pb2.DiffCheckpointsReply.FromString,
+                )
+        self.Drain = channel.unary_unary(
+                '/service.Daemon/Drain',
+                request_serializer=keepsake__pb2.DrainRequest.SerializeToString,
+                response_deserializer=keepsake__pb2.DrainReply.FromString,
+                )
 
 
 class DaemonServicer(object):
@@ -118,6 +158,54 @@ class DaemonServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def LogMetrics(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def WatchExperiments(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetCheckpoint(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def DeleteCheckpoint(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ListCheckpointFiles(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CheckoutPath(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def DiffCheckpoints(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Drain(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_DaemonServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -166,6 +254,46 @@ def add_DaemonServicer_to_server(servicer, server):
                     request_deserializer=keepsake__pb2.GetExperimentStatusRequest.FromString,
                     response_serializer=keepsake__pb2.GetExperimentStatusReply.SerializeToString,
             ),
+            'LogMetrics': grpc.unary_unary_rpc_method_handler(
+                    servicer.LogMetrics,
+                    request_deserializer=keepsake__pb2.LogMetricsRequest.FromString,
+                    response_serializer=keepsake__pb2.LogMetricsReply.SerializeToString,
+            ),
+            'WatchExperiments': grpc.unary_stream_rpc_method_handler(
+                    servicer.WatchExperiments,
+                    request_deserializer=keepsake__pb2.WatchExperimentsRequest.FromString,
+                    response_serializer=keepsake__pb2.ExperimentEvent.SerializeToString,
+            ),
+            'GetCheckpoint': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetCheckpoint,
+                    request_deserializer=keepsake__pb2.GetCheckpointRequest.FromString,
+                    response_serializer=keepsake__pb2.GetCheckpointReply.SerializeToString,
+            ),
+            'DeleteCheckpoint': grpc.unary_unary_rpc_method_handler(
+                    servicer.DeleteCheckpoint,
+                    request_deserializer=keepsake__pb2.DeleteCheckpointRequest.FromString,
+                    response_serializer=keepsake__pb2.DeleteCheckpointReply.SerializeToString,
+            ),
+            'ListCheckpointFiles': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListCheckpointFiles,
+                    request_deserializer=keepsake__pb2.ListCheckpointFilesRequest.FromString,
+                    response_serializer=keepsake__pb2.ListCheckpointFilesReply.SerializeToString,
+            ),
+            'CheckoutPath': grpc.unary_unary_rpc_method_handler(
+                    servicer.CheckoutPath,
+                    request_deserializer=keepsake__pb2.CheckoutPathRequest.FromString,
+                    response_serializer=keepsake__pb2.CheckoutPathReply.SerializeToString,
+            ),
+            'DiffCheckpoints': grpc.unary_unary_rpc_method_handler(
+                    servicer.DiffCheckpoints,
+                    request_deserializer=keepsake__pb2.DiffCheckpointsRequest.FromString,
+                    response_serializer=keepsake__pb2.DiffCheckpointsReply.SerializeToString,
+            ),
+            'Drain': grpc.unary_unary_rpc_method_handler(
+                    servicer.Drain,
+                    request_deserializer=keepsake__pb2.DrainRequest.FromString,
+                    response_serializer=keepsake__pb2.DrainReply.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'service.Daemon', rpc_method_handlers)
@@ -328,3 +456,139 @@ class Daemon(object):
             keepsake__pb2.GetExperimentStatusReply.FromString,
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def LogMetrics(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/service.Daemon/LogMetrics',
+            keepsake__pb2.LogMetricsRequest.SerializeToString,
+            keepsake__pb2.LogMetricsReply.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def WatchExperiments(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_stream(request, target, '/service.Daemon/WatchExperiments',
+            keepsake__pb2.WatchExperimentsRequest.SerializeToString,
+            keepsake__pb2.ExperimentEvent.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetCheckpoint(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/service.Daemon/GetCheckpoint',
+            keepsake__pb2.GetCheckpointRequest.SerializeToString,
+            keepsake__pb2.GetCheckpointReply.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def DeleteCheckpoint(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/service.Daemon/DeleteCheckpoint',
+            keepsake__pb2.DeleteCheckpointRequest.SerializeToString,
+            keepsake__pb2.DeleteCheckpointReply.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ListCheckpointFiles(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/service.Daemon/ListCheckpointFiles',
+            keepsake__pb2.ListCheckpointFilesRequest.SerializeToString,
+            keepsake__pb2.ListCheckpointFilesReply.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def CheckoutPath(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/service.Daemon/CheckoutPath',
+            keepsake__pb2.CheckoutPathRequest.SerializeToString,
+            keepsake__pb2.CheckoutPathReply.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def DiffCheckpoints(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/service.Daemon/DiffCheckpoints',
+            keepsake__pb2.DiffCheckpointsRequest.SerializeToString,
+            keepsake__pb2.DiffCheckpointsReply.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def Drain(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/service.Daemon/Drain',
+            keepsake__pb2.DrainRequest.SerializeToString,
+            keepsake__pb2.DrainReply.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)